@@ -1,25 +1,163 @@
 package main
 
 import (
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	connectorSchema "github.com/conductorone/baton-atlassian/pkg/connector"
 	"github.com/conductorone/baton-sdk/pkg/field"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
 	"github.com/spf13/viper"
 )
 
 var (
 	userEmailField = field.StringField(
 		"user-email",
-		field.WithRequired(true),
-		field.WithDescription("User email used to authenticate to Atlassian API"),
+		field.WithDescription("User email used to authenticate to Atlassian API with Basic auth"),
 	)
 	apiTokenField = field.StringField(
 		"api-token",
-		field.WithRequired(true),
-		field.WithDescription("The API token to get access to Atlassian API."),
+		field.WithDescription("The API token to get access to Atlassian API with Basic auth."),
+	)
+	oauthClientIDField = field.StringField(
+		"oauth-client-id",
+		field.WithDescription("The OAuth 2.0 client ID of an Atlassian service account or OAuth 2.0 (3LO) app."),
+	)
+	oauthClientSecretField = field.StringField(
+		"oauth-client-secret",
+		field.WithDescription("The OAuth 2.0 client secret of an Atlassian service account or OAuth 2.0 (3LO) app."),
+	)
+	oauthRefreshTokenField = field.StringField(
+		"oauth-refresh-token",
+		field.WithDescription("The OAuth 2.0 (3LO) refresh token. When omitted, the client credentials grant is used."),
+	)
+	adminAPIKeyField = field.StringField(
+		"admin-api-key",
+		field.WithDescription("The Atlassian Admin API key used to sync and provision organization groups and product access."),
+	)
+	productAccessModeField = field.StringField(
+		"product-access-mode",
+		field.WithDescription("How product user access is granted: 'role-assignment' assigns product roles, 'default-group' manages the product's default access group."),
+		field.WithDefaultValue(connectorSchema.ProductAccessModeRoleAssignment),
+	)
+	graphqlURLField = field.StringField(
+		"graphql-url",
+		field.WithDescription("Override the Atlassian GraphQL gateway URL, e.g. for Atlassian Government Cloud, an egress proxy or a local mock."),
+	)
+	adminAPIURLField = field.StringField(
+		"admin-api-url",
+		field.WithDescription("Override the Atlassian Admin API base URL."),
+	)
+	userManagementAPIURLField = field.StringField(
+		"user-management-api-url",
+		field.WithDescription("Override the Atlassian user management API base URL, used to list and revoke the API tokens of managed accounts."),
+	)
+	oauthTokenURLField = field.StringField(
+		"oauth-token-url",
+		field.WithDescription("Override the OAuth 2.0 token endpoint used to obtain access tokens."),
+	)
+	concurrencyField = field.IntField(
+		"concurrency",
+		field.WithDescription("The maximum number of teams whose member pages are fetched in parallel."),
+		field.WithDefaultValue(client.DefaultConcurrency),
+	)
+	graphqlCostBudgetField = field.IntField(
+		"graphql-cost-budget",
+		field.WithDescription("The maximum total GraphQL query cost a sync may spend. 0 means no limit."),
+		field.WithDefaultValue(0),
+	)
+	responseCacheBackendField = field.StringField(
+		"response-cache-backend",
+		field.WithDescription("Cache Admin API GET responses between syncs: 'memory' keeps them in memory, 'db' stores them on disk, in place of the SDK response cache. Empty disables the cache."),
+	)
+	responseCacheTTLField = field.IntField(
+		"response-cache-ttl",
+		field.WithDescription("The number of seconds a cached Admin API response is reused."),
+		field.WithDefaultValue(3600),
+	)
+	responseCacheMaxSizeField = field.IntField(
+		"response-cache-max-size",
+		field.WithDescription("The maximum size in megabytes of the in-memory response cache."),
+		field.WithDefaultValue(5),
+	)
+	incrementalSyncField = field.BoolField(
+		"incremental-sync",
+		field.WithDescription("Skip the group and product role grants the Admin API audit log reports as unchanged since the previous sync. Team grants are always listed."),
+	)
+	incrementalSyncMaxAgeField = field.IntField(
+		"incremental-sync-max-age",
+		field.WithDescription("The age in hours after which the previous sync is ignored and an incremental sync lists every grant."),
+		field.WithDefaultValue(168),
+	)
+	exportUsersCSVField = field.StringField(
+		"export-users-csv",
+		field.WithDescription("Sync offline from the users CSV exported from admin.atlassian.com instead of calling the Atlassian APIs."),
+	)
+	exportTeamsJSONField = field.StringField(
+		"export-teams-json",
+		field.WithDescription("Sync offline from a JSON export of the teams and their members instead of calling the Atlassian APIs."),
+	)
+	dataCenterProductField = field.StringField(
+		"data-center-product",
+		field.WithDescription("Sync a self-hosted Data Center or Server instance of this product instead of Atlassian cloud: 'jira', 'confluence', 'bitbucket' or 'crowd'."),
+	)
+	dataCenterURLField = field.StringField(
+		"data-center-url",
+		field.WithDescription("The base URL of the Data Center instance, e.g. https://jira.example.com."),
+	)
+	dataCenterTokenField = field.StringField(
+		"data-center-token",
+		field.WithDescription("The personal access token used to authenticate to the Data Center instance."),
+	)
+	crowdApplicationNameField = field.StringField(
+		"crowd-application-name",
+		field.WithDescription("The name of the Crowd application used to authenticate to a Crowd server instead of a personal access token."),
+	)
+	crowdApplicationPasswordField = field.StringField(
+		"crowd-application-password",
+		field.WithDescription("The password of the Crowd application."),
+	)
+	opsgenieAPIKeyField = field.StringField(
+		"opsgenie-api-key",
+		field.WithDescription("Sync an Opsgenie account with this API key instead of Atlassian cloud."),
+	)
+	opsgenieAPIURLField = field.StringField(
+		"opsgenie-api-url",
+		field.WithDescription("Override the Opsgenie API URL, e.g. https://api.eu.opsgenie.com for accounts in the EU region."),
+	)
+	trelloAPIKeyField = field.StringField(
+		"trello-api-key",
+		field.WithDescription("Sync a Trello Enterprise with this Trello API key instead of Atlassian cloud."),
+	)
+	trelloAPITokenField = field.StringField(
+		"trello-api-token",
+		field.WithDescription("The Trello token of an enterprise admin authorizing the API key."),
+	)
+	trelloEnterpriseIDField = field.StringField(
+		"trello-enterprise-id",
+		field.WithDescription("The ID of the Trello Enterprise to sync."),
+	)
+	trelloAPIURLField = field.StringField(
+		"trello-api-url",
+		field.WithDescription("Override the Trello API URL, e.g. for an egress proxy or a local mock."),
+	)
+	statuspageAPIKeyField = field.StringField(
+		"statuspage-api-key",
+		field.WithDescription("Sync the pages of a Statuspage organization with this API key instead of Atlassian cloud."),
+	)
+	statuspageOrganizationIDField = field.StringField(
+		"statuspage-organization-id",
+		field.WithDescription("The ID of the Statuspage organization whose team members are synced."),
+	)
+	statuspageAPIURLField = field.StringField(
+		"statuspage-api-url",
+		field.WithDescription("Override the Statuspage API URL, e.g. for an egress proxy or a local mock."),
 	)
 	organizationField = field.StringField(
 		"organization",
-		field.WithDescription("Limit syncing to specific organization by providing organization ID."),
-		field.WithRequired(true),
+		field.WithDescription("Limit syncing to specific organization by providing organization ID. Required unless syncing a Data Center instance, Opsgenie, Trello or Statuspage."),
 	)
 	siteIdField = field.StringField(
 		"site-id",
@@ -30,13 +168,67 @@ var (
 	// ConfigurationFields defines the external configuration required for the
 	// connector to run. Note: these fields can be marked as optional or
 	// required.
-	ConfigurationFields = []field.SchemaField{userEmailField, apiTokenField, organizationField, siteIdField}
+	ConfigurationFields = []field.SchemaField{
+		userEmailField,
+		apiTokenField,
+		oauthClientIDField,
+		oauthClientSecretField,
+		oauthRefreshTokenField,
+		adminAPIKeyField,
+		productAccessModeField,
+		graphqlURLField,
+		adminAPIURLField,
+		userManagementAPIURLField,
+		oauthTokenURLField,
+		graphqlCostBudgetField,
+		concurrencyField,
+		responseCacheBackendField,
+		responseCacheTTLField,
+		responseCacheMaxSizeField,
+		incrementalSyncField,
+		incrementalSyncMaxAgeField,
+		exportUsersCSVField,
+		exportTeamsJSONField,
+		dataCenterProductField,
+		dataCenterURLField,
+		dataCenterTokenField,
+		crowdApplicationNameField,
+		crowdApplicationPasswordField,
+		opsgenieAPIKeyField,
+		opsgenieAPIURLField,
+		trelloAPIKeyField,
+		trelloAPITokenField,
+		trelloEnterpriseIDField,
+		trelloAPIURLField,
+		statuspageAPIKeyField,
+		statuspageOrganizationIDField,
+		statuspageAPIURLField,
+		organizationField,
+		siteIdField,
+	}
 
 	// FieldRelationships defines relationships between the fields listed in
 	// ConfigurationFields that can be automatically validated. For example, a
 	// username and password can be required together, or an access token can be
 	// marked as mutually exclusive from the username password pair.
-	FieldRelationships = []field.SchemaFieldRelationship{}
+	FieldRelationships = []field.SchemaFieldRelationship{
+		field.FieldsRequiredTogether(userEmailField, apiTokenField),
+		field.FieldsRequiredTogether(oauthClientIDField, oauthClientSecretField),
+		field.FieldsDependentOn([]field.SchemaField{oauthRefreshTokenField}, []field.SchemaField{oauthClientIDField}),
+		field.FieldsRequiredTogether(dataCenterProductField, dataCenterURLField),
+		field.FieldsRequiredTogether(crowdApplicationNameField, crowdApplicationPasswordField),
+		field.FieldsMutuallyExclusive(dataCenterTokenField, crowdApplicationNameField),
+		field.FieldsDependentOn([]field.SchemaField{dataCenterTokenField, crowdApplicationNameField}, []field.SchemaField{dataCenterURLField}),
+		field.FieldsMutuallyExclusive(userEmailField, oauthClientIDField, exportUsersCSVField, dataCenterURLField, opsgenieAPIKeyField, trelloAPIKeyField, statuspageAPIKeyField),
+		field.FieldsMutuallyExclusive(userEmailField, oauthClientIDField, exportTeamsJSONField, dataCenterURLField, opsgenieAPIKeyField, trelloAPIKeyField, statuspageAPIKeyField),
+		field.FieldsAtLeastOneUsed(userEmailField, oauthClientIDField, exportUsersCSVField, exportTeamsJSONField, dataCenterURLField, opsgenieAPIKeyField, trelloAPIKeyField, statuspageAPIKeyField),
+		field.FieldsDependentOn([]field.SchemaField{opsgenieAPIURLField}, []field.SchemaField{opsgenieAPIKeyField}),
+		field.FieldsRequiredTogether(trelloAPIKeyField, trelloAPITokenField, trelloEnterpriseIDField),
+		field.FieldsDependentOn([]field.SchemaField{trelloAPIURLField}, []field.SchemaField{trelloAPIKeyField}),
+		field.FieldsRequiredTogether(statuspageAPIKeyField, statuspageOrganizationIDField),
+		field.FieldsDependentOn([]field.SchemaField{statuspageAPIURLField}, []field.SchemaField{statuspageAPIKeyField}),
+		field.FieldsDependentOn([]field.SchemaField{incrementalSyncField}, []field.SchemaField{adminAPIKeyField}),
+	}
 )
 
 // ValidateConfig is run after the configuration is loaded, and should return an
@@ -44,5 +236,73 @@ var (
 // needs to perform extra validations that cannot be encoded with configuration
 // parameters.
 func ValidateConfig(v *viper.Viper) error {
+	if v.GetString(dataCenterURLField.FieldName) != "" {
+		product := v.GetString(dataCenterProductField.FieldName)
+		if !slices.Contains(connectorSchema.DataCenterProducts, product) {
+			return fmt.Errorf("invalid %s %q, must be one of %q", dataCenterProductField.FieldName, product, connectorSchema.DataCenterProducts)
+		}
+		if product == connectorSchema.DataCenterProductCrowd && v.GetString(crowdApplicationNameField.FieldName) == "" {
+			return fmt.Errorf("%s is required to sync a Crowd server", crowdApplicationNameField.FieldName)
+		}
+		if product != connectorSchema.DataCenterProductCrowd && v.GetString(dataCenterTokenField.FieldName) == "" {
+			return fmt.Errorf("%s is required to sync a Data Center instance", dataCenterTokenField.FieldName)
+		}
+	} else if v.GetString(organizationField.FieldName) == "" && v.GetString(opsgenieAPIKeyField.FieldName) == "" &&
+		v.GetString(trelloAPIKeyField.FieldName) == "" && v.GetString(statuspageAPIKeyField.FieldName) == "" {
+		return fmt.Errorf("%s is required unless syncing a Data Center instance, Opsgenie, Trello or Statuspage", organizationField.FieldName)
+	}
+
+	switch mode := v.GetString(productAccessModeField.FieldName); mode {
+	case "", connectorSchema.ProductAccessModeRoleAssignment, connectorSchema.ProductAccessModeDefaultGroup:
+	default:
+		return fmt.Errorf("invalid product access mode %q, must be one of %q or %q",
+			mode, connectorSchema.ProductAccessModeRoleAssignment, connectorSchema.ProductAccessModeDefaultGroup)
+	}
+
+	if concurrency := v.GetInt(concurrencyField.FieldName); concurrency < 0 {
+		return fmt.Errorf("invalid %s %d, must not be negative", concurrencyField.FieldName, concurrency)
+	}
+
+	if budget := v.GetInt(graphqlCostBudgetField.FieldName); budget < 0 {
+		return fmt.Errorf("invalid %s %d, must not be negative", graphqlCostBudgetField.FieldName, budget)
+	}
+
+	switch backend := uhttp.CacheBackend(v.GetString(responseCacheBackendField.FieldName)); backend {
+	case "", uhttp.CacheBackendMemory, uhttp.CacheBackendDB:
+	default:
+		return fmt.Errorf("invalid %s %q, must be one of %q or %q",
+			responseCacheBackendField.FieldName, backend, uhttp.CacheBackendMemory, uhttp.CacheBackendDB)
+	}
+
+	for _, intField := range []field.SchemaField{responseCacheTTLField, responseCacheMaxSizeField, incrementalSyncMaxAgeField} {
+		if value := v.GetInt(intField.FieldName); value < 0 {
+			return fmt.Errorf("invalid %s %d, must not be negative", intField.FieldName, value)
+		}
+	}
+
+	if v.GetBool(incrementalSyncField.FieldName) && v.IsSet(incrementalSyncMaxAgeField.FieldName) &&
+		v.GetInt(incrementalSyncMaxAgeField.FieldName) == 0 {
+		return fmt.Errorf("invalid %s 0, incremental syncs need a positive maximum age", incrementalSyncMaxAgeField.FieldName)
+	}
+
+	for _, urlField := range []field.SchemaField{graphqlURLField, adminAPIURLField, userManagementAPIURLField, oauthTokenURLField, dataCenterURLField, opsgenieAPIURLField, trelloAPIURLField, statuspageAPIURLField} {
+		if err := validateURL(urlField.FieldName, v.GetString(urlField.FieldName)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateURL(name, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return fmt.Errorf("invalid %s %q, must be an absolute http or https URL", name, value)
+	}
+
 	return nil
 }
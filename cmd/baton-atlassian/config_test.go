@@ -13,5 +13,382 @@ func TestConfigs(t *testing.T) {
 		FieldRelationships...,
 	)
 
-	test.ExerciseTestCases(t, configurationSchema, ValidateConfig, []test.TestCase{})
+	test.ExerciseTestCases(t, configurationSchema, ValidateConfig, []test.TestCase{
+		{
+			Configs: map[string]string{
+				"user-email":   "user@example.com",
+				"api-token":    "token",
+				"organization": "org",
+			},
+			IsValid: true,
+			Message: "basic auth",
+		},
+		{
+			Configs: map[string]string{
+				"user-email":          "user@example.com",
+				"api-token":           "token",
+				"organization":        "org",
+				"admin-api-key":       "admin-key",
+				"product-access-mode": "default-group",
+			},
+			IsValid: true,
+			Message: "default group product access",
+		},
+		{
+			Configs: map[string]string{
+				"user-email":          "user@example.com",
+				"api-token":           "token",
+				"organization":        "org",
+				"product-access-mode": "license",
+			},
+			IsValid: false,
+			Message: "invalid product access mode",
+		},
+		{
+			Configs: map[string]string{
+				"oauth-client-id":     "client-id",
+				"oauth-client-secret": "client-secret",
+				"organization":        "org",
+			},
+			IsValid: true,
+			Message: "service account client credentials",
+		},
+		{
+			Configs: map[string]string{
+				"oauth-client-id":     "client-id",
+				"oauth-client-secret": "client-secret",
+				"oauth-refresh-token": "refresh-token",
+				"organization":        "org",
+			},
+			IsValid: true,
+			Message: "oauth refresh token",
+		},
+		{
+			Configs: map[string]string{
+				"user-email":          "user@example.com",
+				"api-token":           "token",
+				"oauth-client-id":     "client-id",
+				"oauth-client-secret": "client-secret",
+				"organization":        "org",
+			},
+			IsValid: false,
+			Message: "basic auth and oauth are mutually exclusive",
+		},
+		{
+			Configs: map[string]string{
+				"user-email":   "user@example.com",
+				"organization": "org",
+			},
+			IsValid: false,
+			Message: "basic auth requires an api token",
+		},
+		{
+			Configs: map[string]string{
+				"oauth-refresh-token": "refresh-token",
+				"organization":        "org",
+			},
+			IsValid: false,
+			Message: "refresh token requires client credentials",
+		},
+		{
+			Configs: map[string]string{
+				"admin-api-key": "admin-key",
+				"organization":  "org",
+			},
+			IsValid: false,
+			Message: "missing gateway credentials",
+		},
+		{
+			Configs: map[string]string{
+				"user-email":    "user@example.com",
+				"api-token":     "token",
+				"organization":  "org",
+				"graphql-url":   "http://localhost:8080/graphql",
+				"admin-api-url": "https://api.atlassian-us-gov-mod.com/admin",
+			},
+			IsValid: true,
+			Message: "custom endpoints",
+		},
+		{
+			Configs: map[string]string{
+				"user-email":   "user@example.com",
+				"api-token":    "token",
+				"organization": "org",
+				"graphql-url":  "team.atlassian.com/gateway/api/graphql",
+			},
+			IsValid: false,
+			Message: "relative graphql url",
+		},
+		{
+			Configs: map[string]string{
+				"user-email":       "user@example.com",
+				"api-token":        "token",
+				"organization":     "org",
+				"admin-api-key":    "admin-key",
+				"incremental-sync": "true",
+			},
+			IsValid: true,
+			Message: "incremental sync",
+		},
+		{
+			Configs: map[string]string{
+				"user-email":       "user@example.com",
+				"api-token":        "token",
+				"organization":     "org",
+				"incremental-sync": "true",
+			},
+			IsValid: false,
+			Message: "incremental sync requires an admin api key",
+		},
+		{
+			Configs: map[string]string{
+				"export-users-csv":  "users.csv",
+				"export-teams-json": "teams.json",
+				"organization":      "org",
+			},
+			IsValid: true,
+			Message: "offline export",
+		},
+		{
+			Configs: map[string]string{
+				"user-email":       "user@example.com",
+				"api-token":        "token",
+				"export-users-csv": "users.csv",
+				"organization":     "org",
+			},
+			IsValid: false,
+			Message: "offline export and basic auth are mutually exclusive",
+		},
+		{
+			Configs: map[string]string{
+				"user-email": "user@example.com",
+				"api-token":  "token",
+			},
+			IsValid: false,
+			Message: "missing organization",
+		},
+		{
+			Configs: map[string]string{
+				"data-center-product": "jira",
+				"data-center-url":     "https://jira.example.com",
+				"data-center-token":   "pat",
+			},
+			IsValid: true,
+			Message: "jira data center",
+		},
+		{
+			Configs: map[string]string{
+				"data-center-product": "confluence",
+				"data-center-url":     "https://confluence.example.com",
+				"data-center-token":   "pat",
+			},
+			IsValid: true,
+			Message: "confluence data center",
+		},
+		{
+			Configs: map[string]string{
+				"data-center-product": "bitbucket",
+				"data-center-url":     "https://bitbucket.example.com",
+				"data-center-token":   "pat",
+			},
+			IsValid: true,
+			Message: "bitbucket data center",
+		},
+		{
+			Configs: map[string]string{
+				"data-center-product":        "crowd",
+				"data-center-url":            "https://crowd.example.com/crowd",
+				"crowd-application-name":     "baton",
+				"crowd-application-password": "secret",
+			},
+			IsValid: true,
+			Message: "crowd",
+		},
+		{
+			Configs: map[string]string{
+				"data-center-product": "crowd",
+				"data-center-url":     "https://crowd.example.com/crowd",
+				"data-center-token":   "pat",
+			},
+			IsValid: false,
+			Message: "crowd requires application credentials",
+		},
+		{
+			Configs: map[string]string{
+				"data-center-product":    "crowd",
+				"data-center-url":        "https://crowd.example.com/crowd",
+				"crowd-application-name": "baton",
+			},
+			IsValid: false,
+			Message: "crowd application requires a password",
+		},
+		{
+			Configs: map[string]string{
+				"data-center-product":        "jira",
+				"data-center-url":            "https://jira.example.com",
+				"crowd-application-name":     "baton",
+				"crowd-application-password": "secret",
+			},
+			IsValid: false,
+			Message: "jira data center requires a personal access token",
+		},
+		{
+			Configs: map[string]string{
+				"data-center-product": "jira",
+				"data-center-url":     "https://jira.example.com",
+			},
+			IsValid: false,
+			Message: "data center requires a personal access token",
+		},
+		{
+			Configs: map[string]string{
+				"data-center-product": "fisheye",
+				"data-center-url":     "https://fisheye.example.com",
+				"data-center-token":   "pat",
+			},
+			IsValid: false,
+			Message: "unsupported data center product",
+		},
+		{
+			Configs: map[string]string{
+				"user-email":          "user@example.com",
+				"api-token":           "token",
+				"organization":        "org",
+				"data-center-product": "jira",
+				"data-center-url":     "https://jira.example.com",
+				"data-center-token":   "pat",
+			},
+			IsValid: false,
+			Message: "data center and basic auth are mutually exclusive",
+		},
+		{
+			Configs: map[string]string{
+				"opsgenie-api-key": "key",
+			},
+			IsValid: true,
+			Message: "opsgenie",
+		},
+		{
+			Configs: map[string]string{
+				"opsgenie-api-key": "key",
+				"opsgenie-api-url": "https://api.eu.opsgenie.com",
+			},
+			IsValid: true,
+			Message: "opsgenie in the eu region",
+		},
+		{
+			Configs: map[string]string{
+				"opsgenie-api-url": "https://api.eu.opsgenie.com",
+				"organization":     "org",
+			},
+			IsValid: false,
+			Message: "opsgenie url requires an api key",
+		},
+		{
+			Configs: map[string]string{
+				"user-email":       "user@example.com",
+				"api-token":        "token",
+				"organization":     "org",
+				"opsgenie-api-key": "key",
+			},
+			IsValid: false,
+			Message: "opsgenie and basic auth are mutually exclusive",
+		},
+		{
+			Configs: map[string]string{
+				"trello-api-key":       "key",
+				"trello-api-token":     "token",
+				"trello-enterprise-id": "enterprise",
+			},
+			IsValid: true,
+			Message: "trello",
+		},
+		{
+			Configs: map[string]string{
+				"trello-api-key":   "key",
+				"trello-api-token": "token",
+			},
+			IsValid: false,
+			Message: "trello requires an enterprise",
+		},
+		{
+			Configs: map[string]string{
+				"trello-api-key":       "key",
+				"trello-api-token":     "token",
+				"trello-enterprise-id": "enterprise",
+				"opsgenie-api-key":     "key",
+			},
+			IsValid: false,
+			Message: "trello and opsgenie are mutually exclusive",
+		},
+		{
+			Configs: map[string]string{
+				"trello-api-key":       "key",
+				"trello-api-token":     "token",
+				"trello-enterprise-id": "enterprise",
+				"trello-api-url":       "http://localhost:8080",
+			},
+			IsValid: true,
+			Message: "trello through a local mock",
+		},
+		{
+			Configs: map[string]string{
+				"trello-api-key":       "key",
+				"trello-api-token":     "token",
+				"trello-enterprise-id": "enterprise",
+				"trello-api-url":       "api.trello.com",
+			},
+			IsValid: false,
+			Message: "trello url must be absolute",
+		},
+		{
+			Configs: map[string]string{
+				"trello-api-url": "https://api.trello.com",
+				"organization":   "org",
+			},
+			IsValid: false,
+			Message: "trello url requires an api key",
+		},
+		{
+			Configs: map[string]string{
+				"statuspage-api-key":         "key",
+				"statuspage-organization-id": "org",
+			},
+			IsValid: true,
+			Message: "statuspage",
+		},
+		{
+			Configs: map[string]string{
+				"statuspage-api-key": "key",
+			},
+			IsValid: false,
+			Message: "statuspage requires an organization",
+		},
+		{
+			Configs: map[string]string{
+				"statuspage-api-key":         "key",
+				"statuspage-organization-id": "org",
+				"statuspage-api-url":         "http://localhost:8080",
+			},
+			IsValid: true,
+			Message: "statuspage through a local mock",
+		},
+		{
+			Configs: map[string]string{
+				"statuspage-api-key":         "key",
+				"statuspage-organization-id": "org",
+				"statuspage-api-url":         "api.statuspage.io",
+			},
+			IsValid: false,
+			Message: "statuspage url must be absolute",
+		},
+		{
+			Configs: map[string]string{
+				"statuspage-api-url": "https://api.statuspage.io",
+				"organization":       "org",
+			},
+			IsValid: false,
+			Message: "statuspage url requires an api key",
+		},
+	})
 }
@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/conductorone/baton-atlassian/pkg/client"
 	connectorSchema "github.com/conductorone/baton-atlassian/pkg/connector"
 	"github.com/conductorone/baton-sdk/pkg/config"
 	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
 	"github.com/conductorone/baton-sdk/pkg/field"
 	"github.com/conductorone/baton-sdk/pkg/types"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -25,7 +28,8 @@ func main() {
 		"baton-atlassian",
 		getConnector,
 		field.Configuration{
-			Fields: ConfigurationFields,
+			Fields:      ConfigurationFields,
+			Constraints: FieldRelationships,
 		},
 	)
 	if err != nil {
@@ -55,12 +59,48 @@ func getConnector(ctx context.Context, v *viper.Viper) (types.ConnectorServer, e
 		return nil, err
 	}
 
-	userEmail := v.GetString(userEmailField.FieldName)
-	apiToken := v.GetString(apiTokenField.FieldName)
-	organization := v.GetString(organizationField.FieldName)
-	siteId := v.GetString(siteIdField.FieldName)
-
-	connectorBuilder, err := connectorSchema.New(ctx, userEmail, apiToken, organization, siteId)
+	var connectorBuilder *connectorSchema.Connector
+	var err error
+	usersPath := v.GetString(exportUsersCSVField.FieldName)
+	teamsPath := v.GetString(exportTeamsJSONField.FieldName)
+	switch {
+	case v.GetString(dataCenterURLField.FieldName) != "":
+		var auth client.Authenticator = client.NewBearerAuth(v.GetString(dataCenterTokenField.FieldName))
+		if application := v.GetString(crowdApplicationNameField.FieldName); application != "" {
+			auth = client.NewBasicAuth(application, v.GetString(crowdApplicationPasswordField.FieldName))
+		}
+		connectorBuilder, err = connectorSchema.NewDataCenter(
+			ctx,
+			v.GetString(dataCenterProductField.FieldName),
+			v.GetString(dataCenterURLField.FieldName),
+			auth,
+		)
+	case v.GetString(opsgenieAPIKeyField.FieldName) != "":
+		connectorBuilder, err = connectorSchema.NewOpsgenie(
+			ctx,
+			v.GetString(opsgenieAPIURLField.FieldName),
+			v.GetString(opsgenieAPIKeyField.FieldName),
+		)
+	case v.GetString(trelloAPIKeyField.FieldName) != "":
+		connectorBuilder, err = connectorSchema.NewTrello(
+			ctx,
+			v.GetString(trelloAPIURLField.FieldName),
+			v.GetString(trelloEnterpriseIDField.FieldName),
+			v.GetString(trelloAPIKeyField.FieldName),
+			v.GetString(trelloAPITokenField.FieldName),
+		)
+	case v.GetString(statuspageAPIKeyField.FieldName) != "":
+		connectorBuilder, err = connectorSchema.NewStatuspage(
+			ctx,
+			v.GetString(statuspageAPIURLField.FieldName),
+			v.GetString(statuspageOrganizationIDField.FieldName),
+			v.GetString(statuspageAPIKeyField.FieldName),
+		)
+	case usersPath != "" || teamsPath != "":
+		connectorBuilder, err = connectorSchema.NewFromExport(ctx, usersPath, teamsPath)
+	default:
+		connectorBuilder, err = newAPIConnector(ctx, v)
+	}
 	if err != nil {
 		l.Error("error creating connector", zap.Error(err))
 		return nil, err
@@ -75,3 +115,78 @@ func getConnector(ctx context.Context, v *viper.Viper) (types.ConnectorServer, e
 	}
 	return connector, nil
 }
+
+// newAPIConnector returns a connector that syncs through the Atlassian APIs.
+func newAPIConnector(ctx context.Context, v *viper.Viper) (*connectorSchema.Connector, error) {
+	organization := v.GetString(organizationField.FieldName)
+	siteId := v.GetString(siteIdField.FieldName)
+	productAccessMode := v.GetString(productAccessModeField.FieldName)
+
+	auth, adminAuth := getAuthenticators(v)
+	endpoints := client.Endpoints{
+		GraphQL:        v.GetString(graphqlURLField.FieldName),
+		Admin:          v.GetString(adminAPIURLField.FieldName),
+		UserManagement: v.GetString(userManagementAPIURLField.FieldName),
+	}
+
+	clientOptions := []client.Option{
+		client.WithCostBudget(v.GetInt(graphqlCostBudgetField.FieldName)),
+		client.WithConcurrency(v.GetInt(concurrencyField.FieldName)),
+	}
+	if backend := v.GetString(responseCacheBackendField.FieldName); backend != "" {
+		ttl := uint64(v.GetInt(responseCacheTTLField.FieldName))       //nolint:gosec // ValidateConfig rejects negative values.
+		maxSize := uint(v.GetInt(responseCacheMaxSizeField.FieldName)) //nolint:gosec // ValidateConfig rejects negative values.
+		clientOptions = append(clientOptions, client.WithHTTPCache(uhttp.CacheConfig{
+			Backend: uhttp.CacheBackend(backend),
+			TTL:     ttl,
+			MaxSize: maxSize,
+		}))
+	}
+
+	var incrementalMaxAge time.Duration
+	if v.GetBool(incrementalSyncField.FieldName) {
+		incrementalMaxAge = time.Duration(v.GetInt(incrementalSyncMaxAgeField.FieldName)) * time.Hour
+	}
+
+	return connectorSchema.New(
+		ctx,
+		auth,
+		adminAuth,
+		endpoints,
+		organization,
+		siteId,
+		productAccessMode,
+		incrementalMaxAge,
+		clientOptions...,
+	)
+}
+
+// getAuthenticators returns the credentials for the GraphQL gateway and, if an Admin API key is configured,
+// for the Admin API. FieldRelationships guarantees exactly one GraphQL credential group is set.
+func getAuthenticators(v *viper.Viper) (client.Authenticator, client.Authenticator) {
+	var auth client.Authenticator
+	switch {
+	case v.GetString(oauthRefreshTokenField.FieldName) != "":
+		auth = client.NewOAuth2RefreshToken(
+			v.GetString(oauthClientIDField.FieldName),
+			v.GetString(oauthClientSecretField.FieldName),
+			v.GetString(oauthRefreshTokenField.FieldName),
+			v.GetString(oauthTokenURLField.FieldName),
+		)
+	case v.GetString(oauthClientIDField.FieldName) != "":
+		auth = client.NewOAuth2ClientCredentials(
+			v.GetString(oauthClientIDField.FieldName),
+			v.GetString(oauthClientSecretField.FieldName),
+			v.GetString(oauthTokenURLField.FieldName),
+		)
+	default:
+		auth = client.NewBasicAuth(v.GetString(userEmailField.FieldName), v.GetString(apiTokenField.FieldName))
+	}
+
+	var adminAuth client.Authenticator
+	if adminAPIKey := v.GetString(adminAPIKeyField.FieldName); adminAPIKey != "" {
+		adminAuth = client.NewBearerAuth(adminAPIKey)
+	}
+
+	return auth, adminAuth
+}
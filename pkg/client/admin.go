@@ -0,0 +1,231 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+const (
+	GroupOwnerTypeSCIM = "EXT_SCIM"
+
+	groupsPath           = "/v1/orgs/%s/groups"
+	directoryGroupsPath  = "/v1/orgs/%s/directory/groups"
+	directoryGroupPath   = "/v1/orgs/%s/directory/groups/%s"
+	groupMembershipsPath = "/v1/orgs/%s/directory/groups/%s/memberships"
+	groupMembershipPath  = "/v1/orgs/%s/directory/groups/%s/memberships/%s"
+)
+
+var (
+	// ErrMissingAdminAPIKey is returned when an Admin API endpoint is called
+	// without Admin API credentials configured.
+	ErrMissingAdminAPIKey = errors.New("an Atlassian Admin API key is required for this operation")
+
+	// ErrGroupManagedBySCIM is returned when trying to change a group that is
+	// synced from an external identity provider.
+	ErrGroupManagedBySCIM = errors.New("group is managed by an external identity provider and cannot be changed")
+)
+
+// HasAdminAccess reports whether the client can call the Admin API.
+func (c *AtlassianClient) HasAdminAccess() bool {
+	return c.adminAuth != nil
+}
+
+// ListGroups returns a page of the groups in the configured organization.
+func (c *AtlassianClient) ListGroups(ctx context.Context, options PageOptions) ([]Group, string, annotations.Annotations, error) {
+	var res GroupsResponse
+
+	annotation, err := c.doAdminRequest(ctx, http.MethodGet, fmt.Sprintf(groupsPath, url.PathEscape(c.organizationID)), pageQuery(options), &res, nil)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res.Data, res.Links.Next, annotation, nil
+}
+
+// GetGroup returns the group with the given ID.
+func (c *AtlassianClient) GetGroup(ctx context.Context, groupID string) (*Group, annotations.Annotations, error) {
+	var res Group
+
+	annotation, err := c.doAdminRequest(ctx, http.MethodGet, directoryGroupURL(c.organizationID, groupID), nil, &res, nil)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return &res, annotation, nil
+}
+
+// CreateGroup creates a group in the organization directory.
+func (c *AtlassianClient) CreateGroup(ctx context.Context, input GroupInput) (*Group, annotations.Annotations, error) {
+	var res Group
+
+	annotation, err := c.doAdminRequest(ctx, http.MethodPost, fmt.Sprintf(directoryGroupsPath, url.PathEscape(c.organizationID)), nil, &res, input)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return &res, annotation, nil
+}
+
+// DeleteGroup deletes the group with the given ID. SCIM-managed groups are
+// rejected with ErrGroupManagedBySCIM.
+func (c *AtlassianClient) DeleteGroup(ctx context.Context, groupID string) (annotations.Annotations, error) {
+	annotation, err := c.ensureGroupIsMutable(ctx, groupID)
+	if err != nil {
+		return annotation, err
+	}
+
+	return c.doAdminRequest(ctx, http.MethodDelete, directoryGroupURL(c.organizationID, groupID), nil, nil, nil)
+}
+
+// ListGroupMembers returns a page of the members of the given group.
+func (c *AtlassianClient) ListGroupMembers(ctx context.Context, groupID string, options PageOptions) ([]GroupMember, string, annotations.Annotations, error) {
+	var res GroupMembersResponse
+
+	path := fmt.Sprintf(groupMembershipsPath, url.PathEscape(c.organizationID), url.PathEscape(groupID))
+	annotation, err := c.doAdminRequest(ctx, http.MethodGet, path, pageQuery(options), &res, nil, conditional(options)...)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res.Data, res.Links.Next, annotation, nil
+}
+
+// IsGroupMember reports whether the account is a member of the given group.
+func (c *AtlassianClient) IsGroupMember(ctx context.Context, groupID, accountID string) (bool, annotations.Annotations, error) {
+	var annotation annotations.Annotations
+
+	options := PageOptions{PageSize: ItemsPerPage}
+	for {
+		members, next, pageAnnotation, err := c.ListGroupMembers(ctx, groupID, options)
+		annotation = pageAnnotation
+		if err != nil {
+			return false, annotation, err
+		}
+
+		for _, member := range members {
+			if member.AccountID == accountID {
+				return true, annotation, nil
+			}
+		}
+
+		if next == "" {
+			return false, annotation, nil
+		}
+		options.PageToken = next
+	}
+}
+
+// AddGroupMember adds the account to the given group. SCIM-managed groups are
+// rejected with ErrGroupManagedBySCIM.
+func (c *AtlassianClient) AddGroupMember(ctx context.Context, groupID, accountID string) (annotations.Annotations, error) {
+	annotation, err := c.ensureGroupIsMutable(ctx, groupID)
+	if err != nil {
+		return annotation, err
+	}
+
+	path := fmt.Sprintf(groupMembershipsPath, url.PathEscape(c.organizationID), url.PathEscape(groupID))
+	return c.doAdminRequest(ctx, http.MethodPost, path, nil, nil, groupMembershipBody{AccountID: accountID})
+}
+
+// RemoveGroupMember removes the account from the given group. SCIM-managed
+// groups are rejected with ErrGroupManagedBySCIM.
+func (c *AtlassianClient) RemoveGroupMember(ctx context.Context, groupID, accountID string) (annotations.Annotations, error) {
+	annotation, err := c.ensureGroupIsMutable(ctx, groupID)
+	if err != nil {
+		return annotation, err
+	}
+
+	path := fmt.Sprintf(groupMembershipPath, url.PathEscape(c.organizationID), url.PathEscape(groupID), url.PathEscape(accountID))
+	return c.doAdminRequest(ctx, http.MethodDelete, path, nil, nil, nil)
+}
+
+func (c *AtlassianClient) ensureGroupIsMutable(ctx context.Context, groupID string) (annotations.Annotations, error) {
+	group, annotation, err := c.GetGroup(ctx, groupID)
+	if err != nil {
+		return annotation, err
+	}
+
+	if group.IsSCIMManaged() {
+		return annotation, fmt.Errorf("%w: %s", ErrGroupManagedBySCIM, group.Name)
+	}
+
+	return annotation, nil
+}
+
+func (c *AtlassianClient) doAdminRequest(
+	ctx context.Context,
+	method string,
+	path string,
+	query url.Values,
+	res interface{},
+	body interface{},
+	options ...uhttp.RequestOption,
+) (annotations.Annotations, error) {
+	return c.doAdminKeyRequest(ctx, method, c.adminUrl+path, query, res, body, options...)
+}
+
+// doAdminKeyRequest sends a request authenticated with the Admin API key to an absolute URL, for the APIs
+// outside of the Admin API base URL that accept the key, like user management.
+func (c *AtlassianClient) doAdminKeyRequest(
+	ctx context.Context,
+	method string,
+	rawURL string,
+	query url.Values,
+	res interface{},
+	body interface{},
+	options ...uhttp.RequestOption,
+) (annotations.Annotations, error) {
+	if c.adminAuth == nil {
+		return nil, ErrMissingAdminAPIKey
+	}
+
+	authorization, err := c.adminAuth.Authorization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	urlAddress, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	urlAddress.RawQuery = query.Encode()
+
+	_, annotation, err := c.doRequest(
+		ctx,
+		method,
+		urlAddress,
+		res,
+		body,
+		append([]uhttp.RequestOption{
+			uhttp.WithAcceptJSONHeader(),
+			uhttp.WithHeader("Authorization", authorization),
+		}, options...)...,
+	)
+	if err != nil {
+		return annotation, err
+	}
+
+	return annotation, nil
+}
+
+func directoryGroupURL(organizationID, groupID string) string {
+	return fmt.Sprintf(directoryGroupPath, url.PathEscape(organizationID), url.PathEscape(groupID))
+}
+
+func pageQuery(options PageOptions) url.Values {
+	query := url.Values{}
+	if options.PageToken != "" {
+		query.Set("cursor", options.PageToken)
+	}
+	if options.PageSize > 0 {
+		query.Set("limit", strconv.Itoa(options.PageSize))
+	}
+	return query
+}
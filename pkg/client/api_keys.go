@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+)
+
+const (
+	apiKeysPath = "/v1/orgs/%s/api-keys"
+
+	userAPITokensPath = "/%s/manage/api-tokens"
+	userAPITokenPath  = "/%s/manage/api-tokens/%s"
+)
+
+// APIKey is an Admin API key of the organization.
+type APIKey struct {
+	ID         string           `json:"id"`
+	Type       string           `json:"type"`
+	Attributes APIKeyAttributes `json:"attributes"`
+}
+
+// APIKeyAttributes describe an API key. CreatedBy is the account ID of the admin who created it, and ExpiresAt
+// is zero for keys that never expire.
+type APIKeyAttributes struct {
+	Name      string    `json:"name"`
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Scopes    []string  `json:"scopes"`
+}
+
+type APIKeysResponse struct {
+	Data  []APIKey   `json:"data"`
+	Links AdminLinks `json:"links"`
+}
+
+// UserAPIToken is a personal API token of a managed account. LastAccess is zero for tokens never used.
+type UserAPIToken struct {
+	ID         string    `json:"id"`
+	Label      string    `json:"label"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastAccess time.Time `json:"lastAccess"`
+}
+
+// ListAPIKeys returns a page of the Admin API keys of the configured organization.
+func (c *AtlassianClient) ListAPIKeys(ctx context.Context, options PageOptions) ([]APIKey, string, annotations.Annotations, error) {
+	var res APIKeysResponse
+
+	annotation, err := c.doAdminRequest(ctx, http.MethodGet, fmt.Sprintf(apiKeysPath, url.PathEscape(c.organizationID)), pageQuery(options), &res, nil)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res.Data, res.Links.Next, annotation, nil
+}
+
+// ListUserAPITokens returns the API tokens of a managed account. The user management API returns all of them
+// at once.
+func (c *AtlassianClient) ListUserAPITokens(ctx context.Context, accountID string) ([]UserAPIToken, annotations.Annotations, error) {
+	var res []UserAPIToken
+
+	annotation, err := c.doAdminKeyRequest(ctx, http.MethodGet, c.userManagementEndpoint(userAPITokensPath, accountID), nil, &res, nil)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return res, annotation, nil
+}
+
+// RevokeUserAPIToken revokes an API token of a managed account. Tokens that no longer exist are answered
+// with a NotFound error.
+func (c *AtlassianClient) RevokeUserAPIToken(ctx context.Context, accountID, tokenID string) (annotations.Annotations, error) {
+	return c.doAdminKeyRequest(ctx, http.MethodDelete, c.userManagementEndpoint(userAPITokenPath, accountID, tokenID), nil, nil, nil)
+}
+
+// userManagementEndpoint returns the URL of a user management API path.
+func (c *AtlassianClient) userManagementEndpoint(path string, ids ...string) string {
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, url.PathEscape(id))
+	}
+	return c.userManagementUrl + fmt.Sprintf(path, args...)
+}
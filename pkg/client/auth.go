@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	encoding "encoding/base64"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	defaultOAuthTokenUrl = "https://auth.atlassian.com/oauth/token"
+	oauthAudience        = "api.atlassian.com"
+)
+
+// Authenticator provides the Authorization header value for requests to the
+// Atlassian APIs.
+type Authenticator interface {
+	Authorization(ctx context.Context) (string, error)
+}
+
+// BasicAuth authenticates with an Atlassian account email and API token.
+type BasicAuth struct {
+	UserEmail string
+	APIToken  string
+}
+
+func NewBasicAuth(userEmail, apiToken string) *BasicAuth {
+	return &BasicAuth{
+		UserEmail: userEmail,
+		APIToken:  apiToken,
+	}
+}
+
+func (b *BasicAuth) Authorization(_ context.Context) (string, error) {
+	token := encoding.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", b.UserEmail, b.APIToken)))
+	return "Basic " + token, nil
+}
+
+// BearerAuth authenticates with a static bearer token, such as an Admin API key.
+type BearerAuth struct {
+	Token string
+}
+
+func NewBearerAuth(token string) *BearerAuth {
+	return &BearerAuth{
+		Token: token,
+	}
+}
+
+func (b *BearerAuth) Authorization(_ context.Context) (string, error) {
+	return "Bearer " + b.Token, nil
+}
+
+// GenieKeyAuth authenticates with an Opsgenie API key.
+type GenieKeyAuth struct {
+	APIKey string
+}
+
+func NewGenieKeyAuth(apiKey string) *GenieKeyAuth {
+	return &GenieKeyAuth{
+		APIKey: apiKey,
+	}
+}
+
+func (g *GenieKeyAuth) Authorization(_ context.Context) (string, error) {
+	return "GenieKey " + g.APIKey, nil
+}
+
+// StatuspageAuth authenticates with a Statuspage API key.
+type StatuspageAuth struct {
+	APIKey string
+}
+
+func NewStatuspageAuth(apiKey string) *StatuspageAuth {
+	return &StatuspageAuth{
+		APIKey: apiKey,
+	}
+}
+
+func (s *StatuspageAuth) Authorization(_ context.Context) (string, error) {
+	return "OAuth " + s.APIKey, nil
+}
+
+// TrelloAuth authenticates with a Trello API key and the token a user granted to it.
+type TrelloAuth struct {
+	APIKey string
+	Token  string
+}
+
+func NewTrelloAuth(apiKey, token string) *TrelloAuth {
+	return &TrelloAuth{
+		APIKey: apiKey,
+		Token:  token,
+	}
+}
+
+func (t *TrelloAuth) Authorization(_ context.Context) (string, error) {
+	return fmt.Sprintf(`OAuth oauth_consumer_key="%s", oauth_token="%s"`, t.APIKey, t.Token), nil
+}
+
+// OAuth2Auth authenticates with OAuth 2.0 access tokens that are fetched and
+// refreshed automatically from the underlying token source.
+type OAuth2Auth struct {
+	newTokenSource func(ctx context.Context) oauth2.TokenSource
+
+	mtx         sync.Mutex
+	tokenSource oauth2.TokenSource
+}
+
+// NewOAuth2ClientCredentials authenticates as an Atlassian service account
+// using the OAuth 2.0 client credentials grant.
+func NewOAuth2ClientCredentials(clientID, clientSecret, tokenURL string) *OAuth2Auth {
+	cfg := &clientcredentials.Config{
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		TokenURL:       oauthTokenURL(tokenURL),
+		EndpointParams: url.Values{"audience": {oauthAudience}},
+	}
+
+	return &OAuth2Auth{
+		newTokenSource: func(ctx context.Context) oauth2.TokenSource {
+			return cfg.TokenSource(ctx)
+		},
+	}
+}
+
+// NewOAuth2RefreshToken authenticates with an OAuth 2.0 (3LO) app using a
+// refresh token, which is exchanged for access tokens as they expire.
+func NewOAuth2RefreshToken(clientID, clientSecret, refreshToken, tokenURL string) *OAuth2Auth {
+	cfg := &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Endpoint: oauth2.Endpoint{
+			TokenURL:  oauthTokenURL(tokenURL),
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+
+	return &OAuth2Auth{
+		newTokenSource: func(ctx context.Context) oauth2.TokenSource {
+			return cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken})
+		},
+	}
+}
+
+func (o *OAuth2Auth) Authorization(ctx context.Context) (string, error) {
+	o.mtx.Lock()
+	if o.tokenSource == nil {
+		// The token source keeps the context for later refreshes, so it must
+		// outlive the request that happens to create it.
+		o.tokenSource = o.newTokenSource(context.WithoutCancel(ctx))
+	}
+	tokenSource := o.tokenSource
+	o.mtx.Unlock()
+
+	token, err := tokenSource.Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to get OAuth 2.0 access token: %w", err)
+	}
+
+	return token.Type() + " " + token.AccessToken, nil
+}
+
+// usesAPIGateway reports whether the credentials must be sent to the
+// api.atlassian.com gateway rather than the product and team endpoints.
+func usesAPIGateway(auth Authenticator) bool {
+	_, ok := auth.(*OAuth2Auth)
+	return ok
+}
+
+func oauthTokenURL(tokenURL string) string {
+	if tokenURL == "" {
+		return defaultOAuthTokenUrl
+	}
+	return tokenURL
+}
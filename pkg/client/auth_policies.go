@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+)
+
+const (
+	authPoliciesPath    = "/v1/orgs/%s/policies"
+	authPolicyUsersPath = "/v1/orgs/%s/policies/%s/users"
+
+	// AuthPolicyType is the policy type of authentication policies.
+	AuthPolicyType = "authentication-policy"
+)
+
+type AuthPolicy struct {
+	ID         string               `json:"id"`
+	Type       string               `json:"type"`
+	Attributes AuthPolicyAttributes `json:"attributes"`
+}
+
+type AuthPolicyAttributes struct {
+	Name      string             `json:"name"`
+	Type      string             `json:"type"`
+	Status    string             `json:"status"`
+	IsDefault bool               `json:"isDefault"`
+	Settings  AuthPolicySettings `json:"settings"`
+}
+
+// AuthPolicySettings are the authentication requirements a policy enforces on its members. A session timeout
+// of 0 keeps the default.
+type AuthPolicySettings struct {
+	SSOEnforced                 bool `json:"ssoEnforced"`
+	TwoStepVerificationEnforced bool `json:"twoStepVerificationEnforced"`
+	SessionTimeoutMinutes       int  `json:"sessionIdleTimeoutMinutes"`
+}
+
+type AuthPoliciesResponse struct {
+	Data  []AuthPolicy `json:"data"`
+	Links AdminLinks   `json:"links"`
+}
+
+// ListAuthPolicies returns a page of the authentication policies of the configured organization.
+func (c *AtlassianClient) ListAuthPolicies(ctx context.Context, options PageOptions) ([]AuthPolicy, string, annotations.Annotations, error) {
+	var res AuthPoliciesResponse
+
+	query := pageQuery(options)
+	query.Set("type", AuthPolicyType)
+
+	annotation, err := c.doAdminRequest(ctx, http.MethodGet, fmt.Sprintf(authPoliciesPath, url.PathEscape(c.organizationID)), query, &res, nil)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res.Data, res.Links.Next, annotation, nil
+}
+
+// ListAuthPolicyUsers returns a page of the managed accounts the authentication policy applies to.
+func (c *AtlassianClient) ListAuthPolicyUsers(ctx context.Context, policyID string, options PageOptions) ([]DirectoryUser, string, annotations.Annotations, error) {
+	var res DirectoryUsersResponse
+
+	path := fmt.Sprintf(authPolicyUsersPath, url.PathEscape(c.organizationID), url.PathEscape(policyID))
+	annotation, err := c.doAdminRequest(ctx, http.MethodGet, path, pageQuery(options), &res, nil)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res.Data, res.Links.Next, annotation, nil
+}
@@ -0,0 +1,281 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+)
+
+const (
+	bitbucketUsersPath              = "/rest/api/1.0/admin/users"
+	bitbucketUserPath               = "/rest/api/1.0/users/%s"
+	bitbucketGroupsPath             = "/rest/api/1.0/admin/groups"
+	bitbucketGroupMembersPath       = "/rest/api/1.0/admin/groups/more-members"
+	bitbucketGlobalPermissionsPath  = "/rest/api/1.0/admin/permissions/%s"
+	bitbucketProjectsPath           = "/rest/api/1.0/projects"
+	bitbucketProjectPermissionsPath = "/rest/api/1.0/projects/%s/permissions/%s"
+	bitbucketReposPath              = "/rest/api/1.0/repos"
+	bitbucketRepoPermissionsPath    = "/rest/api/1.0/projects/%s/repos/%s/permissions/%s"
+	bitbucketAccessTokensPath       = "/rest/access-tokens/1.0/users/%s"
+	bitbucketSSHKeysPath            = "/rest/ssh/1.0/keys"
+
+	bitbucketPermissionHolderUsers  = "users"
+	bitbucketPermissionHolderGroups = "groups"
+)
+
+type BitbucketUser struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	EmailAddress string `json:"emailAddress"`
+	DisplayName  string `json:"displayName"`
+	Active       bool   `json:"active"`
+	Type         string `json:"type"`
+}
+
+type BitbucketGroup struct {
+	Name string `json:"name"`
+}
+
+// BitbucketPermission is a permission, such as PROJECT_WRITE, held by either a user or a group.
+type BitbucketPermission struct {
+	User       *BitbucketUser  `json:"user,omitempty"`
+	Group      *BitbucketGroup `json:"group,omitempty"`
+	Permission string          `json:"permission"`
+}
+
+type BitbucketProject struct {
+	ID          int64  `json:"id"`
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Public      bool   `json:"public"`
+	Type        string `json:"type"`
+}
+
+type BitbucketRepository struct {
+	ID      int64            `json:"id"`
+	Slug    string           `json:"slug"`
+	Name    string           `json:"name"`
+	Public  bool             `json:"public"`
+	Project BitbucketProject `json:"project"`
+}
+
+// BitbucketAccessToken is an HTTP access token of a user. Dates are epoch milliseconds.
+type BitbucketAccessToken struct {
+	ID                string        `json:"id"`
+	Name              string        `json:"name"`
+	CreatedDate       int64         `json:"createdDate"`
+	LastAuthenticated int64         `json:"lastAuthenticated"`
+	ExpiryDate        int64         `json:"expiryDate"`
+	Permissions       []string      `json:"permissions"`
+	User              BitbucketUser `json:"user"`
+}
+
+// BitbucketSSHKey is an SSH key of a user. Dates are epoch milliseconds.
+type BitbucketSSHKey struct {
+	ID                int64  `json:"id"`
+	Label             string `json:"label"`
+	AlgorithmType     string `json:"algorithmType"`
+	BitLength         int    `json:"bitLength"`
+	CreatedDate       int64  `json:"createdDate"`
+	LastAuthenticated int64  `json:"lastAuthenticated"`
+	ExpiryDays        int    `json:"expiryDays"`
+}
+
+type bitbucketPage[T any] struct {
+	Values        []T  `json:"values"`
+	Size          int  `json:"size"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+// ListBitbucketUsers returns a page of the users of the instance.
+func (c *DataCenterClient) ListBitbucketUsers(ctx context.Context, options PageOptions) ([]BitbucketUser, string, annotations.Annotations, error) {
+	return listBitbucketPage[BitbucketUser](ctx, c, bitbucketUsersPath, nil, options)
+}
+
+// GetBitbucketUser returns the user with the given slug.
+func (c *DataCenterClient) GetBitbucketUser(ctx context.Context, slug string) (*BitbucketUser, annotations.Annotations, error) {
+	var res BitbucketUser
+
+	annotation, err := c.get(ctx, fmt.Sprintf(bitbucketUserPath, url.PathEscape(slug)), nil, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return &res, annotation, nil
+}
+
+// ListBitbucketGroups returns a page of the groups of the instance.
+func (c *DataCenterClient) ListBitbucketGroups(ctx context.Context, options PageOptions) ([]BitbucketGroup, string, annotations.Annotations, error) {
+	return listBitbucketPage[BitbucketGroup](ctx, c, bitbucketGroupsPath, nil, options)
+}
+
+// ListBitbucketGroupMembers returns a page of the members of the group.
+func (c *DataCenterClient) ListBitbucketGroupMembers(ctx context.Context, groupName string, options PageOptions) ([]BitbucketUser, string, annotations.Annotations, error) {
+	query := url.Values{}
+	query.Set("context", groupName)
+
+	return listBitbucketPage[BitbucketUser](ctx, c, bitbucketGroupMembersPath, query, options)
+}
+
+// ListBitbucketGlobalPermissions returns every user and group holding a global permission. Each holder is
+// listed once, with the highest permission it holds.
+func (c *DataCenterClient) ListBitbucketGlobalPermissions(ctx context.Context) ([]BitbucketPermission, annotations.Annotations, error) {
+	return c.listBitbucketPermissions(ctx, func(holder string) string {
+		return fmt.Sprintf(bitbucketGlobalPermissionsPath, holder)
+	})
+}
+
+// ListBitbucketProjects returns a page of the projects of the instance.
+func (c *DataCenterClient) ListBitbucketProjects(ctx context.Context, options PageOptions) ([]BitbucketProject, string, annotations.Annotations, error) {
+	return listBitbucketPage[BitbucketProject](ctx, c, bitbucketProjectsPath, nil, options)
+}
+
+// ListBitbucketProjectPermissions returns every user and group holding a permission on the project.
+func (c *DataCenterClient) ListBitbucketProjectPermissions(ctx context.Context, projectKey string) ([]BitbucketPermission, annotations.Annotations, error) {
+	return c.listBitbucketPermissions(ctx, func(holder string) string {
+		return bitbucketProjectPermissionsURL(projectKey, holder)
+	})
+}
+
+// SetBitbucketProjectPermission grants the permission on the project to the user or group, replacing the one
+// it held.
+func (c *DataCenterClient) SetBitbucketProjectPermission(ctx context.Context, projectKey string, group bool, name, permission string) (annotations.Annotations, error) {
+	return c.setBitbucketPermission(ctx, bitbucketProjectPermissionsURL(projectKey, bitbucketPermissionHolder(group)), name, permission)
+}
+
+// RevokeBitbucketProjectPermission revokes every permission of the user or group on the project.
+func (c *DataCenterClient) RevokeBitbucketProjectPermission(ctx context.Context, projectKey string, group bool, name string) (annotations.Annotations, error) {
+	return c.revokeBitbucketPermission(ctx, bitbucketProjectPermissionsURL(projectKey, bitbucketPermissionHolder(group)), name)
+}
+
+// ListBitbucketRepositories returns a page of the repositories of the instance.
+func (c *DataCenterClient) ListBitbucketRepositories(ctx context.Context, options PageOptions) ([]BitbucketRepository, string, annotations.Annotations, error) {
+	return listBitbucketPage[BitbucketRepository](ctx, c, bitbucketReposPath, nil, options)
+}
+
+// ListBitbucketRepositoryPermissions returns every user and group holding a permission on the repository.
+func (c *DataCenterClient) ListBitbucketRepositoryPermissions(ctx context.Context, projectKey, repoSlug string) ([]BitbucketPermission, annotations.Annotations, error) {
+	return c.listBitbucketPermissions(ctx, func(holder string) string {
+		return bitbucketRepositoryPermissionsURL(projectKey, repoSlug, holder)
+	})
+}
+
+// SetBitbucketRepositoryPermission grants the permission on the repository to the user or group, replacing
+// the one it held.
+func (c *DataCenterClient) SetBitbucketRepositoryPermission(ctx context.Context, projectKey, repoSlug string, group bool, name, permission string) (annotations.Annotations, error) {
+	return c.setBitbucketPermission(ctx, bitbucketRepositoryPermissionsURL(projectKey, repoSlug, bitbucketPermissionHolder(group)), name, permission)
+}
+
+// RevokeBitbucketRepositoryPermission revokes every permission of the user or group on the repository.
+func (c *DataCenterClient) RevokeBitbucketRepositoryPermission(ctx context.Context, projectKey, repoSlug string, group bool, name string) (annotations.Annotations, error) {
+	return c.revokeBitbucketPermission(ctx, bitbucketRepositoryPermissionsURL(projectKey, repoSlug, bitbucketPermissionHolder(group)), name)
+}
+
+// ListBitbucketAccessTokens returns a page of the HTTP access tokens of the user with the given slug.
+func (c *DataCenterClient) ListBitbucketAccessTokens(ctx context.Context, userSlug string, options PageOptions) ([]BitbucketAccessToken, string, annotations.Annotations, error) {
+	path := fmt.Sprintf(bitbucketAccessTokensPath, url.PathEscape(userSlug))
+	return listBitbucketPage[BitbucketAccessToken](ctx, c, path, nil, options)
+}
+
+// ListBitbucketSSHKeys returns a page of the SSH keys of the user with the given username.
+func (c *DataCenterClient) ListBitbucketSSHKeys(ctx context.Context, username string, options PageOptions) ([]BitbucketSSHKey, string, annotations.Annotations, error) {
+	query := url.Values{}
+	query.Set("user", username)
+
+	return listBitbucketPage[BitbucketSSHKey](ctx, c, bitbucketSSHKeysPath, query, options)
+}
+
+// listBitbucketPermissions returns the user permissions followed by the group permissions of a resource,
+// reading every page of both.
+func (c *DataCenterClient) listBitbucketPermissions(ctx context.Context, path func(holder string) string) ([]BitbucketPermission, annotations.Annotations, error) {
+	var permissions []BitbucketPermission
+	var annotation annotations.Annotations
+
+	for _, holder := range []string{bitbucketPermissionHolderUsers, bitbucketPermissionHolderGroups} {
+		pageToken := ""
+		for {
+			var page []BitbucketPermission
+			var err error
+			page, pageToken, annotation, err = listBitbucketPage[BitbucketPermission](ctx, c, path(holder), nil, PageOptions{
+				PageSize:  ItemsPerPage,
+				PageToken: pageToken,
+			})
+			if err != nil {
+				return nil, annotation, err
+			}
+			permissions = append(permissions, page...)
+			if pageToken == "" {
+				break
+			}
+		}
+	}
+
+	return permissions, annotation, nil
+}
+
+func (c *DataCenterClient) setBitbucketPermission(ctx context.Context, path, name, permission string) (annotations.Annotations, error) {
+	query := url.Values{}
+	query.Set("name", name)
+	query.Set("permission", permission)
+
+	return c.do(ctx, http.MethodPut, path, query, nil, nil)
+}
+
+func (c *DataCenterClient) revokeBitbucketPermission(ctx context.Context, path, name string) (annotations.Annotations, error) {
+	query := url.Values{}
+	query.Set("name", name)
+
+	return c.do(ctx, http.MethodDelete, path, query, nil, nil)
+}
+
+func bitbucketPermissionHolder(group bool) string {
+	if group {
+		return bitbucketPermissionHolderGroups
+	}
+	return bitbucketPermissionHolderUsers
+}
+
+func bitbucketProjectPermissionsURL(projectKey, holder string) string {
+	return fmt.Sprintf(bitbucketProjectPermissionsPath, url.PathEscape(projectKey), holder)
+}
+
+func bitbucketRepositoryPermissionsURL(projectKey, repoSlug, holder string) string {
+	return fmt.Sprintf(bitbucketRepoPermissionsPath, url.PathEscape(projectKey), url.PathEscape(repoSlug), holder)
+}
+
+// listBitbucketPage returns a page of a REST listing paged with start and limit, whose last page is flagged.
+func listBitbucketPage[T any](ctx context.Context, c *DataCenterClient, path string, query url.Values, options PageOptions) ([]T, string, annotations.Annotations, error) {
+	var res bitbucketPage[T]
+
+	start := 0
+	if options.PageToken != "" {
+		var err error
+		start, err = strconv.Atoi(options.PageToken)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("start", strconv.Itoa(start))
+	query.Set("limit", strconv.Itoa(getPageSize(options.PageSize)))
+
+	annotation, err := c.get(ctx, path, query, &res)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	if res.IsLastPage {
+		return res.Values, "", annotation, nil
+	}
+	return res.Values, strconv.Itoa(res.NextPageStart), annotation, nil
+}
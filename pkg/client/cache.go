@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// ErrNotModified is returned by conditional requests when the resource still
+// matches the ETag sent in If-None-Match.
+var ErrNotModified = errors.New("resource not modified")
+
+// WithHTTPCache caches successful GET responses of the Admin and product REST
+// APIs, in memory or in an on-disk database depending on config.Backend, for
+// config.TTL seconds. GraphQL requests are never cached. Requests never go
+// through the uhttp response cache, which is not cleared by mutations.
+func WithHTTPCache(config uhttp.CacheConfig) Option {
+	return func(c *AtlassianClient) {
+		c.cacheConfig = &config
+	}
+}
+
+// httpCache is the method set shared by the uhttp caches.
+type httpCache interface {
+	Get(req *http.Request) (*http.Response, error)
+	Set(req *http.Request, value *http.Response) error
+	Clear(ctx context.Context) error
+}
+
+// cachingTransport serves repeated GET requests under prefix from the cache.
+// Conditional requests always reach the server so that it can answer them
+// with 304 Not Modified. Any other request under prefix may change what those
+// GETs return, so it clears the cache once it succeeds.
+type cachingTransport struct {
+	base   http.RoundTripper
+	cache  httpCache
+	prefix string
+}
+
+func newCachingTransport(base http.RoundTripper, cache httpCache, prefix string) *cachingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &cachingTransport{
+		base:   base,
+		cache:  cache,
+		prefix: prefix,
+	}
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.HasPrefix(req.URL.String(), t.prefix) {
+		return t.base.RoundTrip(req)
+	}
+
+	l := ctxzap.Extract(req.Context())
+
+	if req.Method == http.MethodGet && req.Header.Get("If-None-Match") == "" {
+		resp, err := t.cache.Get(req)
+		if err != nil {
+			l.Warn("error reading http cache", zap.String("url", req.URL.String()), zap.Error(err))
+		}
+		if resp != nil {
+			l.Debug("http cache hit", zap.String("url", req.URL.String()))
+			return resp, nil
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case req.Method == http.MethodGet && resp.StatusCode == http.StatusOK:
+		if err := t.cache.Set(req, resp); err != nil {
+			l.Warn("error setting http cache", zap.String("url", req.URL.String()), zap.Error(err))
+		}
+	case req.Method != http.MethodGet && resp.StatusCode < http.StatusBadRequest:
+		if err := t.cache.Clear(req.Context()); err != nil {
+			l.Warn("error clearing http cache", zap.Error(err))
+		}
+	}
+
+	return resp, nil
+}
+
+// conditional returns the If-None-Match header for a conditional page request.
+func conditional(options PageOptions) []uhttp.RequestOption {
+	if options.IfNoneMatch == "" {
+		return nil
+	}
+	return []uhttp.RequestOption{uhttp.WithHeader("If-None-Match", options.IfNoneMatch)}
+}
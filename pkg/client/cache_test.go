@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+func TestCachingTransport(t *testing.T) {
+	hits := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.Method+" "+r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	cache, err := uhttp.NewHttpCache(context.Background(), &uhttp.CacheConfig{
+		Backend: uhttp.CacheBackendMemory,
+		TTL:     60,
+		MaxSize: 1,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	httpClient := &http.Client{Transport: newCachingTransport(server.Client().Transport, cache, server.URL+"/admin")}
+
+	do := func(method, path string, header http.Header) {
+		req, err := http.NewRequestWithContext(context.Background(), method, server.URL+path, nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+
+	do(http.MethodGet, "/admin/groups", nil)
+	do(http.MethodGet, "/admin/groups", nil)
+	if got := hits["GET /admin/groups"]; got != 1 {
+		t.Errorf("Expected the second admin GET to be served from the cache, got %d requests", got)
+	}
+
+	do(http.MethodGet, "/admin/groups", http.Header{"If-None-Match": {`"v1"`}})
+	if got := hits["GET /admin/groups"]; got != 2 {
+		t.Errorf("Expected the conditional GET to reach the server, got %d requests", got)
+	}
+
+	do(http.MethodGet, "/graphql", nil)
+	do(http.MethodGet, "/graphql", nil)
+	if got := hits["GET /graphql"]; got != 2 {
+		t.Errorf("Expected requests outside the admin API not to be cached, got %d requests", got)
+	}
+
+	do(http.MethodPost, "/admin/groups", nil)
+	do(http.MethodGet, "/admin/groups", nil)
+	if got := hits["GET /admin/groups"]; got != 3 {
+		t.Errorf("Expected a mutation to clear the cache, got %d requests", got)
+	}
+}
+
+func TestListGroupMembers_ConditionalRequest(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"data": [{"accountId": "account-1"}], "links": {}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("", "", "admin-key", Endpoints{Admin: server.URL}, "org", "", uhttp.NewBaseHttpClient(server.Client()))
+
+	members, _, annos, err := client.ListGroupMembers(context.Background(), "group-1", PageOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(members) != 1 {
+		t.Errorf("Expected 1 member, got %d", len(members))
+	}
+	etag := &v2.ETag{}
+	if ok, err := annos.Pick(etag); err != nil || !ok || etag.Value != `"v1"` {
+		t.Errorf("Expected the response ETag in the annotations, got %v", annos)
+	}
+
+	_, _, _, err = client.ListGroupMembers(context.Background(), "group-1", PageOptions{IfNoneMatch: `"v1"`})
+	if !errors.Is(err, ErrNotModified) {
+		t.Errorf("Expected ErrNotModified, got %v", err)
+	}
+}
+
+func TestNew_ResponseCacheReplacesUhttpCache(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "false")
+
+	var gets int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			gets++
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "group-1", "name": "engineering"}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client, err := New(ctx, NewBasicAuth("", ""), NewBearerAuth("admin-key"), Endpoints{Admin: server.URL}, "org", "", WithHTTPCache(uhttp.CacheConfig{
+		Backend: uhttp.CacheBackendMemory,
+		TTL:     60,
+		MaxSize: 1,
+	}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.GetGroup(ctx, "group-1"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if gets != 1 {
+		t.Errorf("Expected the second GET to be served from the cache, got %d requests", gets)
+	}
+
+	if _, err := client.RevokeProductRole(ctx, "workspace-1", ProductRoleAdmin, "account-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, _, err := client.GetGroup(ctx, "group-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if gets != 2 {
+		t.Errorf("Expected the mutation to invalidate every cached GET, got %d requests", gets)
+	}
+}
+
+func TestNew_LookupsBypassUhttpCache(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "false")
+
+	var gets int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gets++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "group-1", "name": "engineering"}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client, err := New(ctx, NewBasicAuth("", ""), NewBearerAuth("admin-key"), Endpoints{Admin: server.URL}, "org", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.GetGroup(ctx, "group-1"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if gets != 2 {
+		t.Errorf("Expected every GET to reach the server, got %d requests", gets)
+	}
+}
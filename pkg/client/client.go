@@ -2,256 +2,382 @@ package client
 
 import (
 	"context"
-	"embed"
-	encoding "encoding/base64"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/conductorone/baton-sdk/pkg/annotations"
-	"github.com/conductorone/baton-sdk/pkg/ratelimit"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 )
 
 type AtlassianClient struct {
-	wrapper        *uhttp.BaseHttpClient
-	userEmail      string
-	apiToken       string
-	organizationID string
-	siteID         string
+	requester
+	auth              Authenticator
+	adminAuth         Authenticator
+	graphqlUrl        string
+	adminUrl          string
+	userManagementUrl string
+	organizationID    string
+	siteID            string
+	costs             *costTracker
+	pageSizes         *pageSizeLimits
+	concurrency       int
+	cacheConfig       *uhttp.CacheConfig
+}
+
+// Option configures optional client behaviour.
+type Option func(*AtlassianClient)
+
+// WithConcurrency sets how many fetches, such as the member pages of different teams, run in parallel.
+func WithConcurrency(concurrency int) Option {
+	return func(c *AtlassianClient) {
+		if concurrency > 0 {
+			c.concurrency = concurrency
+		}
+	}
+}
+
+// WithCostBudget caps the total GraphQL query cost the client may spend.
+// Zero means no limit.
+func WithCostBudget(budget int) Option {
+	return func(c *AtlassianClient) {
+		c.costs.budget = budget
+	}
 }
 
 type GraphQLRequest struct {
-	Query     string                 `json:"query"`
-	Variables map[string]interface{} `json:"variables"`
+	OperationName string `json:"operationName,omitempty"`
+	Query         string `json:"query"`
+	Variables     any    `json:"variables"`
+	// readOnly is set on queries, which are retried after failed and interrupted attempts like GET requests.
+	// Mutations are not, since a failed attempt may have been applied.
+	readOnly bool
 }
 
+// GraphQLResponse is decoded directly into Data, which holds a pointer to one
+// of the generated operation responses.
 type GraphQLResponse struct {
-	Data   map[string]interface{} `json:"data"`
-	Errors interface{}            `json:"errors"`
+	Data       any               `json:"data"`
+	Errors     []GraphQLError    `json:"errors"`
+	Extensions GraphQLExtensions `json:"extensions"`
+}
+
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
 }
 
 const (
-	baseUrl = "https://team.atlassian.com/gateway/api/graphql"
+	baseUrl               = "https://team.atlassian.com/gateway/api/graphql"
+	gatewayBaseUrl        = "https://api.atlassian.com/graphql"
+	adminBaseUrl          = "https://api.atlassian.com/admin"
+	userManagementBaseUrl = "https://api.atlassian.com/users"
+
+	defaultSiteID = "None"
+
+	TeamMembershipSettingsOpen         = "OPEN"
+	TeamMembershipSettingsMemberInvite = "MEMBER_INVITE"
 )
 
-//go:embed *.graphql
-var graphqlFiles embed.FS
+// Endpoints holds the base URLs of the Atlassian APIs. Empty fields fall back
+// to the Atlassian commercial cloud defaults, which lets the connector target
+// isolated clouds, egress proxies or local stand-ins.
+type Endpoints struct {
+	GraphQL string
+	Admin   string
+	// UserManagement is the user management API, which manages the accounts of the organization and takes
+	// the Admin API key.
+	UserManagement string
+}
+
+// withDefaults fills in unset endpoints. OAuth 2.0 access tokens are only
+// accepted by the GraphQL gateway on api.atlassian.com.
+func (e Endpoints) withDefaults(auth Authenticator) Endpoints {
+	if e.GraphQL == "" {
+		e.GraphQL = baseUrl
+		if usesAPIGateway(auth) {
+			e.GraphQL = gatewayBaseUrl
+		}
+	}
+	if e.Admin == "" {
+		e.Admin = adminBaseUrl
+	}
+	e.Admin = strings.TrimSuffix(e.Admin, "/")
+	if e.UserManagement == "" {
+		e.UserManagement = userManagementBaseUrl
+	}
+	e.UserManagement = strings.TrimSuffix(e.UserManagement, "/")
+	return e
+}
+
+//go:generate go run ../graphql/codegen -schema ../graphql/schema/schema.graphql -out graphql_gen.go
 
-func New(ctx context.Context, userEmail, apiToken, organizationID, siteID string) (*AtlassianClient, error) {
+// New returns a client authenticating GraphQL requests with auth and Admin API
+// requests with adminAuth, which may be nil when the Admin API is not used.
+func New(ctx context.Context, auth, adminAuth Authenticator, endpoints Endpoints, organizationID, siteID string, opts ...Option) (*AtlassianClient, error) {
 	httpClient, err := uhttp.NewClient(ctx, uhttp.WithLogger(true, ctxzap.Extract(ctx)))
 	if err != nil {
 		return nil, err
 	}
 
-	cli, err := uhttp.NewBaseHttpClientWithContext(context.Background(), httpClient)
+	client := NewClientWithAuth(auth, adminAuth, endpoints, organizationID, siteID)
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	if client.cacheConfig != nil {
+		cache, err := uhttp.NewHttpCache(ctx, client.cacheConfig)
+		if err != nil {
+			return nil, err
+		}
+		httpClient.Transport = newCachingTransport(httpClient.Transport, cache, client.adminUrl)
+	}
+
+	client.wrapper, err = uhttp.NewBaseHttpClientWithContext(context.Background(), httpClient)
 	if err != nil {
 		return nil, err
 	}
 
-	client := AtlassianClient{
-		wrapper:        cli,
-		userEmail:      userEmail,
-		apiToken:       apiToken,
-		organizationID: organizationID,
-		siteID:         siteID,
-	}
+	return client, nil
+}
 
-	return &client, nil
+// NewClient returns a client using Basic auth for GraphQL requests and, when
+// adminAPIKey is set, bearer auth for Admin API requests.
+func NewClient(userEmail, apiToken, adminAPIKey string, endpoints Endpoints, organizationID, siteID string, httpClient ...*uhttp.BaseHttpClient) *AtlassianClient {
+	var adminAuth Authenticator
+	if adminAPIKey != "" {
+		adminAuth = NewBearerAuth(adminAPIKey)
+	}
+	return NewClientWithAuth(NewBasicAuth(userEmail, apiToken), adminAuth, endpoints, organizationID, siteID, httpClient...)
 }
 
-func NewClient(userEmail, apiToken, organizationID, siteID string, httpClient ...*uhttp.BaseHttpClient) *AtlassianClient {
+func NewClientWithAuth(auth, adminAuth Authenticator, endpoints Endpoints, organizationID, siteID string, httpClient ...*uhttp.BaseHttpClient) *AtlassianClient {
 	var wrapper = &uhttp.BaseHttpClient{}
 	if httpClient != nil || len(httpClient) != 0 {
 		wrapper = httpClient[0]
 	}
+
+	endpoints = endpoints.withDefaults(auth)
+
 	return &AtlassianClient{
-		wrapper:        wrapper,
-		userEmail:      userEmail,
-		apiToken:       apiToken,
-		organizationID: organizationID,
-		siteID:         siteID,
+		requester:         newRequester(wrapper),
+		auth:              auth,
+		adminAuth:         adminAuth,
+		graphqlUrl:        endpoints.GraphQL,
+		adminUrl:          endpoints.Admin,
+		userManagementUrl: endpoints.UserManagement,
+		organizationID:    organizationID,
+		siteID:            siteID,
+		costs:             &costTracker{},
+		pageSizes:         newPageSizeLimits(),
+		concurrency:       DefaultConcurrency,
 	}
 }
 
+// ListTeams returns a page of teams with all of their members. The first page of members comes with the
+// teams; the remaining pages are fetched for up to the client's concurrency limit of teams at a time.
 func (c *AtlassianClient) ListTeams(ctx context.Context, options PageOptions) ([]TeamEdge, string, annotations.Annotations, error) {
 	l := ctxzap.Extract(ctx)
-	var res TeamQuery
-	var teams []TeamEdge
-	var annotation annotations.Annotations
+	var res TeamsResponse
 	nextPageToken := ""
 
-	queryVariables := map[string]interface{}{
-		"organizationId": c.organizationID,
-		"siteId":         "None",
-		"firstTeam":      getPageSize(options.PageSize),
-	}
-	if options.PageToken != "" {
-		queryVariables["afterTeam"] = options.PageToken
-	}
-	body, err := parseGraphQLQuery("Teams.query.graphql", queryVariables)
+	annotation, err := c.getTeamsPage(ctx, &res, options)
 	if err != nil {
 		l.Error(fmt.Sprintf("Error getting resources: %s", err))
 		return nil, "", nil, err
 	}
 
-	_, err = c.getResourcesFromAPI(ctx, &res, &body)
+	teams := res.Team.TeamSearchV2.Edges
+	remainingMembers, err := FetchAll(ctx, c.concurrency, teams, func(ctx context.Context, team TeamEdge) ([]MemberEdge, error) {
+		return c.listRemainingTeamMembers(ctx, team.Node.Team)
+	})
 	if err != nil {
 		l.Error(fmt.Sprintf("Error getting resources: %s", err))
 		return nil, "", nil, err
 	}
 
-	for _, teamEdge := range res.Team.TeamSearch.Edges {
-		teamEdgeCopy := teamEdge
-		team := teamEdgeCopy.Node.Team
-		subQueryNextPageToken := ""
-		subQueryVariables := queryVariables
+	for i := range teams {
+		members := &teams[i].Node.Team.Members
+		members.Edges = append(members.Edges, remainingMembers[i]...)
+		members.PageInfo = PageInfo{}
+	}
 
-		var allMembers []MemberEdge
-		var lastPageInfo PageInfo
+	if res.Team.TeamSearchV2.PageInfo.HasNextPage {
+		nextPageToken = res.Team.TeamSearchV2.PageInfo.EndCursor
+	}
 
-		for {
-			subQueryVariables["firstMember"] = getPageSize(options.PageSize)
-			if subQueryNextPageToken != "" {
-				subQueryVariables["afterMember"] = subQueryNextPageToken
-			}
+	return teams, nextPageToken, annotation, nil
+}
 
-			membersBody, err := parseGraphQLQuery("Teams.query.graphql", subQueryVariables)
-			if err != nil {
-				l.Error(fmt.Sprintf("Error getting resources: %s", err))
-				return nil, "", nil, err
-			}
+// getTeamsPage runs the Teams query for a page of teams and the first page of their members. While the gateway
+// rejects the query as too complex, the team and member page sizes are halved and the query is retried.
+func (c *AtlassianClient) getTeamsPage(ctx context.Context, res *TeamsResponse, options PageOptions) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
 
-			var memberResp TeamQuery
-			_, err = c.getResourcesFromAPI(ctx, &memberResp, &membersBody)
-			if err != nil {
-				l.Error(fmt.Sprintf("Error getting resources: %s", err))
-				return nil, "", nil, err
-			}
+	for {
+		teamPageSize, memberPageSize := c.pageSizes.get(options.PageSize)
 
-			members := memberResp.Team.TeamSearch.Edges
-			for _, edge := range members {
-				if edge.Node.Team.ID == team.ID {
-					allMembers = append(allMembers, edge.Node.Team.Members.Edges...)
-					lastPageInfo = edge.Node.Team.Members.PageInfo
-				}
-			}
+		body := newTeamsRequest(&TeamsVariables{
+			OrganizationID: c.organizationID,
+			SiteID:         c.getSiteID(),
+			FirstTeam:      teamPageSize,
+			AfterTeam:      options.PageToken,
+			FirstMember:    memberPageSize,
+		})
 
-			if lastPageInfo.hasNextPage {
-				subQueryNextPageToken = lastPageInfo.endCursor
-			} else {
-				break
-			}
+		annotation, err := c.getResourcesFromAPI(ctx, res, body)
+		if !errors.Is(err, ErrQueryTooComplex) {
+			return annotation, err
 		}
 
-		team.Members.Edges = allMembers
-		team.Members.PageInfo = lastPageInfo
-		teamEdgeCopy.Node.Team = team
-		teams = append(teams, teamEdgeCopy)
+		if !c.pageSizes.shrink(teamPageSize, memberPageSize) {
+			return annotation, err
+		}
+		l.Info(
+			"graphql query too complex, shrinking page sizes",
+			zap.Int("team_page_size", teamPageSize),
+			zap.Int("member_page_size", memberPageSize),
+		)
 	}
+}
+
+// listRemainingTeamMembers fetches the members of a team that follow the first page returned by the Teams query.
+func (c *AtlassianClient) listRemainingTeamMembers(ctx context.Context, team TeamEdgeNodeTeam) ([]MemberEdge, error) {
+	var members []MemberEdge
+
+	pageInfo := team.Members.PageInfo
+	for pageInfo.HasNextPage {
+		var res TeamMembersResponse
+		_, memberPageSize := c.pageSizes.get(ItemsPerPage)
+
+		body := newTeamMembersRequest(&TeamMembersVariables{
+			TeamID: team.ID,
+			SiteID: c.getSiteID(),
+			First:  memberPageSize,
+			After:  pageInfo.EndCursor,
+		})
+
+		if _, err := c.getResourcesFromAPI(ctx, &res, body); err != nil {
+			return nil, err
+		}
 
-	if res.Team.TeamSearch.PageInfo.hasNextPage {
-		nextPageToken = res.Team.TeamSearch.PageInfo.endCursor
+		connection := res.Team.TeamV2.Members
+		members = append(members, connection.Edges...)
+		pageInfo = connection.PageInfo
 	}
 
-	return teams, nextPageToken, annotation, nil
+	return members, nil
 }
 
-func (c *AtlassianClient) getResourcesFromAPI(
-	ctx context.Context,
-	resources any,
-	body any,
-) (annotations.Annotations, error) {
-	var res GraphQLResponse
-	_, annotation, err := c.doRequest(ctx, &res, &body)
+// CreateTeam creates a team in the configured organization and site and
+// returns the created team.
+func (c *AtlassianClient) CreateTeam(ctx context.Context, input TeamInput) (*Team, annotations.Annotations, error) {
+	var res CreateTeamResponse
 
-	if err != nil || res.Errors != nil {
-		return nil, err
+	membershipSettings := input.MembershipSettings
+	if membershipSettings == "" {
+		membershipSettings = TeamMembershipSettingsOpen
 	}
 
-	jsonBytes, err := json.Marshal(res.Data)
+	body := newCreateTeamRequest(&CreateTeamVariables{
+		OrganizationID:     c.organizationID,
+		SiteID:             c.getSiteID(),
+		DisplayName:        input.DisplayName,
+		Description:        input.Description,
+		MembershipSettings: membershipSettings,
+	})
+
+	annotation, err := c.getResourcesFromAPI(ctx, &res, body)
 	if err != nil {
-		return nil, err
+		return nil, annotation, err
 	}
-	err = json.Unmarshal(jsonBytes, &resources)
-	if err != nil {
-		return nil, err
+
+	result := res.Team.CreateTeam
+	if err := mutationError("create team", result.Success, result.Errors); err != nil {
+		return nil, annotation, err
 	}
 
-	return annotation, nil
+	return &result.Team, annotation, nil
 }
 
-func (c *AtlassianClient) doRequest(
-	ctx context.Context,
-	res interface{},
-	body interface{},
-) (http.Header, annotations.Annotations, error) {
-	var (
-		resp *http.Response
-		err  error
-	)
+// DeleteTeam deletes the team with the given ID.
+func (c *AtlassianClient) DeleteTeam(ctx context.Context, teamID string) (annotations.Annotations, error) {
+	var res DeleteTeamResponse
 
-	urlAddress, err := url.Parse(baseUrl)
+	body := newDeleteTeamRequest(&DeleteTeamVariables{
+		TeamID: teamID,
+	})
+
+	annotation, err := c.getResourcesFromAPI(ctx, &res, body)
 	if err != nil {
-		return nil, nil, err
+		return annotation, err
 	}
 
-	authorizationToken := encoding.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", c.userEmail, c.apiToken)))
-
-	req, err := c.wrapper.NewRequest(
-		ctx,
-		http.MethodPost,
-		urlAddress,
-		uhttp.WithContentTypeJSONHeader(),
-		uhttp.WithAccept("*/*"),
-		uhttp.WithHeader("Authorization", "Basic "+authorizationToken),
-		uhttp.WithJSONBody(body),
-	)
+	result := res.Team.DeleteTeam
+	return annotation, mutationError("delete team", result.Success, result.Errors)
+}
 
-	if err != nil {
-		return nil, nil, err
+func (c *AtlassianClient) getSiteID() string {
+	if c.siteID == "" {
+		return defaultSiteID
 	}
+	return c.siteID
+}
 
-	var doOptions []uhttp.DoOption
-	if res != nil {
-		doOptions = append(doOptions, uhttp.WithResponse(&res))
-	}
-	resp, err = c.wrapper.Do(req, doOptions...)
-	if resp != nil {
-		defer resp.Body.Close()
+func (c *AtlassianClient) getResourcesFromAPI(
+	ctx context.Context,
+	resources any,
+	body *GraphQLRequest,
+) (annotations.Annotations, error) {
+	res := GraphQLResponse{Data: resources}
+
+	if err := c.costs.check(ctx); err != nil {
+		return nil, err
 	}
 
+	urlAddress, err := url.Parse(c.graphqlUrl)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	annotation := annotations.Annotations{}
-	if resp != nil {
-		if desc, err := ratelimit.ExtractRateLimitData(resp.StatusCode, &resp.Header); err == nil {
-			annotation.WithRateLimiting(desc)
-		} else {
-			return nil, annotation, err
-		}
-
-		return resp.Header, annotation, nil
+	authorization, err := c.auth.Authorization(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, nil, err
-}
+	do := c.doRequest
+	if body.readOnly {
+		do = c.doReadRequest
+	}
 
-func parseGraphQLQuery(query string, queryVariables map[string]interface{}) (interface{}, error) {
-	queryBytes, err := graphqlFiles.ReadFile(query)
+	_, annotation, err := do(
+		ctx,
+		http.MethodPost,
+		urlAddress,
+		&res,
+		body,
+		uhttp.WithAccept("*/*"),
+		uhttp.WithHeader("Authorization", authorization),
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	requestBody := GraphQLRequest{
-		Query:     strings.TrimSpace(string(queryBytes)),
-		Variables: queryVariables,
+	c.costs.record(ctx, body.OperationName, body.Variables, res.Extensions.Cost.total())
+
+	if len(res.Errors) > 0 {
+		for _, e := range res.Errors {
+			if isQueryTooComplex(e) {
+				return annotation, fmt.Errorf("%w: %s", ErrQueryTooComplex, e.Message)
+			}
+		}
+		return annotation, graphQLError(res.Errors)
 	}
 
-	return requestBody, nil
+	return annotation, nil
 }
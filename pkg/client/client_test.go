@@ -1,6 +1,10 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -8,12 +12,76 @@ func TestTrelloClient_AddCredentials(t *testing.T) {
 	mockUserEmail := "user@test.com"
 	mockApiToken := "api-token"
 
-	client := NewClient(mockUserEmail, mockApiToken, "", "")
+	client := NewClient(mockUserEmail, mockApiToken, "", Endpoints{}, "", "")
 
-	if client.userEmail != mockUserEmail {
-		t.Errorf("Set user email failed. Expected %s, got %s", mockUserEmail, client.userEmail)
+	auth, ok := client.auth.(*BasicAuth)
+	if !ok {
+		t.Fatalf("Expected basic auth, got %T", client.auth)
 	}
-	if client.apiToken != mockApiToken {
-		t.Errorf("Set API token failed. Expected %s, got %s", mockApiToken, client.apiToken)
+	if auth.UserEmail != mockUserEmail {
+		t.Errorf("Set user email failed. Expected %s, got %s", mockUserEmail, auth.UserEmail)
+	}
+	if auth.APIToken != mockApiToken {
+		t.Errorf("Set API token failed. Expected %s, got %s", mockApiToken, auth.APIToken)
+	}
+	if client.HasAdminAccess() {
+		t.Error("Expected no Admin API access without an Admin API key")
+	}
+}
+
+func TestOAuth2RefreshToken_Authorization(t *testing.T) {
+	var refreshCount int
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if r.PostForm.Get("grant_type") != "refresh_token" || r.PostForm.Get("refresh_token") != "refresh-token" {
+			t.Errorf("Unexpected token request: %v", r.PostForm)
+		}
+		refreshCount++
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "access-token",
+			"refresh_token": "refresh-token",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	}))
+	defer tokenServer.Close()
+
+	auth := NewOAuth2RefreshToken("client-id", "client-secret", "refresh-token", tokenServer.URL)
+	for i := 0; i < 2; i++ {
+		authorization, err := auth.Authorization(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if authorization != "Bearer access-token" {
+			t.Errorf("Unexpected authorization %s", authorization)
+		}
+	}
+
+	if refreshCount != 1 {
+		t.Errorf("Expected the access token to be reused, got %d refreshes", refreshCount)
+	}
+
+	client := NewClientWithAuth(auth, nil, Endpoints{}, "", "")
+	if client.graphqlUrl != gatewayBaseUrl {
+		t.Errorf("Expected OAuth requests to use the API gateway, got %s", client.graphqlUrl)
+	}
+}
+
+func TestEndpoints_WithDefaults(t *testing.T) {
+	client := NewClient("", "", "", Endpoints{Admin: "http://localhost:8080/admin/"}, "", "")
+	if client.graphqlUrl != baseUrl {
+		t.Errorf("Expected default GraphQL URL %s, got %s", baseUrl, client.graphqlUrl)
+	}
+	if client.adminUrl != "http://localhost:8080/admin" {
+		t.Errorf("Expected overridden Admin API URL without trailing slash, got %s", client.adminUrl)
+	}
+
+	client = NewClient("", "", "", Endpoints{GraphQL: "https://team.atlassian-us-gov-mod.com/gateway/api/graphql"}, "", "")
+	if client.graphqlUrl != "https://team.atlassian-us-gov-mod.com/gateway/api/graphql" {
+		t.Errorf("Expected overridden GraphQL URL, got %s", client.graphqlUrl)
 	}
 }
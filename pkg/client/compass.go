@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+)
+
+// compassTierFieldID is the ID of the built-in field holding the tier of a component, from 1 to 4.
+const compassTierFieldID = "compass:tier"
+
+// CloudID returns the cloud ID of the configured site, or an empty string when no site is configured.
+// Compass components belong to a site.
+func (c *AtlassianClient) CloudID() string {
+	if c.siteID == defaultSiteID {
+		return ""
+	}
+	return c.siteID
+}
+
+// ListCompassComponents returns a page of the Compass components of the configured site.
+func (c *AtlassianClient) ListCompassComponents(ctx context.Context, options PageOptions) ([]CompassComponent, string, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+	var res CompassComponentsResponse
+	nextPageToken := ""
+
+	body := newCompassComponentsRequest(&CompassComponentsVariables{
+		CloudID: c.CloudID(),
+		First:   getPageSize(options.PageSize),
+		After:   options.PageToken,
+	})
+
+	annotation, err := c.getResourcesFromAPI(ctx, &res, body)
+	if err != nil {
+		l.Error(fmt.Sprintf("Error getting resources: %s", err))
+		return nil, "", nil, err
+	}
+
+	result := res.Compass.SearchComponents
+	if result.Message != "" {
+		return nil, "", annotation, fmt.Errorf("search compass components: %s", result.Message)
+	}
+
+	components := make([]CompassComponent, 0, len(result.Nodes))
+	for _, node := range result.Nodes {
+		components = append(components, node.Component)
+	}
+
+	if result.PageInfo.HasNextPage {
+		nextPageToken = result.PageInfo.EndCursor
+	}
+
+	return components, nextPageToken, annotation, nil
+}
+
+// Tier returns the tier of the component, or an empty string if it has none.
+func (c *CompassComponent) Tier() string {
+	for _, field := range c.Fields {
+		if field.Definition.ID == compassTierFieldID && len(field.Value) > 0 {
+			return field.Value[0]
+		}
+	}
+	return ""
+}
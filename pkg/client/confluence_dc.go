@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+)
+
+const (
+	confluenceUsersPath        = "/rest/api/user/list"
+	confluenceUserPath         = "/rest/api/user"
+	confluenceGroupsPath       = "/rest/api/group"
+	confluenceGroupMembersPath = "/rest/api/group/%s/member"
+	confluenceSpacesPath       = "/rest/api/space"
+	confluenceJSONRPCPath      = "/rpc/json-rpc/confluenceservice-v2/%s"
+
+	confluenceGlobalPermissionsMethod = "getGlobalPermissionSets"
+	confluenceSpacePermissionsMethod  = "getSpacePermissionSets"
+	confluenceAddUserToGroupMethod    = "addUserToGroup"
+	confluenceRemoveUserFromGroup     = "removeUserFromGroup"
+)
+
+type ConfluenceUser struct {
+	Type        string `json:"type"`
+	Username    string `json:"username"`
+	UserKey     string `json:"userKey"`
+	DisplayName string `json:"displayName"`
+}
+
+type ConfluenceGroup struct {
+	Name string `json:"name"`
+}
+
+type ConfluenceSpace struct {
+	ID   int64  `json:"id"`
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ConfluencePermissionSet is a permission, such as VIEWSPACE, and the users and groups holding it.
+type ConfluencePermissionSet struct {
+	Type        string                 `json:"type"`
+	Permissions []ConfluencePermission `json:"spacePermissions"`
+}
+
+// ConfluencePermission is held by either a user, by username, or a group, by name.
+type ConfluencePermission struct {
+	Type      string `json:"type"`
+	UserName  string `json:"userName"`
+	GroupName string `json:"groupName"`
+}
+
+type confluencePage[T any] struct {
+	Results []T `json:"results"`
+	Start   int `json:"start"`
+	Limit   int `json:"limit"`
+	Size    int `json:"size"`
+}
+
+type confluenceRPCError struct {
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// ListConfluenceUsers returns a page of the users of the instance.
+func (c *DataCenterClient) ListConfluenceUsers(ctx context.Context, options PageOptions) ([]ConfluenceUser, string, annotations.Annotations, error) {
+	return listConfluencePage[ConfluenceUser](ctx, c, confluenceUsersPath, options)
+}
+
+// GetConfluenceUser returns the user with the given username or, if username is empty, user key.
+func (c *DataCenterClient) GetConfluenceUser(ctx context.Context, username, userKey string) (*ConfluenceUser, annotations.Annotations, error) {
+	var res ConfluenceUser
+
+	query := url.Values{}
+	if username != "" {
+		query.Set("username", username)
+	} else {
+		query.Set("key", userKey)
+	}
+
+	annotation, err := c.get(ctx, confluenceUserPath, query, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return &res, annotation, nil
+}
+
+// ListConfluenceGroups returns a page of the groups of the instance.
+func (c *DataCenterClient) ListConfluenceGroups(ctx context.Context, options PageOptions) ([]ConfluenceGroup, string, annotations.Annotations, error) {
+	return listConfluencePage[ConfluenceGroup](ctx, c, confluenceGroupsPath, options)
+}
+
+// ListConfluenceGroupMembers returns a page of the members of the group.
+func (c *DataCenterClient) ListConfluenceGroupMembers(ctx context.Context, groupName string, options PageOptions) ([]ConfluenceUser, string, annotations.Annotations, error) {
+	path := fmt.Sprintf(confluenceGroupMembersPath, url.PathEscape(groupName))
+	return listConfluencePage[ConfluenceUser](ctx, c, path, options)
+}
+
+// ListConfluenceSpaces returns a page of the spaces of the instance.
+func (c *DataCenterClient) ListConfluenceSpaces(ctx context.Context, options PageOptions) ([]ConfluenceSpace, string, annotations.Annotations, error) {
+	return listConfluencePage[ConfluenceSpace](ctx, c, confluenceSpacesPath, options)
+}
+
+// ListConfluenceGlobalPermissions returns the global permissions of the instance and their holders.
+func (c *DataCenterClient) ListConfluenceGlobalPermissions(ctx context.Context) ([]ConfluencePermissionSet, annotations.Annotations, error) {
+	var res []ConfluencePermissionSet
+
+	annotation, err := c.callConfluence(ctx, confluenceGlobalPermissionsMethod, true, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return res, annotation, nil
+}
+
+// ListConfluenceSpacePermissions returns the permissions of the space and their holders.
+func (c *DataCenterClient) ListConfluenceSpacePermissions(ctx context.Context, spaceKey string) ([]ConfluencePermissionSet, annotations.Annotations, error) {
+	var res []ConfluencePermissionSet
+
+	annotation, err := c.callConfluence(ctx, confluenceSpacePermissionsMethod, true, &res, spaceKey)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return res, annotation, nil
+}
+
+// AddConfluenceGroupMember adds the user to the group.
+func (c *DataCenterClient) AddConfluenceGroupMember(ctx context.Context, groupName, username string) (annotations.Annotations, error) {
+	var res bool
+	return c.callConfluence(ctx, confluenceAddUserToGroupMethod, false, &res, username, groupName)
+}
+
+// RemoveConfluenceGroupMember removes the user from the group.
+func (c *DataCenterClient) RemoveConfluenceGroupMember(ctx context.Context, groupName, username string) (annotations.Annotations, error) {
+	var res bool
+	return c.callConfluence(ctx, confluenceRemoveUserFromGroup, false, &res, username, groupName)
+}
+
+// callConfluence calls a method of the JSON-RPC API, whose parameters are sent as a JSON array. Failed calls
+// answer with an error object instead of the result. Every call is a POST request, so calls to methods that
+// only read are marked readOnly to be retried after failures.
+func (c *DataCenterClient) callConfluence(ctx context.Context, method string, readOnly bool, res interface{}, params ...interface{}) (annotations.Annotations, error) {
+	var raw json.RawMessage
+
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	path := fmt.Sprintf(confluenceJSONRPCPath, method)
+	do := c.do
+	if readOnly {
+		do = c.doRead
+	}
+	annotation, err := do(ctx, http.MethodPost, path, nil, &raw, params)
+	if err != nil {
+		return annotation, err
+	}
+
+	var rpcErr confluenceRPCError
+	if json.Unmarshal(raw, &rpcErr) == nil && rpcErr.Error != nil {
+		return annotation, fmt.Errorf("confluence %s failed: %s", method, rpcErr.Error.Message)
+	}
+
+	if err := json.Unmarshal(raw, res); err != nil {
+		return annotation, fmt.Errorf("confluence %s returned an invalid result: %w", method, err)
+	}
+
+	return annotation, nil
+}
+
+// listConfluencePage returns a page of a REST listing paged with start and limit.
+func listConfluencePage[T any](ctx context.Context, c *DataCenterClient, path string, options PageOptions) ([]T, string, annotations.Annotations, error) {
+	var res confluencePage[T]
+
+	start := 0
+	if options.PageToken != "" {
+		var err error
+		start, err = strconv.Atoi(options.PageToken)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+	limit := getPageSize(options.PageSize)
+
+	query := url.Values{}
+	query.Set("start", strconv.Itoa(start))
+	query.Set("limit", strconv.Itoa(limit))
+
+	annotation, err := c.get(ctx, path, query, &res)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res.Results, nextStartAt(start, len(res.Results), len(res.Results) < limit), annotation, nil
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const maxTrackedOperations = 5
+
+var (
+	// ErrQueryTooComplex is returned when the GraphQL gateway rejects a query
+	// because its estimated cost exceeds the per-query limit.
+	ErrQueryTooComplex = errors.New("graphql query rejected as too complex")
+
+	// ErrCostBudgetExceeded is returned once the GraphQL queries of a sync
+	// have spent the configured cost budget.
+	ErrCostBudgetExceeded = errors.New("graphql query cost budget exceeded")
+)
+
+type GraphQLExtensions struct {
+	Cost *GraphQLCost `json:"cost,omitempty"`
+}
+
+type GraphQLCost struct {
+	RequestedQueryCost int `json:"requestedQueryCost"`
+	ActualQueryCost    int `json:"actualQueryCost"`
+}
+
+// total returns the actual cost when reported, and the estimate otherwise.
+func (c *GraphQLCost) total() int {
+	if c == nil {
+		return 0
+	}
+	if c.ActualQueryCost > 0 {
+		return c.ActualQueryCost
+	}
+	return c.RequestedQueryCost
+}
+
+// isQueryTooComplex reports whether the gateway rejected the query for its
+// cost or complexity rather than for its content.
+func isQueryTooComplex(e GraphQLError) bool {
+	for _, key := range []string{"classification", "errorType", "code"} {
+		value, ok := e.Extensions[key].(string)
+		if !ok {
+			continue
+		}
+		value = strings.ToLower(value)
+		if strings.Contains(value, "complexity") || strings.Contains(value, "cost") {
+			return true
+		}
+	}
+
+	message := strings.ToLower(e.Message)
+	return strings.Contains(message, "too complex") || strings.Contains(message, "query cost")
+}
+
+type operationCost struct {
+	Operation string `json:"operation"`
+	Variables string `json:"variables"`
+	Cost      int    `json:"cost"`
+}
+
+// costTracker adds up the cost of the GraphQL operations of a sync, enforces
+// the optional budget and remembers the most expensive operations.
+type costTracker struct {
+	mtx    sync.Mutex
+	budget int
+	spent  int
+	top    []operationCost
+}
+
+// check returns ErrCostBudgetExceeded once the budget has been spent.
+func (t *costTracker) check(ctx context.Context) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.budget <= 0 || t.spent < t.budget {
+		return nil
+	}
+
+	ctxzap.Extract(ctx).Warn(
+		"graphql cost budget exceeded",
+		zap.Int("budget", t.budget),
+		zap.Int("spent", t.spent),
+		zap.Any("most_expensive_operations", t.top),
+	)
+	return fmt.Errorf("%w: spent %d of %d", ErrCostBudgetExceeded, t.spent, t.budget)
+}
+
+func (t *costTracker) record(ctx context.Context, operation string, variables any, cost int) {
+	if cost <= 0 {
+		return
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.spent += cost
+
+	if len(t.top) == maxTrackedOperations && cost <= t.top[len(t.top)-1].Cost {
+		return
+	}
+
+	op := operationCost{
+		Operation: operation,
+		Variables: fmt.Sprintf("%+v", variables),
+		Cost:      cost,
+	}
+	t.top = append(t.top, op)
+	sort.SliceStable(t.top, func(i, j int) bool {
+		return t.top[i].Cost > t.top[j].Cost
+	})
+	if len(t.top) > maxTrackedOperations {
+		t.top = t.top[:maxTrackedOperations]
+	}
+
+	ctxzap.Extract(ctx).Info(
+		"expensive graphql operation",
+		zap.String("operation", op.Operation),
+		zap.String("variables", op.Variables),
+		zap.Int("cost", op.Cost),
+		zap.Int("spent", t.spent),
+	)
+}
+
+// CostSpent returns the total GraphQL query cost spent by the client.
+func (c *AtlassianClient) CostSpent() int {
+	c.costs.mtx.Lock()
+	defer c.costs.mtx.Unlock()
+
+	return c.costs.spent
+}
+
+// ResetCosts starts a new cost budget, forgetting the operations recorded so far.
+func (c *AtlassianClient) ResetCosts() {
+	c.costs.mtx.Lock()
+	defer c.costs.mtx.Unlock()
+
+	c.costs.spent = 0
+	c.costs.top = nil
+}
+
+// pageSizeLimits caps the team and member page sizes of the Teams query. The
+// caps shrink whenever the gateway rejects a query as too complex, so later
+// pages are requested at a size the gateway accepts.
+type pageSizeLimits struct {
+	mtx    sync.Mutex
+	team   int
+	member int
+}
+
+func newPageSizeLimits() *pageSizeLimits {
+	return &pageSizeLimits{
+		team:   ItemsPerPage,
+		member: ItemsPerPage,
+	}
+}
+
+func (p *pageSizeLimits) get(requested int) (int, int) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	requested = getPageSize(requested)
+	return min(requested, p.team), min(requested, p.member)
+}
+
+// shrink halves the page sizes and returns false when they cannot get any smaller.
+func (p *pageSizeLimits) shrink(team, member int) bool {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if team <= 1 && member <= 1 {
+		return false
+	}
+
+	p.team = min(p.team, max(team/2, 1))
+	p.member = min(p.member, max(member/2, 1))
+	return true
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+func TestListTeams_ShrinksPageSizeWhenQueryTooComplex(t *testing.T) {
+	var firstTeams []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := GraphQLRequest{Variables: &TeamsVariables{}}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Error decoding request: %v", err)
+		}
+		firstTeam := req.Variables.(*TeamsVariables).FirstTeam
+		firstTeams = append(firstTeams, firstTeam)
+
+		w.Header().Set("Content-Type", "application/json")
+		if firstTeam > 25 {
+			_, _ = w.Write([]byte(`{"errors": [{"message": "Query is too complex", "extensions": {"classification": "QueryComplexityExceeded"}}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"team": {"teamSearchV2": {"edges": []}}}, "extensions": {"cost": {"requestedQueryCost": 40, "actualQueryCost": 12}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("", "", "", Endpoints{GraphQL: server.URL}, "org", "", uhttp.NewBaseHttpClient(server.Client()))
+
+	_, _, _, err := client.ListTeams(context.Background(), PageOptions{PageSize: 100})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []int{100, 50, 25}
+	if len(firstTeams) != len(expected) {
+		t.Fatalf("Expected page sizes %v, got %v", expected, firstTeams)
+	}
+	for i := range expected {
+		if firstTeams[i] != expected[i] {
+			t.Fatalf("Expected page sizes %v, got %v", expected, firstTeams)
+		}
+	}
+	if spent := client.CostSpent(); spent != 12 {
+		t.Errorf("Expected a spent cost of 12, got %d", spent)
+	}
+
+	// Later pages start at the reduced size.
+	firstTeams = nil
+	_, _, _, err = client.ListTeams(context.Background(), PageOptions{PageSize: 100})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(firstTeams) != 1 || firstTeams[0] != 25 {
+		t.Errorf("Expected a single request with 25 teams, got %v", firstTeams)
+	}
+}
+
+func TestListTeams_StopsAtCostBudget(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": {"team": {"teamSearchV2": {"edges": []}}}, "extensions": {"cost": {"actualQueryCost": 60}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("", "", "", Endpoints{GraphQL: server.URL}, "org", "", uhttp.NewBaseHttpClient(server.Client()))
+	WithCostBudget(100)(client)
+
+	for i := 0; i < 2; i++ {
+		if _, _, _, err := client.ListTeams(context.Background(), PageOptions{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	_, _, _, err := client.ListTeams(context.Background(), PageOptions{})
+	if !errors.Is(err, ErrCostBudgetExceeded) {
+		t.Fatalf("Expected ErrCostBudgetExceeded, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
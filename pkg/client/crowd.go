@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+)
+
+const (
+	crowdSearchPath           = "/rest/usermanagement/1/search"
+	crowdUserPath             = "/rest/usermanagement/1/user"
+	crowdGroupUsersPath       = "/rest/usermanagement/1/group/user/direct"
+	crowdGroupChildGroupsPath = "/rest/usermanagement/1/group/child-group/direct"
+
+	crowdEntityTypeUser  = "user"
+	crowdEntityTypeGroup = "group"
+)
+
+type CrowdUser struct {
+	Name        string `json:"name"`
+	Key         string `json:"key,omitempty"`
+	Active      bool   `json:"active"`
+	FirstName   string `json:"first-name,omitempty"`
+	LastName    string `json:"last-name,omitempty"`
+	DisplayName string `json:"display-name,omitempty"`
+	Email       string `json:"email,omitempty"`
+}
+
+type CrowdGroup struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Active      bool   `json:"active"`
+	Type        string `json:"type,omitempty"`
+}
+
+type crowdUsers struct {
+	Users []CrowdUser `json:"users"`
+}
+
+type crowdGroups struct {
+	Groups []CrowdGroup `json:"groups"`
+}
+
+// ListCrowdUsers returns a page of the users of the Crowd directory.
+func (c *DataCenterClient) ListCrowdUsers(ctx context.Context, options PageOptions) ([]CrowdUser, string, annotations.Annotations, error) {
+	var res crowdUsers
+
+	query, startIndex, maxResults, err := crowdPageQuery(options)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	query.Set("entity-type", crowdEntityTypeUser)
+	query.Set("expand", crowdEntityTypeUser)
+
+	annotation, err := c.get(ctx, crowdSearchPath, query, &res)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res.Users, nextStartAt(startIndex, len(res.Users), len(res.Users) < maxResults), annotation, nil
+}
+
+// GetCrowdUser returns the user with the given username.
+func (c *DataCenterClient) GetCrowdUser(ctx context.Context, username string) (*CrowdUser, annotations.Annotations, error) {
+	var res CrowdUser
+
+	query := url.Values{}
+	query.Set("username", username)
+
+	annotation, err := c.get(ctx, crowdUserPath, query, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return &res, annotation, nil
+}
+
+// DeactivateCrowdUser marks the user inactive, keeping its memberships. Crowd replaces the whole user, so the
+// current user is read first.
+func (c *DataCenterClient) DeactivateCrowdUser(ctx context.Context, username string) (annotations.Annotations, error) {
+	user, annotation, err := c.GetCrowdUser(ctx, username)
+	if err != nil {
+		return annotation, err
+	}
+	user.Active = false
+
+	query := url.Values{}
+	query.Set("username", username)
+
+	return c.do(ctx, http.MethodPut, crowdUserPath, query, nil, user)
+}
+
+// ListCrowdGroups returns a page of the groups of the Crowd directory.
+func (c *DataCenterClient) ListCrowdGroups(ctx context.Context, options PageOptions) ([]CrowdGroup, string, annotations.Annotations, error) {
+	var res crowdGroups
+
+	query, startIndex, maxResults, err := crowdPageQuery(options)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	query.Set("entity-type", crowdEntityTypeGroup)
+	query.Set("expand", crowdEntityTypeGroup)
+
+	annotation, err := c.get(ctx, crowdSearchPath, query, &res)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res.Groups, nextStartAt(startIndex, len(res.Groups), len(res.Groups) < maxResults), annotation, nil
+}
+
+// ListCrowdGroupUsers returns a page of the direct user members of the group.
+func (c *DataCenterClient) ListCrowdGroupUsers(ctx context.Context, groupName string, options PageOptions) ([]CrowdUser, string, annotations.Annotations, error) {
+	var res crowdUsers
+
+	query, startIndex, maxResults, err := crowdPageQuery(options)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	query.Set("groupname", groupName)
+
+	annotation, err := c.get(ctx, crowdGroupUsersPath, query, &res)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res.Users, nextStartAt(startIndex, len(res.Users), len(res.Users) < maxResults), annotation, nil
+}
+
+// ListCrowdGroupChildGroups returns a page of the groups nested directly in the group.
+func (c *DataCenterClient) ListCrowdGroupChildGroups(ctx context.Context, groupName string, options PageOptions) ([]CrowdGroup, string, annotations.Annotations, error) {
+	var res crowdGroups
+
+	query, startIndex, maxResults, err := crowdPageQuery(options)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	query.Set("groupname", groupName)
+
+	annotation, err := c.get(ctx, crowdGroupChildGroupsPath, query, &res)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res.Groups, nextStartAt(startIndex, len(res.Groups), len(res.Groups) < maxResults), annotation, nil
+}
+
+// AddCrowdGroupMember makes the user a direct member of the group.
+func (c *DataCenterClient) AddCrowdGroupMember(ctx context.Context, groupName, username string) (annotations.Annotations, error) {
+	query := url.Values{}
+	query.Set("groupname", groupName)
+
+	return c.do(ctx, http.MethodPost, crowdGroupUsersPath, query, nil, map[string]string{"name": username})
+}
+
+// RemoveCrowdGroupMember removes the user from the direct members of the group.
+func (c *DataCenterClient) RemoveCrowdGroupMember(ctx context.Context, groupName, username string) (annotations.Annotations, error) {
+	query := url.Values{}
+	query.Set("groupname", groupName)
+	query.Set("username", username)
+
+	return c.do(ctx, http.MethodDelete, crowdGroupUsersPath, query, nil, nil)
+}
+
+// crowdPageQuery returns the query of a page of a listing paged with start-index and max-results.
+func crowdPageQuery(options PageOptions) (url.Values, int, int, error) {
+	startIndex := 0
+	if options.PageToken != "" {
+		var err error
+		startIndex, err = strconv.Atoi(options.PageToken)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+	}
+	maxResults := getPageSize(options.PageSize)
+
+	query := url.Values{}
+	query.Set("start-index", strconv.Itoa(startIndex))
+	query.Set("max-results", strconv.Itoa(maxResults))
+
+	return query, startIndex, maxResults, nil
+}
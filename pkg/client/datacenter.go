@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+// DataCenterClient calls the REST API of a self-hosted Atlassian Data Center or Server product, such as
+// https://jira.example.com. Each product has its own methods, prefixed with the product name.
+type DataCenterClient struct {
+	restClient
+}
+
+// NewDataCenterClient returns a client for the Data Center instance at baseURL, authenticating with auth,
+// usually a personal access token sent as a bearer token.
+func NewDataCenterClient(ctx context.Context, baseURL string, auth Authenticator) (*DataCenterClient, error) {
+	wrapper, err := newHTTPWrapper(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDataCenterClientWithAuth(baseURL, auth, wrapper), nil
+}
+
+func NewDataCenterClientWithAuth(baseURL string, auth Authenticator, wrapper *uhttp.BaseHttpClient) *DataCenterClient {
+	return &DataCenterClient{
+		restClient: newRESTClient(baseURL, auth, wrapper),
+	}
+}
+
+// startAtQuery returns the query of a page of a Data Center listing paged with startAt and maxResults, which
+// is what the page tokens of such listings hold.
+func startAtQuery(options PageOptions) (url.Values, int, error) {
+	startAt := 0
+	if options.PageToken != "" {
+		var err error
+		startAt, err = strconv.Atoi(options.PageToken)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	pageSize := getPageSize(options.PageSize)
+
+	query := url.Values{}
+	query.Set("startAt", strconv.Itoa(startAt))
+	query.Set("maxResults", strconv.Itoa(pageSize))
+	return query, startAt, nil
+}
+
+// nextStartAt returns the page token of the page after one that started at startAt and held count items, or
+// "" when it was the last one.
+func nextStartAt(startAt, count int, last bool) string {
+	if last || count == 0 {
+		return ""
+	}
+	return strconv.Itoa(startAt + count)
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+)
+
+const eventsPath = "/v1/orgs/%s/events"
+
+// ListEvents returns a page of the organization audit log events that happened
+// between from and to.
+func (c *AtlassianClient) ListEvents(ctx context.Context, from, to time.Time, options PageOptions) ([]Event, string, annotations.Annotations, error) {
+	var res EventsResponse
+
+	query := pageQuery(options)
+	query.Set("from", strconv.FormatInt(from.UnixMilli(), 10))
+	query.Set("to", strconv.FormatInt(to.UnixMilli(), 10))
+
+	path := fmt.Sprintf(eventsPath, url.PathEscape(c.organizationID))
+	annotation, err := c.doAdminRequest(ctx, http.MethodGet, path, query, &res, nil)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res.Data, res.Links.Next, annotation, nil
+}
@@ -0,0 +1,411 @@
+// Code generated by github.com/conductorone/baton-atlassian/pkg/graphql/codegen. DO NOT EDIT.
+
+package client
+
+// compassComponentsDocument is the CompassComponents query, including the fragments it spreads.
+const compassComponentsDocument = `
+query CompassComponents ($cloudId: ID!, $first: Int = 50, $after: String) {
+	compass {
+		searchComponents(cloudId: $cloudId, query: {first:$first,after:$after}) {
+			... CompassComponentConnection
+			... QueryError
+		}
+	}
+}
+fragment CompassComponent on CompassComponent {
+	id
+	name
+	description
+	typeId
+	ownerId
+	fields {
+		... CompassEnumField
+	}
+}
+fragment CompassComponentConnection on CompassSearchComponentConnection {
+	pageInfo {
+		... PageInfo
+	}
+	nodes {
+		component {
+			... CompassComponent
+		}
+	}
+}
+fragment CompassEnumField on CompassEnumField {
+	definition {
+		id
+		name
+	}
+	value
+}
+fragment PageInfo on PageInfo {
+	hasNextPage
+	endCursor
+}
+fragment QueryError on QueryError {
+	message
+}
+`
+
+// CompassComponentsVariables are the variables of the CompassComponents query.
+type CompassComponentsVariables struct {
+	CloudID string `json:"cloudId"`
+	First   int    `json:"first,omitempty"`
+	After   string `json:"after,omitempty"`
+}
+
+// CompassComponentsResponse is the data returned by the CompassComponents query.
+type CompassComponentsResponse struct {
+	Compass CompassComponentsResponseCompass `json:"compass"`
+}
+
+type CompassComponentsResponseCompass struct {
+	SearchComponents CompassComponentsResponseCompassSearchComponents `json:"searchComponents"`
+}
+
+type CompassComponentsResponseCompassSearchComponents struct {
+	CompassComponentConnection
+	QueryError
+}
+
+// CompassComponentConnection is the CompassComponentConnection fragment on CompassSearchComponentConnection.
+type CompassComponentConnection struct {
+	PageInfo PageInfo                          `json:"pageInfo"`
+	Nodes    []CompassComponentConnectionNodes `json:"nodes"`
+}
+
+// PageInfo is the PageInfo fragment on PageInfo.
+type PageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type CompassComponentConnectionNodes struct {
+	Component CompassComponent `json:"component"`
+}
+
+// CompassComponent is the CompassComponent fragment on CompassComponent.
+type CompassComponent struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	TypeID      string             `json:"typeId"`
+	OwnerID     string             `json:"ownerId"`
+	Fields      []CompassEnumField `json:"fields"`
+}
+
+// CompassEnumField is the CompassEnumField fragment on CompassEnumField.
+type CompassEnumField struct {
+	Definition CompassEnumFieldDefinition `json:"definition"`
+	Value      []string                   `json:"value"`
+}
+
+type CompassEnumFieldDefinition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// QueryError is the QueryError fragment on QueryError.
+type QueryError struct {
+	Message string `json:"message"`
+}
+
+func newCompassComponentsRequest(variables *CompassComponentsVariables) *GraphQLRequest {
+	return &GraphQLRequest{OperationName: "CompassComponents", Query: compassComponentsDocument, Variables: variables, readOnly: true}
+}
+
+// createTeamDocument is the CreateTeam mutation, including the fragments it spreads.
+const createTeamDocument = `
+mutation CreateTeam ($organizationId: ID!, $siteId: String!, $displayName: String!, $description: String!, $membershipSettings: TeamMembershipSettings!) {
+	team {
+		createTeam(organizationId: $organizationId, siteId: $siteId, input: {displayName:$displayName,description:$description,membershipSettings:$membershipSettings}) {
+			success
+			errors {
+				... MutationError
+			}
+			team {
+				... Team
+			}
+		}
+	}
+}
+fragment MutationError on MutationError {
+	message
+	code
+}
+fragment Team on TeamV2 {
+	id
+	organizationId
+	displayName
+	description
+	membershipSettings
+}
+`
+
+// CreateTeamVariables are the variables of the CreateTeam mutation.
+type CreateTeamVariables struct {
+	OrganizationID     string `json:"organizationId"`
+	SiteID             string `json:"siteId"`
+	DisplayName        string `json:"displayName"`
+	Description        string `json:"description"`
+	MembershipSettings string `json:"membershipSettings"`
+}
+
+// CreateTeamResponse is the data returned by the CreateTeam mutation.
+type CreateTeamResponse struct {
+	Team CreateTeamResponseTeam `json:"team"`
+}
+
+type CreateTeamResponseTeam struct {
+	CreateTeam CreateTeamResponseTeamCreateTeam `json:"createTeam"`
+}
+
+type CreateTeamResponseTeamCreateTeam struct {
+	Success bool            `json:"success"`
+	Errors  []MutationError `json:"errors"`
+	Team    Team            `json:"team"`
+}
+
+// MutationError is the MutationError fragment on MutationError.
+type MutationError struct {
+	Message string `json:"message"`
+	Code    string `json:"code"`
+}
+
+// Team is the Team fragment on TeamV2.
+type Team struct {
+	ID                 string `json:"id"`
+	OrganizationID     string `json:"organizationId"`
+	DisplayName        string `json:"displayName"`
+	Description        string `json:"description"`
+	MembershipSettings string `json:"membershipSettings"`
+}
+
+func newCreateTeamRequest(variables *CreateTeamVariables) *GraphQLRequest {
+	return &GraphQLRequest{OperationName: "CreateTeam", Query: createTeamDocument, Variables: variables}
+}
+
+// deleteTeamDocument is the DeleteTeam mutation, including the fragments it spreads.
+const deleteTeamDocument = `
+mutation DeleteTeam ($teamId: ID!) {
+	team {
+		deleteTeam(id: $teamId) {
+			success
+			errors {
+				... MutationError
+			}
+		}
+	}
+}
+fragment MutationError on MutationError {
+	message
+	code
+}
+`
+
+// DeleteTeamVariables are the variables of the DeleteTeam mutation.
+type DeleteTeamVariables struct {
+	TeamID string `json:"teamId"`
+}
+
+// DeleteTeamResponse is the data returned by the DeleteTeam mutation.
+type DeleteTeamResponse struct {
+	Team DeleteTeamResponseTeam `json:"team"`
+}
+
+type DeleteTeamResponseTeam struct {
+	DeleteTeam DeleteTeamResponseTeamDeleteTeam `json:"deleteTeam"`
+}
+
+type DeleteTeamResponseTeamDeleteTeam struct {
+	Success bool            `json:"success"`
+	Errors  []MutationError `json:"errors"`
+}
+
+func newDeleteTeamRequest(variables *DeleteTeamVariables) *GraphQLRequest {
+	return &GraphQLRequest{OperationName: "DeleteTeam", Query: deleteTeamDocument, Variables: variables}
+}
+
+// teamMembersDocument is the TeamMembers query, including the fragments it spreads.
+const teamMembersDocument = `
+query TeamMembers ($teamId: ID!, $siteId: String!, $first: Int = 50, $after: String) {
+	team {
+		teamV2(id: $teamId, siteId: $siteId) {
+			members(first: $first, after: $after) {
+				pageInfo {
+					... PageInfo
+				}
+				edges {
+					... MemberEdge
+				}
+			}
+		}
+	}
+}
+fragment Member on User {
+	accountId
+	id
+	name
+}
+fragment MemberEdge on TeamMemberEdgeV2 {
+	node {
+		member {
+			... Member
+		}
+		role
+	}
+}
+fragment PageInfo on PageInfo {
+	hasNextPage
+	endCursor
+}
+`
+
+// TeamMembersVariables are the variables of the TeamMembers query.
+type TeamMembersVariables struct {
+	TeamID string `json:"teamId"`
+	SiteID string `json:"siteId"`
+	First  int    `json:"first,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// TeamMembersResponse is the data returned by the TeamMembers query.
+type TeamMembersResponse struct {
+	Team TeamMembersResponseTeam `json:"team"`
+}
+
+type TeamMembersResponseTeam struct {
+	TeamV2 TeamMembersResponseTeamTeamV2 `json:"teamV2"`
+}
+
+type TeamMembersResponseTeamTeamV2 struct {
+	Members TeamMembersResponseTeamTeamV2Members `json:"members"`
+}
+
+type TeamMembersResponseTeamTeamV2Members struct {
+	PageInfo PageInfo     `json:"pageInfo"`
+	Edges    []MemberEdge `json:"edges"`
+}
+
+// MemberEdge is the MemberEdge fragment on TeamMemberEdgeV2.
+type MemberEdge struct {
+	Node MemberEdgeNode `json:"node"`
+}
+
+type MemberEdgeNode struct {
+	Member Member `json:"member"`
+	Role   string `json:"role"`
+}
+
+// Member is the Member fragment on User.
+type Member struct {
+	AccountID string `json:"accountId"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+}
+
+func newTeamMembersRequest(variables *TeamMembersVariables) *GraphQLRequest {
+	return &GraphQLRequest{OperationName: "TeamMembers", Query: teamMembersDocument, Variables: variables, readOnly: true}
+}
+
+// teamsDocument is the Teams query, including the fragments it spreads.
+const teamsDocument = `
+query Teams ($organizationId: ID!, $siteId: String!, $firstTeam: Int = 50, $afterTeam: String, $firstMember: Int = 50) {
+	team {
+		teamSearchV2(organizationId: $organizationId, siteId: $siteId, first: $firstTeam, after: $afterTeam) {
+			pageInfo {
+				... PageInfo
+			}
+			edges {
+				... TeamEdge
+			}
+		}
+	}
+}
+fragment Member on User {
+	accountId
+	id
+	name
+}
+fragment MemberEdge on TeamMemberEdgeV2 {
+	node {
+		member {
+			... Member
+		}
+		role
+	}
+}
+fragment PageInfo on PageInfo {
+	hasNextPage
+	endCursor
+}
+fragment Team on TeamV2 {
+	id
+	organizationId
+	displayName
+	description
+	membershipSettings
+}
+fragment TeamEdge on TeamSearchResultEdgeV2 {
+	node {
+		team {
+			... Team
+			members(first: $firstMember) {
+				pageInfo {
+					... PageInfo
+				}
+				edges {
+					... MemberEdge
+				}
+			}
+		}
+	}
+}
+`
+
+// TeamsVariables are the variables of the Teams query.
+type TeamsVariables struct {
+	OrganizationID string `json:"organizationId"`
+	SiteID         string `json:"siteId"`
+	FirstTeam      int    `json:"firstTeam,omitempty"`
+	AfterTeam      string `json:"afterTeam,omitempty"`
+	FirstMember    int    `json:"firstMember,omitempty"`
+}
+
+// TeamsResponse is the data returned by the Teams query.
+type TeamsResponse struct {
+	Team TeamsResponseTeam `json:"team"`
+}
+
+type TeamsResponseTeam struct {
+	TeamSearchV2 TeamsResponseTeamTeamSearchV2 `json:"teamSearchV2"`
+}
+
+type TeamsResponseTeamTeamSearchV2 struct {
+	PageInfo PageInfo   `json:"pageInfo"`
+	Edges    []TeamEdge `json:"edges"`
+}
+
+// TeamEdge is the TeamEdge fragment on TeamSearchResultEdgeV2.
+type TeamEdge struct {
+	Node TeamEdgeNode `json:"node"`
+}
+
+type TeamEdgeNode struct {
+	Team TeamEdgeNodeTeam `json:"team"`
+}
+
+type TeamEdgeNodeTeam struct {
+	Team
+	Members TeamEdgeNodeTeamMembers `json:"members"`
+}
+
+type TeamEdgeNodeTeamMembers struct {
+	PageInfo PageInfo     `json:"pageInfo"`
+	Edges    []MemberEdge `json:"edges"`
+}
+
+func newTeamsRequest(variables *TeamsVariables) *GraphQLRequest {
+	return &GraphQLRequest{OperationName: "Teams", Query: teamsDocument, Variables: variables, readOnly: true}
+}
@@ -1,12 +1,19 @@
 package client
 
-import "os"
+import (
+	"fmt"
+	"os"
+	"strings"
+)
 
 const ItemsPerPage = 100
 
 type PageOptions struct {
 	PageSize  int
 	PageToken string
+	// IfNoneMatch makes the request conditional: when the page still matches
+	// this ETag, ErrNotModified is returned instead of the page.
+	IfNoneMatch string
 }
 
 func getPageSize(pageSize int) int {
@@ -23,3 +30,26 @@ func GetEnv(key string, defaultVal string) string {
 	}
 	return value
 }
+
+func graphQLError(errs []GraphQLError) error {
+	messages := make([]string, 0, len(errs))
+	for _, e := range errs {
+		messages = append(messages, e.Message)
+	}
+	return fmt.Errorf("graphql request failed: %s", strings.Join(messages, "; "))
+}
+
+func mutationError(operation string, success bool, errs []MutationError) error {
+	if success && len(errs) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(errs))
+	for _, e := range errs {
+		messages = append(messages, e.Message)
+	}
+	if len(messages) == 0 {
+		messages = append(messages, "unknown error")
+	}
+	return fmt.Errorf("failed to %s: %s", operation, strings.Join(messages, "; "))
+}
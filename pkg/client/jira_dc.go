@@ -0,0 +1,208 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+)
+
+const (
+	jiraUserSearchPath      = "/rest/api/2/user/search"
+	jiraUserPath            = "/rest/api/2/user"
+	jiraGroupPickerPath     = "/rest/api/2/groups/picker"
+	jiraGroupMembersPath    = "/rest/api/2/group/member"
+	jiraApplicationRolePath = "/rest/api/2/applicationrole"
+	jiraProjectsPath        = "/rest/api/2/project"
+	jiraRolesPath           = "/rest/api/2/role"
+	jiraProjectRolePath     = "/rest/api/2/project/%s/role/%d"
+
+	// jiraUserSearchAll is the user search term matching every user of a Jira Data Center instance.
+	jiraUserSearchAll = "."
+
+	// jiraGroupPickerLimit is the most groups listed. Jira Data Center has no paged listing of every group, so
+	// they are read from the group picker in one request.
+	jiraGroupPickerLimit = 10000
+
+	JiraRoleActorUser  = "atlassian-user-role-actor"
+	JiraRoleActorGroup = "atlassian-group-role-actor"
+)
+
+type JiraUser struct {
+	Key          string `json:"key"`
+	Name         string `json:"name"`
+	EmailAddress string `json:"emailAddress"`
+	DisplayName  string `json:"displayName"`
+	Active       bool   `json:"active"`
+	// DirectoryID is the user directory the user comes from, on instances that report it.
+	DirectoryID int64 `json:"directoryId,omitempty"`
+}
+
+type JiraGroup struct {
+	Name string `json:"name"`
+}
+
+type jiraGroupPickerResponse struct {
+	Total  int         `json:"total"`
+	Groups []JiraGroup `json:"groups"`
+}
+
+type jiraGroupMembersResponse struct {
+	IsLast bool       `json:"isLast"`
+	Values []JiraUser `json:"values"`
+}
+
+// JiraApplicationRole is an application, such as Jira Software, and the groups whose members can use it.
+type JiraApplicationRole struct {
+	Key               string   `json:"key"`
+	Name              string   `json:"name"`
+	Groups            []string `json:"groups"`
+	DefaultGroups     []string `json:"defaultGroups"`
+	NumberOfSeats     int      `json:"numberOfSeats"`
+	RemainingSeats    int      `json:"remainingSeats"`
+	UserCount         int      `json:"userCount"`
+	HasUnlimitedSeats bool     `json:"hasUnlimitedSeats"`
+}
+
+type JiraProject struct {
+	ID   string `json:"id"`
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+type JiraProjectRole struct {
+	ID          int64           `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Actors      []JiraRoleActor `json:"actors,omitempty"`
+}
+
+// JiraRoleActor is a user or group holding a project role. Name is the username or the group name.
+type JiraRoleActor struct {
+	ID          int64  `json:"id"`
+	DisplayName string `json:"displayName"`
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+}
+
+// ListJiraUsers returns a page of the active and inactive users of the instance.
+func (c *DataCenterClient) ListJiraUsers(ctx context.Context, options PageOptions) ([]JiraUser, string, annotations.Annotations, error) {
+	var res []JiraUser
+
+	query, startAt, err := startAtQuery(options)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	query.Set("username", jiraUserSearchAll)
+	query.Set("includeActive", "true")
+	query.Set("includeInactive", "true")
+
+	annotation, err := c.get(ctx, jiraUserSearchPath, query, &res)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res, nextStartAt(startAt, len(res), len(res) < getPageSize(options.PageSize)), annotation, nil
+}
+
+// GetJiraUser returns the user with the given username.
+func (c *DataCenterClient) GetJiraUser(ctx context.Context, username string) (*JiraUser, annotations.Annotations, error) {
+	var res JiraUser
+
+	annotation, err := c.get(ctx, jiraUserPath, url.Values{"username": {username}}, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return &res, annotation, nil
+}
+
+// ListJiraGroups returns every group of the instance.
+func (c *DataCenterClient) ListJiraGroups(ctx context.Context) ([]JiraGroup, annotations.Annotations, error) {
+	var res jiraGroupPickerResponse
+
+	query := url.Values{}
+	query.Set("query", "")
+	query.Set("maxResults", strconv.Itoa(jiraGroupPickerLimit))
+
+	annotation, err := c.get(ctx, jiraGroupPickerPath, query, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	if res.Total > len(res.Groups) {
+		return nil, annotation, fmt.Errorf("jira data center lists %d of its %d groups", len(res.Groups), res.Total)
+	}
+
+	return res.Groups, annotation, nil
+}
+
+// ListJiraGroupMembers returns a page of the active and inactive members of the group.
+func (c *DataCenterClient) ListJiraGroupMembers(ctx context.Context, groupName string, options PageOptions) ([]JiraUser, string, annotations.Annotations, error) {
+	var res jiraGroupMembersResponse
+
+	query, startAt, err := startAtQuery(options)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	query.Set("groupname", groupName)
+	query.Set("includeInactiveUsers", "true")
+
+	annotation, err := c.get(ctx, jiraGroupMembersPath, query, &res)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res.Values, nextStartAt(startAt, len(res.Values), res.IsLast), annotation, nil
+}
+
+// ListJiraApplicationRoles returns the application roles of the instance.
+func (c *DataCenterClient) ListJiraApplicationRoles(ctx context.Context) ([]JiraApplicationRole, annotations.Annotations, error) {
+	var res []JiraApplicationRole
+
+	annotation, err := c.get(ctx, jiraApplicationRolePath, nil, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return res, annotation, nil
+}
+
+// ListJiraProjects returns every project of the instance.
+func (c *DataCenterClient) ListJiraProjects(ctx context.Context) ([]JiraProject, annotations.Annotations, error) {
+	var res []JiraProject
+
+	annotation, err := c.get(ctx, jiraProjectsPath, nil, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return res, annotation, nil
+}
+
+// ListJiraProjectRoles returns the project roles of the instance, without their actors.
+func (c *DataCenterClient) ListJiraProjectRoles(ctx context.Context) ([]JiraProjectRole, annotations.Annotations, error) {
+	var res []JiraProjectRole
+
+	annotation, err := c.get(ctx, jiraRolesPath, nil, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return res, annotation, nil
+}
+
+// GetJiraProjectRole returns the project role with the users and groups holding it in the project.
+func (c *DataCenterClient) GetJiraProjectRole(ctx context.Context, projectID string, roleID int64) (*JiraProjectRole, annotations.Annotations, error) {
+	var res JiraProjectRole
+
+	path := fmt.Sprintf(jiraProjectRolePath, url.PathEscape(projectID), roleID)
+	annotation, err := c.get(ctx, path, nil, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return &res, annotation, nil
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRequestsPerSecond = 10
+	defaultBurst             = 10
+)
+
+// rateLimiter is waited on before every request of a client.
+type rateLimiter interface {
+	Wait(ctx context.Context) error
+	PauseUntil(t time.Time)
+}
+
+// noRateLimit is a rateLimiter that never waits.
+type noRateLimit struct{}
+
+func (noRateLimit) Wait(context.Context) error { return nil }
+
+func (noRateLimit) PauseUntil(time.Time) {}
+
+// tokenBucket is a context-aware token bucket shared by every request made
+// through a client, so concurrently syncing resource types draw from the same
+// budget. When the tenant signals throttling, the bucket is paused for all
+// callers until the advertised retry time.
+type tokenBucket struct {
+	mtx         sync.Mutex
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newTokenBucket(requestsPerSecond float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent or the context is done.
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	for {
+		wait := b.reserve(time.Now())
+		if wait == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// PauseUntil stops handing out tokens until the given time.
+func (b *tokenBucket) PauseUntil(t time.Time) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if t.After(b.pausedUntil) {
+		b.pausedUntil = t
+		b.tokens = 0
+	}
+}
+
+// reserve takes a token and returns zero, or returns how long to wait before
+// trying again.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now)
+	}
+
+	if b.last.Before(b.pausedUntil) {
+		b.last = b.pausedUntil
+	}
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
@@ -1,48 +1,120 @@
 package client
 
-type PageInfo struct {
-	hasNextPage bool
-	endCursor   string
+type TeamInput struct {
+	DisplayName        string
+	Description        string
+	MembershipSettings string
 }
-type TeamQuery struct {
-	Team struct {
-		TeamSearch TeamSearch `json:"teamSearchV2"`
-	} `json:"team"`
+
+type AdminLinks struct {
+	Next string `json:"next"`
+}
+
+type Group struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	ResourceOwnerType string `json:"resourceOwnerType"`
+}
+
+// IsSCIMManaged reports whether the group is synced from an external identity
+// provider, in which case its membership can only be changed at the source.
+func (g *Group) IsSCIMManaged() bool {
+	return g.ResourceOwnerType == GroupOwnerTypeSCIM
+}
+
+type GroupsResponse struct {
+	Data  []Group    `json:"data"`
+	Links AdminLinks `json:"links"`
+}
+
+type GroupMember struct {
+	AccountID string `json:"account_id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+}
+
+type GroupMembersResponse struct {
+	Data  []GroupMember `json:"data"`
+	Links AdminLinks    `json:"links"`
+}
+
+type GroupInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
 }
 
-type TeamSearch struct {
-	PageInfo PageInfo   `json:"pageInfo"`
-	Edges    []TeamEdge `json:"edges"`
+type groupMembershipBody struct {
+	AccountID string `json:"account_id"`
 }
 
-type TeamEdge struct {
-	Node struct {
-		Team Team `json:"team"`
-	} `json:"node"`
+type Workspace struct {
+	ID         string              `json:"id"`
+	Type       string              `json:"type"`
+	Attributes WorkspaceAttributes `json:"attributes"`
 }
 
-type Team struct {
-	ID             string           `json:"id"`
-	OrganizationID string           `json:"organizationId"`
-	DisplayName    string           `json:"displayName"`
-	Description    string           `json:"description"`
-	Members        MemberConnection `json:"members"`
+type WorkspaceAttributes struct {
+	Name                  string   `json:"name"`
+	TypeKey               string   `json:"typeKey"`
+	HostURL               string   `json:"hostUrl"`
+	Status                string   `json:"status"`
+	Usage                 int      `json:"usage"`
+	Capacity              int      `json:"capacity"`
+	DefaultAccessGroupIDs []string `json:"defaultAccessGroupIds"`
 }
 
-type MemberConnection struct {
-	PageInfo PageInfo     `json:"pageInfo"`
-	Edges    []MemberEdge `json:"edges"`
+// SeatsExhausted reports whether every licensed seat of the product is in use.
+// Products without a reported capacity are treated as unlimited.
+func (w *Workspace) SeatsExhausted() bool {
+	return w.Attributes.Capacity > 0 && w.Attributes.Usage >= w.Attributes.Capacity
 }
 
-type MemberEdge struct {
-	Node struct {
-		Member Member `json:"member"`
-		Role   string `json:"role"`
-	} `json:"node"`
+type WorkspacesResponse struct {
+	Data  []Workspace `json:"data"`
+	Links AdminLinks  `json:"links"`
 }
 
-type Member struct {
-	ID        string `json:"id"`
+type WorkspaceResponse struct {
+	Data Workspace `json:"data"`
+}
+
+type DirectoryUser struct {
 	AccountID string `json:"accountId"`
 	Name      string `json:"name"`
+	Email     string `json:"email"`
+}
+
+type DirectoryUsersResponse struct {
+	Data  []DirectoryUser `json:"data"`
+	Links AdminLinks      `json:"links"`
+}
+
+type roleAssignmentBody struct {
+	ResourceID string `json:"resourceId"`
+	RoleID     string `json:"roleId"`
+}
+
+type Event struct {
+	ID         string          `json:"id"`
+	Attributes EventAttributes `json:"attributes"`
+}
+
+type EventAttributes struct {
+	Time      string        `json:"time"`
+	Action    string        `json:"action"`
+	Context   []EventObject `json:"context"`
+	Container []EventObject `json:"container"`
+}
+
+// EventObject is an object an audit log event acted on, such as a group or a
+// product.
+type EventObject struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+type EventsResponse struct {
+	Data  []Event    `json:"data"`
+	Links AdminLinks `json:"links"`
 }
@@ -0,0 +1,226 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+const (
+	// DefaultOpsgenieURL is the Opsgenie API of the US region; the EU region is at https://api.eu.opsgenie.com.
+	DefaultOpsgenieURL = "https://api.opsgenie.com"
+
+	opsgenieUsersPath       = "/v2/users"
+	opsgenieRolesPath       = "/v2/roles"
+	opsgenieTeamsPath       = "/v2/teams"
+	opsgenieTeamPath        = "/v2/teams/%s"
+	opsgenieTeamMembersPath = "/v2/teams/%s/members"
+	opsgenieTeamMemberPath  = "/v2/teams/%s/members/%s"
+	opsgenieSchedulesPath   = "/v2/schedules"
+	opsgenieSchedulePath    = "/v2/schedules/%s"
+
+	// opsgenieMaxPageSize is the largest page of users Opsgenie returns.
+	opsgenieMaxPageSize = 500
+
+	OpsgenieTeamRoleAdmin = "admin"
+	OpsgenieTeamRoleUser  = "user"
+
+	OpsgenieParticipantUser = "user"
+	OpsgenieParticipantTeam = "team"
+)
+
+// OpsgenieRoles are the built-in account roles of Opsgenie, from highest to lowest.
+var OpsgenieRoles = []string{"Owner", "Admin", "User", "Stakeholder"}
+
+// OpsgenieClient calls the Opsgenie REST API with an API key.
+type OpsgenieClient struct {
+	restClient
+}
+
+type OpsgenieRole struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type OpsgenieUser struct {
+	ID        string       `json:"id"`
+	Username  string       `json:"username"`
+	FullName  string       `json:"fullName"`
+	Role      OpsgenieRole `json:"role"`
+	Blocked   bool         `json:"blocked"`
+	Verified  bool         `json:"verified"`
+	TimeZone  string       `json:"timeZone"`
+	CreatedAt string       `json:"createdAt"`
+}
+
+type OpsgenieTeam struct {
+	ID          string               `json:"id"`
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Members     []OpsgenieTeamMember `json:"members,omitempty"`
+}
+
+type OpsgenieTeamMember struct {
+	User OpsgenieUserRef `json:"user"`
+	Role string          `json:"role"`
+}
+
+type OpsgenieUserRef struct {
+	ID       string `json:"id,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+type OpsgenieSchedule struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Timezone    string             `json:"timezone"`
+	Enabled     bool               `json:"enabled"`
+	OwnerTeam   *OpsgenieTeam      `json:"ownerTeam,omitempty"`
+	Rotations   []OpsgenieRotation `json:"rotations"`
+}
+
+type OpsgenieRotation struct {
+	ID           string                `json:"id"`
+	Name         string                `json:"name"`
+	Participants []OpsgenieParticipant `json:"participants"`
+}
+
+// OpsgenieParticipant is a user, team or escalation taking part in a rotation.
+type OpsgenieParticipant struct {
+	Type     string `json:"type"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
+
+type opsgenieResponse[T any] struct {
+	Data T `json:"data"`
+}
+
+// NewOpsgenieClient returns a client for the Opsgenie API at baseURL, or DefaultOpsgenieURL if it is empty.
+func NewOpsgenieClient(ctx context.Context, baseURL, apiKey string) (*OpsgenieClient, error) {
+	wrapper, err := newHTTPWrapper(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewOpsgenieClientWithAuth(baseURL, NewGenieKeyAuth(apiKey), wrapper), nil
+}
+
+func NewOpsgenieClientWithAuth(baseURL string, auth Authenticator, wrapper *uhttp.BaseHttpClient) *OpsgenieClient {
+	if baseURL == "" {
+		baseURL = DefaultOpsgenieURL
+	}
+
+	return &OpsgenieClient{
+		restClient: newRESTClient(baseURL, auth, wrapper),
+	}
+}
+
+// ListUsers returns a page of the users of the account.
+func (c *OpsgenieClient) ListUsers(ctx context.Context, options PageOptions) ([]OpsgenieUser, string, annotations.Annotations, error) {
+	var res opsgenieResponse[[]OpsgenieUser]
+
+	offset := 0
+	if options.PageToken != "" {
+		var err error
+		offset, err = strconv.Atoi(options.PageToken)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+	limit := min(getPageSize(options.PageSize), opsgenieMaxPageSize)
+
+	query := url.Values{}
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("limit", strconv.Itoa(limit))
+
+	annotation, err := c.get(ctx, opsgenieUsersPath, query, &res)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res.Data, nextStartAt(offset, len(res.Data), len(res.Data) < limit), annotation, nil
+}
+
+// ListCustomRoles returns the custom account roles defined in addition to OpsgenieRoles.
+func (c *OpsgenieClient) ListCustomRoles(ctx context.Context) ([]OpsgenieRole, annotations.Annotations, error) {
+	var res opsgenieResponse[[]OpsgenieRole]
+
+	annotation, err := c.get(ctx, opsgenieRolesPath, nil, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return res.Data, annotation, nil
+}
+
+// ListTeams returns every team of the account, without their members.
+func (c *OpsgenieClient) ListTeams(ctx context.Context) ([]OpsgenieTeam, annotations.Annotations, error) {
+	var res opsgenieResponse[[]OpsgenieTeam]
+
+	annotation, err := c.get(ctx, opsgenieTeamsPath, nil, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return res.Data, annotation, nil
+}
+
+// GetTeam returns the team with its members.
+func (c *OpsgenieClient) GetTeam(ctx context.Context, teamID string) (*OpsgenieTeam, annotations.Annotations, error) {
+	var res opsgenieResponse[OpsgenieTeam]
+
+	annotation, err := c.get(ctx, fmt.Sprintf(opsgenieTeamPath, url.PathEscape(teamID)), nil, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return &res.Data, annotation, nil
+}
+
+// AddTeamMember adds the user to the team with the given role, OpsgenieTeamRoleAdmin or OpsgenieTeamRoleUser.
+func (c *OpsgenieClient) AddTeamMember(ctx context.Context, teamID, userID, role string) (annotations.Annotations, error) {
+	body := OpsgenieTeamMember{
+		User: OpsgenieUserRef{ID: userID},
+		Role: role,
+	}
+
+	return c.do(ctx, http.MethodPost, fmt.Sprintf(opsgenieTeamMembersPath, url.PathEscape(teamID)), nil, nil, body)
+}
+
+// RemoveTeamMember removes the user from the team.
+func (c *OpsgenieClient) RemoveTeamMember(ctx context.Context, teamID, userID string) (annotations.Annotations, error) {
+	path := fmt.Sprintf(opsgenieTeamMemberPath, url.PathEscape(teamID), url.PathEscape(userID))
+	return c.do(ctx, http.MethodDelete, path, nil, nil, nil)
+}
+
+// ListSchedules returns every schedule of the account.
+func (c *OpsgenieClient) ListSchedules(ctx context.Context) ([]OpsgenieSchedule, annotations.Annotations, error) {
+	var res opsgenieResponse[[]OpsgenieSchedule]
+
+	annotation, err := c.get(ctx, opsgenieSchedulesPath, nil, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return res.Data, annotation, nil
+}
+
+// GetSchedule returns the schedule with its rotations and their participants.
+func (c *OpsgenieClient) GetSchedule(ctx context.Context, scheduleID string) (*OpsgenieSchedule, annotations.Annotations, error) {
+	var res opsgenieResponse[OpsgenieSchedule]
+
+	annotation, err := c.get(ctx, fmt.Sprintf(opsgenieSchedulePath, url.PathEscape(scheduleID)), nil, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return &res.Data, annotation, nil
+}
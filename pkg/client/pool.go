@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultConcurrency is the number of fetches a client runs in parallel unless configured otherwise.
+const DefaultConcurrency = 4
+
+// FetchAll calls fetch for every item, with at most concurrency calls in flight, and returns the results in
+// the order of items so the output does not depend on scheduling. The first error cancels the context passed
+// to the remaining calls and is returned. Requests made by fetch still go through the client's shared rate
+// limiter, so concurrency bounds parallelism without raising the request rate.
+func FetchAll[T, R any](ctx context.Context, concurrency int, items []T, fetch func(context.Context, T) (R, error)) ([]R, error) {
+	if len(items) == 0 {
+		return nil, ctx.Err()
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		results  = make([]R, len(items))
+		indexes  = make(chan int)
+	)
+
+	for w := 0; w < min(concurrency, len(items)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result, err := fetch(fetchCtx, items[i])
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				results[i] = result
+			}
+		}()
+	}
+
+feed:
+	for i := range items {
+		select {
+		case indexes <- i:
+		case <-fetchCtx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+func TestFetchAll_KeepsInputOrderAndLimitsConcurrency(t *testing.T) {
+	items := make([]int, 50)
+	for i := range items {
+		items[i] = i
+	}
+
+	var inFlight, maxInFlight atomic.Int32
+	results, err := FetchAll(context.Background(), 4, items, func(ctx context.Context, item int) (string, error) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			peak := maxInFlight.Load()
+			if current <= peak || maxInFlight.CompareAndSwap(peak, current) {
+				break
+			}
+		}
+
+		// Later items finish first.
+		time.Sleep(time.Duration(len(items)-item) * 50 * time.Microsecond)
+		return fmt.Sprintf("item-%d", item), nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i, result := range results {
+		if result != fmt.Sprintf("item-%d", i) {
+			t.Fatalf("Expected results in input order, got %v", results)
+		}
+	}
+	if peak := maxInFlight.Load(); peak > 4 {
+		t.Errorf("Expected at most 4 fetches in flight, got %d", peak)
+	}
+}
+
+func TestFetchAll_CancelsRemainingFetchesOnError(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	items := make([]int, 100)
+
+	var started atomic.Int32
+	_, err := FetchAll(context.Background(), 2, items, func(ctx context.Context, _ int) (int, error) {
+		if started.Add(1) == 1 {
+			return 0, errFetch
+		}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, errFetch) {
+		t.Fatalf("Expected the first error, got %v", err)
+	}
+	if got := started.Load(); got > 3 {
+		t.Errorf("Expected the remaining fetches to be skipped, %d started", got)
+	}
+}
+
+func TestFetchAll_StopsWhenContextIsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := FetchAll(ctx, 2, []int{1, 2, 3}, func(ctx context.Context, item int) (int, error) {
+		return item, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestListTeams_FetchesRemainingMemberPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			OperationName string         `json:"operationName"`
+			Variables     map[string]any `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.OperationName == "Teams" {
+			_, _ = w.Write([]byte(`{"data": {"team": {"teamSearchV2": {"edges": [
+				{"node": {"team": {"id": "team-1", "members": {"pageInfo": {"hasNextPage": true, "endCursor": "team-1-page-1"},
+					"edges": [{"node": {"member": {"accountId": "user-1"}, "role": "REGULAR"}}]}}}},
+				{"node": {"team": {"id": "team-2", "members": {"pageInfo": {"hasNextPage": false},
+					"edges": [{"node": {"member": {"accountId": "user-2"}, "role": "ADMIN"}}]}}}}
+			]}}}}`))
+			return
+		}
+
+		switch req.Variables["after"] {
+		case "team-1-page-1":
+			_, _ = w.Write([]byte(`{"data": {"team": {"teamV2": {"members": {"pageInfo": {"hasNextPage": true, "endCursor": "team-1-page-2"},
+				"edges": [{"node": {"member": {"accountId": "user-3"}, "role": "REGULAR"}}]}}}}}`))
+		case "team-1-page-2":
+			_, _ = w.Write([]byte(`{"data": {"team": {"teamV2": {"members": {"pageInfo": {"hasNextPage": false},
+				"edges": [{"node": {"member": {"accountId": "user-4"}, "role": "ADMIN"}}]}}}}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("", "", "", Endpoints{GraphQL: server.URL}, "org", "", uhttp.NewBaseHttpClient(server.Client()))
+
+	teams, _, _, err := client.ListTeams(context.Background(), PageOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string][]string{
+		"team-1": {"user-1", "user-3", "user-4"},
+		"team-2": {"user-2"},
+	}
+	for _, team := range teams {
+		var accountIDs []string
+		for _, member := range team.Node.Team.Members.Edges {
+			accountIDs = append(accountIDs, member.Node.Member.AccountID)
+		}
+		if fmt.Sprint(accountIDs) != fmt.Sprint(expected[team.Node.Team.ID]) {
+			t.Errorf("Unexpected members of %s: %v", team.Node.Team.ID, accountIDs)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+)
+
+const (
+	ProductRoleUser  = "atlassian/user"
+	ProductRoleAdmin = "atlassian/admin"
+
+	workspacesPath     = "/v2/orgs/%s/workspaces"
+	workspacePath      = "/v2/orgs/%s/workspaces/%s"
+	directoryUsersPath = "/v2/orgs/%s/directories/-/users"
+	roleAssignPath     = "/v2/orgs/%s/directories/-/users/%s/role-assignments/assign"
+	roleRevokePath     = "/v2/orgs/%s/directories/-/users/%s/role-assignments/revoke"
+)
+
+// ErrLicenseLimitReached is returned when granting product access to a product
+// whose licensed seats are all in use.
+var ErrLicenseLimitReached = errors.New("product license limit reached")
+
+// ListWorkspaces returns a page of the product instances (workspaces) in the
+// configured organization.
+func (c *AtlassianClient) ListWorkspaces(ctx context.Context, options PageOptions) ([]Workspace, string, annotations.Annotations, error) {
+	var res WorkspacesResponse
+
+	path := fmt.Sprintf(workspacesPath, url.PathEscape(c.organizationID))
+	annotation, err := c.doAdminRequest(ctx, http.MethodGet, path, pageQuery(options), &res, nil)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res.Data, res.Links.Next, annotation, nil
+}
+
+// GetWorkspace returns the product instance with the given ID.
+func (c *AtlassianClient) GetWorkspace(ctx context.Context, workspaceID string) (*Workspace, annotations.Annotations, error) {
+	var res WorkspaceResponse
+
+	path := fmt.Sprintf(workspacePath, url.PathEscape(c.organizationID), url.PathEscape(workspaceID))
+	annotation, err := c.doAdminRequest(ctx, http.MethodGet, path, nil, &res, nil)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return &res.Data, annotation, nil
+}
+
+// ListDirectoryUsers returns a page of the users of the organization, whether or not they belong to a team.
+func (c *AtlassianClient) ListDirectoryUsers(ctx context.Context, options PageOptions) ([]DirectoryUser, string, annotations.Annotations, error) {
+	var res DirectoryUsersResponse
+
+	path := fmt.Sprintf(directoryUsersPath, url.PathEscape(c.organizationID))
+	annotation, err := c.doAdminRequest(ctx, http.MethodGet, path, pageQuery(options), &res, nil)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res.Data, res.Links.Next, annotation, nil
+}
+
+// ListProductRoleUsers returns a page of the users holding a role on a
+// product instance.
+func (c *AtlassianClient) ListProductRoleUsers(ctx context.Context, workspaceID, roleID string, options PageOptions) ([]DirectoryUser, string, annotations.Annotations, error) {
+	var res DirectoryUsersResponse
+
+	query := pageQuery(options)
+	query.Set("resourceIds", workspaceID)
+	query.Set("roleIds", roleID)
+
+	path := fmt.Sprintf(directoryUsersPath, url.PathEscape(c.organizationID))
+	annotation, err := c.doAdminRequest(ctx, http.MethodGet, path, query, &res, nil, conditional(options)...)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res.Data, res.Links.Next, annotation, nil
+}
+
+// HasProductRole reports whether the account holds a role on a product
+// instance.
+func (c *AtlassianClient) HasProductRole(ctx context.Context, workspaceID, roleID, accountID string) (bool, annotations.Annotations, error) {
+	var res DirectoryUsersResponse
+
+	query := url.Values{}
+	query.Set("accountIds", accountID)
+	query.Set("resourceIds", workspaceID)
+	query.Set("roleIds", roleID)
+
+	path := fmt.Sprintf(directoryUsersPath, url.PathEscape(c.organizationID))
+	annotation, err := c.doAdminRequest(ctx, http.MethodGet, path, query, &res, nil)
+	if err != nil {
+		return false, annotation, err
+	}
+
+	for _, user := range res.Data {
+		if user.AccountID == accountID {
+			return true, annotation, nil
+		}
+	}
+
+	return false, annotation, nil
+}
+
+// AssignProductRole grants a role on a product instance to the account.
+// Granting the user role is refused with ErrLicenseLimitReached when the
+// product has no seats left, so callers check HasProductRole first.
+func (c *AtlassianClient) AssignProductRole(ctx context.Context, workspaceID, roleID, accountID string) (annotations.Annotations, error) {
+	if roleID == ProductRoleUser {
+		_, annotation, err := c.CheckSeatAvailable(ctx, workspaceID)
+		if err != nil {
+			return annotation, err
+		}
+	}
+
+	path := fmt.Sprintf(roleAssignPath, url.PathEscape(c.organizationID), url.PathEscape(accountID))
+	return c.doAdminRequest(ctx, http.MethodPost, path, nil, nil, roleAssignmentBody{
+		ResourceID: workspaceID,
+		RoleID:     roleID,
+	})
+}
+
+// RevokeProductRole removes a role on a product instance from the account.
+func (c *AtlassianClient) RevokeProductRole(ctx context.Context, workspaceID, roleID, accountID string) (annotations.Annotations, error) {
+	path := fmt.Sprintf(roleRevokePath, url.PathEscape(c.organizationID), url.PathEscape(accountID))
+	return c.doAdminRequest(ctx, http.MethodPost, path, nil, nil, roleAssignmentBody{
+		ResourceID: workspaceID,
+		RoleID:     roleID,
+	})
+}
+
+// CheckSeatAvailable returns the product instance, or ErrLicenseLimitReached
+// if all of its licensed seats are in use.
+func (c *AtlassianClient) CheckSeatAvailable(ctx context.Context, workspaceID string) (*Workspace, annotations.Annotations, error) {
+	workspace, annotation, err := c.GetWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	if workspace.SeatsExhausted() {
+		return nil, annotation, fmt.Errorf(
+			"%w: %s on %s uses %d of %d seats",
+			ErrLicenseLimitReached,
+			workspace.Attributes.Name,
+			workspace.Attributes.HostURL,
+			workspace.Attributes.Usage,
+			workspace.Attributes.Capacity,
+		)
+	}
+
+	return workspace, annotation, nil
+}
@@ -0,0 +1,243 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/ratelimit"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+// requester sends the HTTP requests of a client, sharing one token bucket and retry policy between every
+// request of that client.
+type requester struct {
+	wrapper     *uhttp.BaseHttpClient
+	limiter     rateLimiter
+	retryPolicy RetryPolicy
+}
+
+// newRequester returns a requester with the default rate limit and retry policy.
+func newRequester(wrapper *uhttp.BaseHttpClient) requester {
+	return requester{
+		wrapper:     wrapper,
+		limiter:     newTokenBucket(defaultRequestsPerSecond, defaultBurst),
+		retryPolicy: DefaultRetryPolicy,
+	}
+}
+
+// DisableRateLimit sends requests without waiting on the token bucket, for servers that are not rate limited
+// such as test servers. Throttled requests are still retried.
+func (r *requester) DisableRateLimit() {
+	r.limiter = noRateLimit{}
+}
+
+// doRequest sends the request, waiting on the shared token bucket before every attempt and
+// retrying throttled (429) requests, and failed (5xx) and interrupted idempotent requests, within the
+// RetryPolicy.
+func (r *requester) doRequest(
+	ctx context.Context,
+	method string,
+	urlAddress *url.URL,
+	res interface{},
+	body interface{},
+	options ...uhttp.RequestOption,
+) (http.Header, annotations.Annotations, error) {
+	return r.doAttempts(ctx, method, idempotent(method), urlAddress, res, body, options...)
+}
+
+// doReadRequest is doRequest for requests that only read whatever their method, such as GraphQL queries
+// and searches sent as POST requests, which are retried after failed and interrupted attempts too.
+func (r *requester) doReadRequest(
+	ctx context.Context,
+	method string,
+	urlAddress *url.URL,
+	res interface{},
+	body interface{},
+	options ...uhttp.RequestOption,
+) (http.Header, annotations.Annotations, error) {
+	return r.doAttempts(ctx, method, true, urlAddress, res, body, options...)
+}
+
+// doAttempts sends the request until it succeeds or the RetryPolicy gives up. Failed and interrupted
+// attempts are only retried when retrySafe is set.
+func (r *requester) doAttempts(
+	ctx context.Context,
+	method string,
+	retrySafe bool,
+	urlAddress *url.URL,
+	res interface{},
+	body interface{},
+	options ...uhttp.RequestOption,
+) (http.Header, annotations.Annotations, error) {
+	var (
+		resp      *http.Response
+		err       error
+		totalWait time.Duration
+	)
+	l := ctxzap.Extract(ctx)
+
+	reqOptions := []uhttp.RequestOption{uhttp.WithContentTypeJSONHeader()}
+	reqOptions = append(reqOptions, options...)
+	if body != nil {
+		reqOptions = append(reqOptions, uhttp.WithJSONBody(body))
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := r.limiter.Wait(ctx); err != nil {
+			return nil, nil, err
+		}
+
+		var req *http.Request
+		req, err = r.wrapper.NewRequest(ctx, method, urlAddress, reqOptions...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		resp, err = r.send(req, res)
+		if resp != nil {
+			r.throttle(resp)
+		}
+
+		delay, retryable := r.retryPolicy.retryDelay(retrySafe, resp, err, attempt)
+		if !retryable || attempt+1 >= r.retryPolicy.MaxAttempts || totalWait+delay > r.retryPolicy.MaxTotalWait {
+			break
+		}
+
+		l.Debug(
+			"retrying Atlassian request",
+			zap.String("method", method),
+			zap.String("url", urlAddress.String()),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("delay", delay),
+			zap.Error(err),
+		)
+		if err := sleep(ctx, delay); err != nil {
+			return nil, nil, err
+		}
+		totalWait += delay
+	}
+
+	annotation := annotations.Annotations{}
+	if resp != nil {
+		if desc, rlErr := ratelimit.ExtractRateLimitData(resp.StatusCode, &resp.Header); rlErr == nil {
+			annotation.WithRateLimiting(desc)
+		} else if err == nil {
+			return nil, annotation, rlErr
+		}
+	}
+
+	if resp != nil && resp.StatusCode == http.StatusNotModified {
+		return resp.Header, annotation, ErrNotModified
+	}
+
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	if resp != nil {
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			annotation.Update(&v2.ETag{Value: etag})
+		}
+		return resp.Header, annotation, nil
+	}
+
+	return nil, nil, nil
+}
+
+// send sends the request through the HTTP client of the wrapper, bypassing the uhttp response cache:
+// BaseHttpClient.Do serves repeated GETs from it for an hour by default, so lookups made to provision a grant
+// would read what they returned before the previous grant or revoke. Like BaseHttpClient.Do, it decodes the
+// response into res and maps failed statuses to gRPC codes carrying the rate limit of the response.
+func (r *requester) send(req *http.Request, res interface{}) (*http.Response, error) {
+	httpClient := r.wrapper.HttpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) && urlErr.Timeout() {
+			return nil, uhttp.WrapErrors(codes.DeadlineExceeded, fmt.Sprintf("request timeout: %v", urlErr.URL), urlErr)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return resp, uhttp.WrapErrors(codes.Unavailable, "error reading response body", err)
+	}
+
+	var errs []error
+	if res != nil {
+		wrapped := &uhttp.WrapperResponse{
+			Header:     resp.Header,
+			Status:     resp.Status,
+			StatusCode: resp.StatusCode,
+			Body:       body,
+		}
+		if err := uhttp.WithResponse(&res)(wrapped); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	switch code := statusCode(resp.StatusCode); {
+	case code != codes.OK:
+		return resp, uhttp.WrapErrorsWithRateLimitInfo(code, resp, errs...)
+	case resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices:
+		errs = append(errs, fmt.Errorf("unexpected status code: %d", resp.StatusCode))
+		return resp, uhttp.WrapErrorsWithRateLimitInfo(codes.Unknown, resp, errs...)
+	}
+
+	return resp, errors.Join(errs...)
+}
+
+// statusCode returns the gRPC code BaseHttpClient.Do maps the HTTP status to, or OK for the statuses it does
+// not map.
+func statusCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusRequestTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusTooManyRequests:
+		return codes.Unavailable
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	}
+	if httpStatus >= http.StatusInternalServerError && httpStatus <= 599 {
+		return codes.Unavailable
+	}
+	return codes.OK
+}
+
+// throttle pauses the shared token bucket when a response asks clients to back off, so every syncer
+// waits instead of only the request that was throttled.
+func (r *requester) throttle(resp *http.Response) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.Header.Get(betaRetryAfterHeader) == "" {
+		return
+	}
+
+	if delay, ok := throttleDelay(resp.Header, time.Now()); ok {
+		r.limiter.PauseUntil(time.Now().Add(delay))
+	}
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+)
+
+// restClient calls a JSON REST API at baseURL, authenticating every request with auth. It backs the clients
+// of the products that are not reached through the cloud GraphQL gateway or Admin API.
+type restClient struct {
+	requester
+	auth    Authenticator
+	baseURL string
+}
+
+func newRESTClient(baseURL string, auth Authenticator, wrapper *uhttp.BaseHttpClient) restClient {
+	return restClient{
+		requester: newRequester(wrapper),
+		auth:      auth,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// newHTTPWrapper returns the logging HTTP client requests are made with.
+func newHTTPWrapper(ctx context.Context) (*uhttp.BaseHttpClient, error) {
+	httpClient, err := uhttp.NewClient(ctx, uhttp.WithLogger(true, ctxzap.Extract(ctx)))
+	if err != nil {
+		return nil, err
+	}
+
+	return uhttp.NewBaseHttpClientWithContext(context.Background(), httpClient)
+}
+
+// BaseURL returns the URL of the API.
+func (c *restClient) BaseURL() string {
+	return c.baseURL
+}
+
+func (c *restClient) do(
+	ctx context.Context,
+	method string,
+	path string,
+	query url.Values,
+	res interface{},
+	body interface{},
+) (annotations.Annotations, error) {
+	return c.call(ctx, c.doRequest, method, path, query, res, body)
+}
+
+// doRead is do for requests that only read whatever their method, which are retried after failures.
+func (c *restClient) doRead(
+	ctx context.Context,
+	method string,
+	path string,
+	query url.Values,
+	res interface{},
+	body interface{},
+) (annotations.Annotations, error) {
+	return c.call(ctx, c.doReadRequest, method, path, query, res, body)
+}
+
+func (c *restClient) call(
+	ctx context.Context,
+	doRequest func(context.Context, string, *url.URL, interface{}, interface{}, ...uhttp.RequestOption) (http.Header, annotations.Annotations, error),
+	method string,
+	path string,
+	query url.Values,
+	res interface{},
+	body interface{},
+) (annotations.Annotations, error) {
+	authorization, err := c.auth.Authorization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	urlAddress, err := url.Parse(c.baseURL + path)
+	if err != nil {
+		return nil, err
+	}
+	urlAddress.RawQuery = query.Encode()
+
+	_, annotation, err := doRequest(
+		ctx,
+		method,
+		urlAddress,
+		res,
+		body,
+		uhttp.WithAcceptJSONHeader(),
+		uhttp.WithHeader("Authorization", authorization),
+	)
+	return annotation, err
+}
+
+func (c *restClient) get(ctx context.Context, path string, query url.Values, res interface{}) (annotations.Annotations, error) {
+	return c.do(ctx, http.MethodGet, path, query, res, nil)
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy controls how throttled and failed requests are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	// BaseDelay is the initial backoff delay, doubled on every attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps a single backoff delay.
+	MaxDelay time.Duration
+	// MaxTotalWait caps the time a single request spends waiting between
+	// attempts. A retry that would exceed it is not attempted.
+	MaxTotalWait time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:  8,
+	BaseDelay:    500 * time.Millisecond,
+	MaxDelay:     30 * time.Second,
+	MaxTotalWait: 2 * time.Minute,
+}
+
+const (
+	retryAfterHeader     = "Retry-After"
+	betaRetryAfterHeader = "Beta-Retry-After"
+	rateLimitReset       = "X-RateLimit-Reset"
+	rateLimitRemaining   = "X-RateLimit-Remaining"
+)
+
+// retryDelay returns how long to wait before retrying a request that ended
+// with the given response and error, and whether it should be retried at all.
+// Throttled requests were not processed and are always retried, while failed
+// and interrupted requests may have been applied and are only retried when
+// retrySafe is set, for idempotent methods and requests that only read.
+func (p RetryPolicy) retryDelay(retrySafe bool, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if err == nil {
+		return 0, false
+	}
+	if errors.Is(err, context.Canceled) {
+		return 0, false
+	}
+
+	if resp != nil {
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests:
+		case http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			if !retrySafe {
+				return 0, false
+			}
+		default:
+			return 0, false
+		}
+
+		if delay, ok := throttleDelay(resp.Header, time.Now()); ok {
+			return delay, true
+		}
+		return p.backoff(attempt), true
+	}
+
+	if !retrySafe {
+		return 0, false
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr):
+	case status.Code(err) == codes.Unavailable, status.Code(err) == codes.DeadlineExceeded:
+	default:
+		return 0, false
+	}
+
+	return p.backoff(attempt), true
+}
+
+// idempotent reports whether sending a request with the method twice has the
+// same effect as sending it once.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns a full-jitter exponential backoff delay for the attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.MaxDelay
+	if attempt < 32 {
+		if d := p.BaseDelay << attempt; d > 0 && d < p.MaxDelay {
+			delay = d
+		}
+	}
+
+	//nolint:gosec // Jitter does not need a cryptographically secure source.
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+// throttleDelay reads the delay the Atlassian APIs ask clients to wait from
+// the Retry-After, Beta-Retry-After and X-RateLimit-* headers.
+func throttleDelay(header http.Header, now time.Time) (time.Duration, bool) {
+	for _, name := range []string{retryAfterHeader, betaRetryAfterHeader} {
+		value := header.Get(name)
+		if value == "" {
+			continue
+		}
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+			return time.Duration(seconds * float64(time.Second)), true
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return nonNegative(at.Sub(now)), true
+		}
+	}
+
+	if header.Get(rateLimitRemaining) == "0" {
+		if at, err := time.Parse(time.RFC3339, header.Get(rateLimitReset)); err == nil {
+			return nonNegative(at.Sub(now)), true
+		}
+	}
+
+	return 0, false
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,183 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+func TestDoRequest_RetriesThrottledRequests(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "application/json")
+		switch attempts {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write([]byte(`{"id": "group-1", "name": "engineering"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("", "", "admin-key", Endpoints{Admin: server.URL}, "org", "", uhttp.NewBaseHttpClient(server.Client()))
+	client.retryPolicy = RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, MaxTotalWait: time.Second}
+
+	group, _, err := client.GetGroup(context.Background(), "group-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if group.Name != "engineering" {
+		t.Errorf("Unexpected group %+v", group)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestDoRequest_DoesNotRetryClientErrors(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewClient("", "", "admin-key", Endpoints{Admin: server.URL}, "org", "", uhttp.NewBaseHttpClient(server.Client()))
+	client.retryPolicy = RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, MaxTotalWait: time.Second}
+
+	_, _, err := client.GetGroup(context.Background(), "group-1")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestDoRequest_RetriesOnlyThrottledNonIdempotentRequests(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch attempts {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("", "", "admin-key", Endpoints{Admin: server.URL}, "org", "", uhttp.NewBaseHttpClient(server.Client()))
+	client.retryPolicy = RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, MaxTotalWait: time.Second}
+
+	_, err := client.AssignProductRole(context.Background(), "workspace-1", ProductRoleAdmin, "account-1")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if attempts != 2 {
+		t.Errorf("Expected the throttled POST to be retried once and the failed one not at all, got %d attempts", attempts)
+	}
+}
+
+func TestDoRequest_RetriesFailedQueries(t *testing.T) {
+	var queries, mutations int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.OperationName == "DeleteTeam" {
+			mutations++
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		queries++
+		if queries == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"team": {"teamSearchV2": {"edges": []}}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("", "", "", Endpoints{GraphQL: server.URL}, "org", "", uhttp.NewBaseHttpClient(server.Client()))
+	client.retryPolicy = RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, MaxTotalWait: time.Second}
+
+	if _, _, _, err := client.ListTeams(context.Background(), PageOptions{}); err != nil {
+		t.Fatalf("Expected the failed query to be retried, got %v", err)
+	}
+	if queries != 2 {
+		t.Errorf("Expected 2 attempts of the query, got %d", queries)
+	}
+
+	if _, err := client.DeleteTeam(context.Background(), "team-1"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if mutations != 1 {
+		t.Errorf("Expected the failed mutation not to be retried, got %d attempts", mutations)
+	}
+}
+
+func TestDoRequest_CapsTotalWait(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient("", "", "admin-key", Endpoints{Admin: server.URL}, "org", "", uhttp.NewBaseHttpClient(server.Client()))
+	client.retryPolicy = RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, MaxTotalWait: time.Second}
+
+	_, _, err := client.GetGroup(context.Background(), "group-1")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected the retry to be skipped when it exceeds the total wait, got %d attempts", attempts)
+	}
+}
+
+func TestThrottleDelay(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		header   http.Header
+		expected time.Duration
+		ok       bool
+	}{
+		{"retry after seconds", http.Header{"Retry-After": {"5"}}, 5 * time.Second, true},
+		{"retry after date", http.Header{"Retry-After": {now.Add(time.Minute).Format(http.TimeFormat)}}, time.Minute, true},
+		{"beta retry after", http.Header{"Beta-Retry-After": {"2"}}, 2 * time.Second, true},
+		{
+			"rate limit reset",
+			http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {now.Add(10 * time.Second).Format(time.RFC3339)}},
+			10 * time.Second,
+			true,
+		},
+		{"no headers", http.Header{}, 0, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			delay, ok := throttleDelay(testCase.header, now)
+			if ok != testCase.ok || delay != testCase.expected {
+				t.Errorf("Expected %s (%t), got %s (%t)", testCase.expected, testCase.ok, delay, ok)
+			}
+		})
+	}
+}
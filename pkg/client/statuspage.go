@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+const (
+	// DefaultStatuspageURL is the Statuspage REST API.
+	DefaultStatuspageURL = "https://api.statuspage.io"
+
+	statuspagePagesPath            = "/v1/pages"
+	statuspageUsersPath            = "/v1/organizations/%s/users"
+	statuspagePermissionsPath      = "/v1/organizations/%s/permissions/%s"
+	statuspageAccessGroupsPath     = "/v1/pages/%s/page_access_groups"
+	statuspageAccessGroupPath      = "/v1/pages/%s/page_access_groups/%s"
+	statuspageAccessGroupUsersPath = "/v1/pages/%s/page_access_groups/%s/page_access_users"
+	statuspageAccessGroupUserPath  = "/v1/pages/%s/page_access_groups/%s/page_access_users/%s"
+	statuspageAccessUsersPath      = "/v1/pages/%s/page_access_users"
+
+	// statuspageMaxPageSize is the largest page Statuspage returns.
+	statuspageMaxPageSize = 100
+
+	StatuspageRoleOwner = "owner"
+	StatuspageRoleAdmin = "admin"
+	StatuspageRoleUser  = "user"
+)
+
+// StatuspageRoles are the roles of Statuspage team members, from highest to lowest.
+var StatuspageRoles = []string{StatuspageRoleOwner, StatuspageRoleAdmin, StatuspageRoleUser}
+
+// StatuspageClient calls the Statuspage REST API with an API key for the pages and team members of an
+// organization.
+type StatuspageClient struct {
+	restClient
+	organizationID string
+}
+
+type StatuspagePage struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Subdomain string `json:"subdomain"`
+	Domain    string `json:"domain"`
+	URL       string `json:"url"`
+}
+
+// StatuspageUser is a team member of the organization. Role is empty for accounts whose role is not
+// reported, which are plain users.
+type StatuspageUser struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+	Email          string `json:"email"`
+	FirstName      string `json:"first_name"`
+	LastName       string `json:"last_name"`
+	Role           string `json:"role"`
+	CreatedAt      string `json:"created_at"`
+}
+
+// StatuspagePagePermissions are the permissions of a team member on a page.
+type StatuspagePagePermissions struct {
+	PageID             string `json:"page_id"`
+	PageConfiguration  bool   `json:"page_configuration"`
+	IncidentManager    bool   `json:"incident_manager"`
+	MaintenanceManager bool   `json:"maintenance_manager"`
+}
+
+type StatuspagePermissions struct {
+	UserID string                      `json:"user_id"`
+	Pages  []StatuspagePagePermissions `json:"pages"`
+}
+
+// StatuspageAccessGroup is a group of the audience of an audience-specific page.
+type StatuspageAccessGroup struct {
+	ID                 string   `json:"id"`
+	PageID             string   `json:"page_id"`
+	Name               string   `json:"name"`
+	ExternalIdentifier string   `json:"external_identifier"`
+	PageAccessUserIDs  []string `json:"page_access_user_ids"`
+}
+
+// StatuspageAccessUser is a member of the audience of an audience-specific page.
+type StatuspageAccessUser struct {
+	ID                 string   `json:"id"`
+	PageID             string   `json:"page_id"`
+	Email              string   `json:"email"`
+	ExternalLogin      string   `json:"external_login"`
+	PageAccessGroupIDs []string `json:"page_access_group_ids"`
+	CreatedAt          string   `json:"created_at"`
+}
+
+// NewStatuspageClient returns a client for the Statuspage API at baseURL, or DefaultStatuspageURL if it is
+// empty, for the organization with the given ID.
+func NewStatuspageClient(ctx context.Context, baseURL, organizationID, apiKey string) (*StatuspageClient, error) {
+	wrapper, err := newHTTPWrapper(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStatuspageClientWithAuth(baseURL, organizationID, NewStatuspageAuth(apiKey), wrapper), nil
+}
+
+func NewStatuspageClientWithAuth(baseURL, organizationID string, auth Authenticator, wrapper *uhttp.BaseHttpClient) *StatuspageClient {
+	if baseURL == "" {
+		baseURL = DefaultStatuspageURL
+	}
+
+	return &StatuspageClient{
+		restClient:     newRESTClient(baseURL, auth, wrapper),
+		organizationID: organizationID,
+	}
+}
+
+// ListPages returns every page the API key can access.
+func (c *StatuspageClient) ListPages(ctx context.Context) ([]StatuspagePage, annotations.Annotations, error) {
+	var res []StatuspagePage
+
+	annotation, err := c.get(ctx, statuspagePagesPath, nil, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return res, annotation, nil
+}
+
+// ListUsers returns a page of the team members of the organization.
+func (c *StatuspageClient) ListUsers(ctx context.Context, options PageOptions) ([]StatuspageUser, string, annotations.Annotations, error) {
+	return listStatuspagePage[StatuspageUser](ctx, c, fmt.Sprintf(statuspageUsersPath, url.PathEscape(c.organizationID)), options)
+}
+
+// GetPermissions returns the pages the team member can access and its permissions on them.
+func (c *StatuspageClient) GetPermissions(ctx context.Context, userID string) (*StatuspagePermissions, annotations.Annotations, error) {
+	var res struct {
+		Data StatuspagePermissions `json:"data"`
+	}
+
+	path := fmt.Sprintf(statuspagePermissionsPath, url.PathEscape(c.organizationID), url.PathEscape(userID))
+	annotation, err := c.get(ctx, path, nil, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return &res.Data, annotation, nil
+}
+
+// CanAccess reports whether the permissions give access to the page.
+func (p *StatuspagePermissions) CanAccess(pageID string) bool {
+	return slices.ContainsFunc(p.Pages, func(page StatuspagePagePermissions) bool { return page.PageID == pageID })
+}
+
+// ListAccessGroups returns the page access groups of the page, with the IDs of their members.
+func (c *StatuspageClient) ListAccessGroups(ctx context.Context, pageID string) ([]StatuspageAccessGroup, annotations.Annotations, error) {
+	var res []StatuspageAccessGroup
+
+	annotation, err := c.get(ctx, fmt.Sprintf(statuspageAccessGroupsPath, url.PathEscape(pageID)), nil, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return res, annotation, nil
+}
+
+// GetAccessGroup returns the page access group of the page, with the IDs of its members.
+func (c *StatuspageClient) GetAccessGroup(ctx context.Context, pageID, groupID string) (*StatuspageAccessGroup, annotations.Annotations, error) {
+	var res StatuspageAccessGroup
+
+	annotation, err := c.get(ctx, fmt.Sprintf(statuspageAccessGroupPath, url.PathEscape(pageID), url.PathEscape(groupID)), nil, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return &res, annotation, nil
+}
+
+// ListAccessUsers returns a page of the page access users of the page.
+func (c *StatuspageClient) ListAccessUsers(ctx context.Context, pageID string, options PageOptions) ([]StatuspageAccessUser, string, annotations.Annotations, error) {
+	return listStatuspagePage[StatuspageAccessUser](ctx, c, fmt.Sprintf(statuspageAccessUsersPath, url.PathEscape(pageID)), options)
+}
+
+// AddAccessGroupUser adds the page access user to the page access group.
+func (c *StatuspageClient) AddAccessGroupUser(ctx context.Context, pageID, groupID, userID string) (annotations.Annotations, error) {
+	body := map[string][]string{
+		"page_access_user_ids": {userID},
+	}
+
+	path := fmt.Sprintf(statuspageAccessGroupUsersPath, url.PathEscape(pageID), url.PathEscape(groupID))
+	return c.do(ctx, http.MethodPut, path, nil, nil, body)
+}
+
+// RemoveAccessGroupUser removes the page access user from the page access group.
+func (c *StatuspageClient) RemoveAccessGroupUser(ctx context.Context, pageID, groupID, userID string) (annotations.Annotations, error) {
+	path := fmt.Sprintf(statuspageAccessGroupUserPath, url.PathEscape(pageID), url.PathEscape(groupID), url.PathEscape(userID))
+	return c.do(ctx, http.MethodDelete, path, nil, nil, nil)
+}
+
+// listStatuspagePage returns a page of a listing paged with page, starting at 1, and per_page.
+func listStatuspagePage[T any](ctx context.Context, c *StatuspageClient, path string, options PageOptions) ([]T, string, annotations.Annotations, error) {
+	var res []T
+
+	page := 1
+	if options.PageToken != "" {
+		var err error
+		page, err = strconv.Atoi(options.PageToken)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+	perPage := min(getPageSize(options.PageSize), statuspageMaxPageSize)
+
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(perPage))
+
+	annotation, err := c.get(ctx, path, query, &res)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	if len(res) < perPage {
+		return res, "", annotation, nil
+	}
+	return res, strconv.Itoa(page + 1), annotation, nil
+}
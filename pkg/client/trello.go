@@ -0,0 +1,219 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+const (
+	// DefaultTrelloURL is the Trello REST API.
+	DefaultTrelloURL = "https://api.trello.com"
+
+	trelloEnterpriseMembersPath     = "/1/enterprises/%s/members"
+	trelloEnterpriseMemberPath      = "/1/enterprises/%s/members/%s/deactivated"
+	trelloEnterpriseWorkspacesPath  = "/1/enterprises/%s/organizations"
+	trelloWorkspaceMembershipsPath  = "/1/organizations/%s/memberships"
+	trelloWorkspaceMemberPath       = "/1/organizations/%s/members/%s"
+	trelloWorkspaceBoardsPath       = "/1/organizations/%s/boards"
+	trelloBoardMembershipsPath      = "/1/boards/%s/memberships"
+	trelloEnterpriseMemberFields    = "id,username,fullName,email,idEnterprisesDeactivated,dateLastAccessed"
+	trelloLicensedEnterpriseFilter  = "licensed eq true"
+	trelloEnterpriseMembersMaxCount = 100
+
+	TrelloMemberTypeAdmin    = "admin"
+	TrelloMemberTypeNormal   = "normal"
+	TrelloMemberTypeObserver = "observer"
+)
+
+// TrelloClient calls the Trello REST API for the workspaces and boards of a Trello Enterprise.
+type TrelloClient struct {
+	restClient
+	enterpriseID string
+}
+
+type TrelloMember struct {
+	ID                       string   `json:"id"`
+	Username                 string   `json:"username"`
+	FullName                 string   `json:"fullName"`
+	Email                    string   `json:"email"`
+	IDEnterprisesDeactivated []string `json:"idEnterprisesDeactivated"`
+	DateLastAccessed         string   `json:"dateLastAccessed"`
+}
+
+type TrelloWorkspace struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Desc        string `json:"desc"`
+}
+
+type TrelloBoard struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Desc           string `json:"desc"`
+	Closed         bool   `json:"closed"`
+	IDOrganization string `json:"idOrganization"`
+}
+
+// TrelloMembership is the membership of a member in a workspace or board, of type TrelloMemberTypeAdmin,
+// TrelloMemberTypeNormal or, on boards, TrelloMemberTypeObserver.
+type TrelloMembership struct {
+	ID          string `json:"id"`
+	IDMember    string `json:"idMember"`
+	MemberType  string `json:"memberType"`
+	Unconfirmed bool   `json:"unconfirmed"`
+	Deactivated bool   `json:"deactivated"`
+}
+
+// NewTrelloClient returns a client for the Trello API at baseURL, or DefaultTrelloURL if it is empty, for the
+// enterprise with the given ID.
+func NewTrelloClient(ctx context.Context, baseURL, enterpriseID, apiKey, token string) (*TrelloClient, error) {
+	wrapper, err := newHTTPWrapper(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTrelloClientWithAuth(baseURL, enterpriseID, NewTrelloAuth(apiKey, token), wrapper), nil
+}
+
+func NewTrelloClientWithAuth(baseURL, enterpriseID string, auth Authenticator, wrapper *uhttp.BaseHttpClient) *TrelloClient {
+	if baseURL == "" {
+		baseURL = DefaultTrelloURL
+	}
+
+	return &TrelloClient{
+		restClient:   newRESTClient(baseURL, auth, wrapper),
+		enterpriseID: enterpriseID,
+	}
+}
+
+// EnterpriseID returns the ID of the enterprise the client syncs.
+func (c *TrelloClient) EnterpriseID() string {
+	return c.enterpriseID
+}
+
+// Deactivated reports whether the member is deactivated in the enterprise.
+func (c *TrelloClient) Deactivated(member *TrelloMember) bool {
+	return slices.Contains(member.IDEnterprisesDeactivated, c.enterpriseID)
+}
+
+// ListEnterpriseMembers returns a page of the members of the enterprise.
+func (c *TrelloClient) ListEnterpriseMembers(ctx context.Context, options PageOptions) ([]TrelloMember, string, annotations.Annotations, error) {
+	return c.listEnterpriseMembers(ctx, "", options)
+}
+
+// ListLicensedEnterpriseMembers returns a page of the members holding a license of the enterprise.
+func (c *TrelloClient) ListLicensedEnterpriseMembers(ctx context.Context, options PageOptions) ([]TrelloMember, string, annotations.Annotations, error) {
+	return c.listEnterpriseMembers(ctx, trelloLicensedEnterpriseFilter, options)
+}
+
+// DeactivateEnterpriseMember deactivates the member in the enterprise, freeing its license.
+func (c *TrelloClient) DeactivateEnterpriseMember(ctx context.Context, memberID string) (annotations.Annotations, error) {
+	query := url.Values{}
+	query.Set("value", "true")
+
+	path := fmt.Sprintf(trelloEnterpriseMemberPath, url.PathEscape(c.enterpriseID), url.PathEscape(memberID))
+	return c.do(ctx, http.MethodPut, path, query, nil, nil)
+}
+
+// ListWorkspaces returns every workspace of the enterprise.
+func (c *TrelloClient) ListWorkspaces(ctx context.Context) ([]TrelloWorkspace, annotations.Annotations, error) {
+	var res []TrelloWorkspace
+
+	annotation, err := c.get(ctx, fmt.Sprintf(trelloEnterpriseWorkspacesPath, url.PathEscape(c.enterpriseID)), nil, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return res, annotation, nil
+}
+
+// ListWorkspaceMemberships returns the memberships of the workspace.
+func (c *TrelloClient) ListWorkspaceMemberships(ctx context.Context, workspaceID string) ([]TrelloMembership, annotations.Annotations, error) {
+	return c.listMemberships(ctx, fmt.Sprintf(trelloWorkspaceMembershipsPath, url.PathEscape(workspaceID)))
+}
+
+// SetWorkspaceMember adds the member to the workspace, or changes its membership, with the given type.
+func (c *TrelloClient) SetWorkspaceMember(ctx context.Context, workspaceID, memberID, memberType string) (annotations.Annotations, error) {
+	query := url.Values{}
+	query.Set("type", memberType)
+
+	path := fmt.Sprintf(trelloWorkspaceMemberPath, url.PathEscape(workspaceID), url.PathEscape(memberID))
+	return c.do(ctx, http.MethodPut, path, query, nil, nil)
+}
+
+// RemoveWorkspaceMember removes the member from the workspace.
+func (c *TrelloClient) RemoveWorkspaceMember(ctx context.Context, workspaceID, memberID string) (annotations.Annotations, error) {
+	path := fmt.Sprintf(trelloWorkspaceMemberPath, url.PathEscape(workspaceID), url.PathEscape(memberID))
+	return c.do(ctx, http.MethodDelete, path, nil, nil, nil)
+}
+
+// ListWorkspaceBoards returns the boards of the workspace, including closed ones.
+func (c *TrelloClient) ListWorkspaceBoards(ctx context.Context, workspaceID string) ([]TrelloBoard, annotations.Annotations, error) {
+	var res []TrelloBoard
+
+	query := url.Values{}
+	query.Set("filter", "all")
+	query.Set("fields", "id,name,desc,closed,idOrganization")
+
+	annotation, err := c.get(ctx, fmt.Sprintf(trelloWorkspaceBoardsPath, url.PathEscape(workspaceID)), query, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return res, annotation, nil
+}
+
+// ListBoardMemberships returns the memberships of the board.
+func (c *TrelloClient) ListBoardMemberships(ctx context.Context, boardID string) ([]TrelloMembership, annotations.Annotations, error) {
+	return c.listMemberships(ctx, fmt.Sprintf(trelloBoardMembershipsPath, url.PathEscape(boardID)))
+}
+
+func (c *TrelloClient) listMemberships(ctx context.Context, path string) ([]TrelloMembership, annotations.Annotations, error) {
+	var res []TrelloMembership
+
+	annotation, err := c.get(ctx, path, nil, &res)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return res, annotation, nil
+}
+
+// listEnterpriseMembers returns a page of the members of the enterprise matching the SCIM-style filter, if
+// any. Pages start at index 1.
+func (c *TrelloClient) listEnterpriseMembers(ctx context.Context, filter string, options PageOptions) ([]TrelloMember, string, annotations.Annotations, error) {
+	var res []TrelloMember
+
+	startIndex := 1
+	if options.PageToken != "" {
+		var err error
+		startIndex, err = strconv.Atoi(options.PageToken)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+	count := min(getPageSize(options.PageSize), trelloEnterpriseMembersMaxCount)
+
+	query := url.Values{}
+	query.Set("fields", trelloEnterpriseMemberFields)
+	query.Set("startIndex", strconv.Itoa(startIndex))
+	query.Set("count", strconv.Itoa(count))
+	if filter != "" {
+		query.Set("filter", filter)
+	}
+
+	annotation, err := c.get(ctx, fmt.Sprintf(trelloEnterpriseMembersPath, url.PathEscape(c.enterpriseID)), query, &res)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return res, nextStartAt(startIndex, len(res), len(res) < count), annotation, nil
+}
@@ -0,0 +1,253 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// apiKeyBuilder reports the Admin API keys of the organization, created by its admins.
+type apiKeyBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.AtlassianClient
+}
+
+func (o *apiKeyBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return apiKeyResourceType
+}
+
+func (o *apiKeyBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, apiKeyResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	keys, nextPageToken, annotation, err := o.client.ListAPIKeys(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, key := range keys {
+		keyCopy := key
+		keyResource, err := parseIntoAPIKeyResource(ctx, &keyCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, keyResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+// Entitlements always returns an empty slice for API keys.
+func (o *apiKeyBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for API keys since they don't have any entitlements.
+func (o *apiKeyBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func newAPIKeyBuilder(c *client.AtlassianClient) *apiKeyBuilder {
+	return &apiKeyBuilder{
+		resourceType: apiKeyResourceType,
+		client:       c,
+	}
+}
+
+// apiTokenBuilder reports the personal API tokens of managed accounts, as children of the user, and revokes
+// them.
+type apiTokenBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.AtlassianClient
+}
+
+func (o *apiTokenBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return apiTokenResourceType
+}
+
+// List returns the API tokens of the parent user. Only the accounts the organization manages have tokens
+// visible to it; the others are answered with a not found or permission denied error and have none.
+func (o *apiTokenBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	accountID := accountIDFromUserResourceID(parentResourceID.Resource)
+	tokens, annotation, err := o.client.ListUserAPITokens(ctx, accountID)
+	if err != nil {
+		if code := status.Code(err); code == codes.NotFound || code == codes.PermissionDenied {
+			return nil, "", annotation, nil
+		}
+		return nil, "", annotation, err
+	}
+
+	for _, token := range tokens {
+		tokenCopy := token
+		tokenResource, err := parseIntoAPITokenResource(ctx, &tokenCopy, parentResourceID)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, tokenResource)
+	}
+
+	return resources, "", annotation, nil
+}
+
+// Entitlements always returns an empty slice for API tokens.
+func (o *apiTokenBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for API tokens since they don't have any entitlements.
+func (o *apiTokenBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Create always fails: API tokens can only be created by their owner. It is implemented so that the SDK
+// routes token revocation to Delete.
+func (o *apiTokenBuilder) Create(_ context.Context, _ *v2.Resource) (*v2.Resource, annotations.Annotations, error) {
+	return nil, nil, status.Error(codes.Unimplemented, "baton-atlassian: api tokens can only be created by their owner")
+}
+
+// Delete revokes the API token, so that it can no longer be used to act as the user. Revoking a token that
+// no longer exists succeeds.
+func (o *apiTokenBuilder) Delete(ctx context.Context, resourceId *v2.ResourceId) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if resourceId.ResourceType != apiTokenResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: non-api-token resource passed to api token delete: %s", resourceId.ResourceType)
+	}
+
+	accountID, tokenID, err := parseAPITokenResourceID(resourceId.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	annotation, err := o.client.RevokeUserAPIToken(ctx, accountID, tokenID)
+	if status.Code(err) == codes.NotFound {
+		l.Debug("api token already revoked", zap.String("account_id", accountID), zap.String("token_id", tokenID))
+		return annotation, nil
+	}
+	if err != nil {
+		l.Error(
+			"failed to revoke api token",
+			zap.String("account_id", accountID),
+			zap.String("token_id", tokenID),
+			zap.Error(err),
+		)
+		return annotation, err
+	}
+
+	return annotation, nil
+}
+
+func newAPITokenBuilder(c *client.AtlassianClient) *apiTokenBuilder {
+	return &apiTokenBuilder{
+		resourceType: apiTokenResourceType,
+		client:       c,
+	}
+}
+
+// parseIntoAPIKeyResource returns the API key, created by the admin with the account ID in CreatedBy, with
+// the scopes it was granted as description.
+func parseIntoAPIKeyResource(_ context.Context, key *client.APIKey, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	attributes := key.Attributes
+
+	var secretTraits []resource.SecretTraitOption
+	if attributes.CreatedBy != "" {
+		secretTraits = append(secretTraits, resource.WithSecretCreatedByID(&v2.ResourceId{
+			ResourceType: userResourceType.Id,
+			Resource:     userResourceID(attributes.CreatedBy),
+		}))
+	}
+	if !attributes.CreatedAt.IsZero() {
+		secretTraits = append(secretTraits, resource.WithSecretCreatedAt(attributes.CreatedAt))
+	}
+	if !attributes.ExpiresAt.IsZero() {
+		secretTraits = append(secretTraits, resource.WithSecretExpiresAt(attributes.ExpiresAt))
+	}
+
+	ret, err := resource.NewSecretResource(
+		attributes.Name,
+		apiKeyResourceType,
+		key.ID,
+		secretTraits,
+		resource.WithParentResourceID(parentResourceID),
+		resource.WithDescription(strings.Join(attributes.Scopes, ", ")),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// parseIntoAPITokenResource returns the API token, owned and created by its parent user. Token IDs are only
+// unique per account, so the resource ID holds both.
+func parseIntoAPITokenResource(_ context.Context, token *client.UserAPIToken, userID *v2.ResourceId) (*v2.Resource, error) {
+	secretTraits := []resource.SecretTraitOption{
+		resource.WithSecretIdentityID(userID),
+		resource.WithSecretCreatedByID(userID),
+	}
+	if !token.CreatedAt.IsZero() {
+		secretTraits = append(secretTraits, resource.WithSecretCreatedAt(token.CreatedAt))
+	}
+	if !token.LastAccess.IsZero() {
+		secretTraits = append(secretTraits, resource.WithSecretLastUsedAt(token.LastAccess))
+	}
+
+	ret, err := resource.NewSecretResource(
+		token.Label,
+		apiTokenResourceType,
+		apiTokenResourceID(accountIDFromUserResourceID(userID.Resource), token.ID),
+		secretTraits,
+		resource.WithParentResourceID(userID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func apiTokenResourceID(accountID, tokenID string) string {
+	return fmt.Sprintf("%s/%s", accountID, tokenID)
+}
+
+func parseAPITokenResourceID(resourceID string) (string, string, error) {
+	accountID, tokenID, ok := strings.Cut(resourceID, "/")
+	if !ok || accountID == "" || tokenID == "" {
+		return "", "", fmt.Errorf("baton-atlassian: invalid api token ID %q", resourceID)
+	}
+
+	return accountID, tokenID, nil
+}
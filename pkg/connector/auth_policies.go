@@ -0,0 +1,294 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const authPolicyMemberEntitlement = "member"
+
+// authPolicyBuilder reports the authentication policies of the organization, whose member entitlement is held
+// by the managed accounts the policy applies to.
+type authPolicyBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.AtlassianClient
+	membership   *authPolicyMembership
+}
+
+func (o *authPolicyBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return authPolicyResourceType
+}
+
+func (o *authPolicyBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, authPolicyResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	policies, nextPageToken, annotation, err := o.client.ListAuthPolicies(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, policy := range policies {
+		policyCopy := policy
+		policyResource, err := parseIntoAuthPolicyResource(ctx, &policyCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, policyResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+func (o *authPolicyBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	assigmentOptions := []entitlement.EntitlementOption{
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDescription(fmt.Sprintf("Member of the %s authentication policy", resource.DisplayName)),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Policy Member", resource.DisplayName)),
+	}
+
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(resource, authPolicyMemberEntitlement, assigmentOptions...),
+	}, "", nil, nil
+}
+
+// Grants returns a grant to every managed account the policy applies to, from the policy membership the user
+// listing loaded.
+func (o *authPolicyBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+
+	bag, offset, err := getOffsetToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	accountIDs, next, err := o.membership.Members(ctx, resource.Id.Resource, offset, getPageSize(pToken))
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, accountID := range accountIDs {
+		principalID := &v2.ResourceId{
+			ResourceType: userResourceType.Id,
+			Resource:     userResourceID(accountID),
+		}
+		grants = append(grants, grant.NewGrant(resource, authPolicyMemberEntitlement, principalID))
+	}
+
+	nextPageToken, err := marshalOffsetToken(bag, next)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return grants, nextPageToken, nil, nil
+}
+
+func newAuthPolicyBuilder(c *client.AtlassianClient, membership *authPolicyMembership) *authPolicyBuilder {
+	return &authPolicyBuilder{
+		resourceType: authPolicyResourceType,
+		client:       c,
+		membership:   membership,
+	}
+}
+
+func parseIntoAuthPolicyResource(_ context.Context, policy *client.AuthPolicy, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	settings := policy.Attributes.Settings
+	profile := map[string]interface{}{
+		"policy_id":                      policy.ID,
+		"name":                           policy.Attributes.Name,
+		"status":                         policy.Attributes.Status,
+		"is_default":                     policy.Attributes.IsDefault,
+		"sso_enforced":                   settings.SSOEnforced,
+		"two_step_verification_enforced": settings.TwoStepVerificationEnforced,
+		"session_timeout_minutes":        settings.SessionTimeoutMinutes,
+	}
+
+	groupTraits := []resource.GroupTraitOption{
+		resource.WithGroupProfile(profile),
+	}
+
+	ret, err := resource.NewGroupResource(
+		policy.Attributes.Name,
+		authPolicyResourceType,
+		policy.ID,
+		groupTraits,
+		resource.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// authPolicyMembership maps managed accounts to the authentication policy applying to them, so users can
+// report whether SSO and two-step verification are enforced on them, and policies list their members without
+// fetching them again. It is loaded in full the first time it is needed and kept for the rest of the sync;
+// Reset clears it before the next one. A nil authPolicyMembership, for clients without Admin API access, knows
+// no policy.
+type authPolicyMembership struct {
+	client *client.AtlassianClient
+
+	mtx       sync.Mutex
+	loaded    bool
+	failed    bool
+	byAccount map[string]client.AuthPolicy
+	byPolicy  map[string][]string
+}
+
+func newAuthPolicyMembership(c *client.AtlassianClient) *authPolicyMembership {
+	if c == nil || !c.HasAdminAccess() {
+		return nil
+	}
+
+	return &authPolicyMembership{
+		client: c,
+	}
+}
+
+// Reset drops the loaded policies.
+func (m *authPolicyMembership) Reset() {
+	if m == nil {
+		return
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.loaded = false
+	m.failed = false
+	m.byAccount = nil
+	m.byPolicy = nil
+}
+
+// Policy returns the authentication policy applying to the account, if any. When the policies cannot be
+// loaded a warning is logged once and no account has a policy, so that users are still synced, without their
+// SSO and MFA status.
+func (m *authPolicyMembership) Policy(ctx context.Context, accountID string) (*client.AuthPolicy, bool) {
+	if m == nil {
+		return nil, false
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if !m.loaded && !m.failed {
+		if err := m.load(ctx); err != nil {
+			ctxzap.Extract(ctx).Warn("error loading authentication policies, syncing users without their SSO and MFA status", zap.Error(err))
+			m.failed = true
+		}
+	}
+
+	policy, ok := m.byAccount[accountID]
+	if !ok {
+		return nil, false
+	}
+	return &policy, true
+}
+
+// Members returns up to limit account IDs the policy applies to starting at offset, and the offset of the
+// next page or 0 when there are no more. Unlike Policy, it retries a failed load and returns its error.
+func (m *authPolicyMembership) Members(ctx context.Context, policyID string, offset, limit int) ([]string, int, error) {
+	if m == nil {
+		return nil, 0, nil
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if !m.loaded {
+		if err := m.load(ctx); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	accountIDs := m.byPolicy[policyID]
+	if offset >= len(accountIDs) {
+		return nil, 0, nil
+	}
+
+	end := min(offset+limit, len(accountIDs))
+	if end == len(accountIDs) {
+		return accountIDs[offset:end], 0, nil
+	}
+	return accountIDs[offset:end], end, nil
+}
+
+// load reads every policy and the accounts it applies to. It must be called with m.mtx held.
+func (m *authPolicyMembership) load(ctx context.Context) error {
+	byAccount := make(map[string]client.AuthPolicy)
+	byPolicy := make(map[string][]string)
+
+	var policies []client.AuthPolicy
+	for pageToken := ""; ; {
+		page, next, _, err := m.client.ListAuthPolicies(ctx, client.PageOptions{PageToken: pageToken})
+		if err != nil {
+			return err
+		}
+		policies = append(policies, page...)
+		if next == "" {
+			break
+		}
+		pageToken = next
+	}
+
+	for _, policy := range policies {
+		for pageToken := ""; ; {
+			users, next, _, err := m.client.ListAuthPolicyUsers(ctx, policy.ID, client.PageOptions{PageToken: pageToken})
+			if err != nil {
+				return err
+			}
+			for _, user := range users {
+				byAccount[user.AccountID] = policy
+				byPolicy[policy.ID] = append(byPolicy[policy.ID], user.AccountID)
+			}
+			if next == "" {
+				break
+			}
+			pageToken = next
+		}
+	}
+
+	m.byAccount = byAccount
+	m.byPolicy = byPolicy
+	m.loaded = true
+	m.failed = false
+	return nil
+}
+
+// authPolicyUserTraits returns the SSO and MFA status of a user from the policy applying to it.
+func authPolicyUserTraits(policy *client.AuthPolicy) []resource.UserTraitOption {
+	settings := policy.Attributes.Settings
+	return []resource.UserTraitOption{
+		resource.WithSSOStatus(&v2.UserTrait_SSOStatus{SsoEnabled: settings.SSOEnforced}),
+		resource.WithMFAStatus(&v2.UserTrait_MFAStatus{MfaEnabled: settings.TwoStepVerificationEnforced}),
+	}
+}
@@ -0,0 +1,69 @@
+package connector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/test"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+// Tests that users are still listed, without their SSO and MFA status, when the authentication policies
+// cannot be read, and that the policy grants report the error.
+func TestUserBuilder_ListWithoutAuthPolicies(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	teamsServer, _ := newTeamsServer(t)
+	d := newTestDirectory(teamsServer, defaultDirectoryMemoryLimit)
+	t.Cleanup(func() { _ = d.Invalidate() })
+
+	var policyRequests int
+	adminServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/policies") {
+			policyRequests++
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message": "the API key lacks the policies scope"}`))
+	}))
+	t.Cleanup(adminServer.Close)
+
+	adminClient := client.NewClient("", "", "admin-key", client.Endpoints{Admin: adminServer.URL}, test.OrganizationID, "",
+		uhttp.NewBaseHttpClient(adminServer.Client()))
+	membership := newAuthPolicyMembership(adminClient)
+
+	ctx := context.Background()
+	users, _, _, err := newUserBuilder(d, membership).List(ctx, nil, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(users) == 0 {
+		t.Fatalf("Expected users to be listed")
+	}
+	for _, user := range users {
+		userTrait, err := resource.GetUserTrait(user)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if userTrait.GetSsoStatus() != nil || userTrait.GetMfaStatus() != nil {
+			t.Errorf("Expected %s to have no SSO or MFA status, got %v and %v", user.DisplayName, userTrait.GetSsoStatus(), userTrait.GetMfaStatus())
+		}
+	}
+	if policyRequests != 1 {
+		t.Errorf("Expected the policies to be requested once for all users, got %d requests", policyRequests)
+	}
+
+	policyResource, err := parseIntoAuthPolicyResource(ctx, &client.AuthPolicy{ID: "policy-1"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, _, _, err := newAuthPolicyBuilder(adminClient, membership).Grants(ctx, policyResource, &pagination.Token{}); err == nil {
+		t.Errorf("Expected the policy grants to fail")
+	}
+}
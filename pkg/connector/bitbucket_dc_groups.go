@@ -0,0 +1,106 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+)
+
+type bitbucketDCGroupBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+}
+
+func (o *bitbucketDCGroupBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return groupResourceType
+}
+
+// List returns the groups of the Bitbucket instance.
+func (o *bitbucketDCGroupBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, groupResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	groups, nextPageToken, annotation, err := o.client.ListBitbucketGroups(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, group := range groups {
+		groupResource, err := parseIntoDataCenterGroupResource(ctx, group.Name, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, groupResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+func (o *bitbucketDCGroupBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return []*v2.Entitlement{dataCenterGroupMemberEntitlement(resource)}, "", nil, nil
+}
+
+func (o *bitbucketDCGroupBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	members, nextPageToken, annotation, err := o.client.ListBitbucketGroupMembers(ctx, resource.Id.Resource, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, member := range members {
+		principalID := &v2.ResourceId{
+			ResourceType: userResourceType.Id,
+			Resource:     member.Slug,
+		}
+		grants = append(grants, grant.NewGrant(resource, groupMemberEntitlement, principalID))
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return grants, nextPageToken, annotation, nil
+}
+
+func newBitbucketDCGroupBuilder(c *client.DataCenterClient) *bitbucketDCGroupBuilder {
+	return &bitbucketDCGroupBuilder{
+		resourceType: groupResourceType,
+		client:       c,
+	}
+}
@@ -0,0 +1,187 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+)
+
+// bitbucketGlobalPermissions are the global permissions of a Bitbucket instance, from lowest to highest.
+var bitbucketGlobalPermissions = []dataCenterPermission{
+	{key: "LICENSED_USER", displayName: "Bitbucket User"},
+	{key: "PROJECT_CREATE", displayName: "Project Creator"},
+	{key: "ADMIN", displayName: "Admin"},
+	{key: "SYS_ADMIN", displayName: "System Admin"},
+}
+
+// bitbucketProjectPermissions are the permissions held on a Bitbucket project, from lowest to highest.
+var bitbucketProjectPermissions = []dataCenterPermission{
+	{key: "PROJECT_READ", displayName: "Read"},
+	{key: "PROJECT_WRITE", displayName: "Write"},
+	{key: "PROJECT_ADMIN", displayName: "Admin"},
+}
+
+// bitbucketRepositoryPermissions are the permissions held on a Bitbucket repository, from lowest to highest.
+var bitbucketRepositoryPermissions = []dataCenterPermission{
+	{key: "REPO_READ", displayName: "Read"},
+	{key: "REPO_WRITE", displayName: "Write"},
+	{key: "REPO_ADMIN", displayName: "Admin"},
+}
+
+// bitbucketDCGlobalPermissionBuilder reports the global permissions of a Bitbucket instance, held by users and
+// groups.
+type bitbucketDCGlobalPermissionBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+}
+
+func (o *bitbucketDCGlobalPermissionBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return globalPermissionResourceType
+}
+
+// List returns a global permission resource for every global permission in a single page.
+func (o *bitbucketDCGlobalPermissionBuilder) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	resources := make([]*v2.Resource, 0, len(bitbucketGlobalPermissions))
+
+	for _, permission := range bitbucketGlobalPermissions {
+		permissionResource, err := parseIntoGlobalPermissionResource(ctx, permission, nil)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		resources = append(resources, permissionResource)
+	}
+
+	return resources, "", nil, nil
+}
+
+func (o *bitbucketDCGlobalPermissionBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return []*v2.Entitlement{globalPermissionEntitlement(resource)}, "", nil, nil
+}
+
+// Grants returns a grant to every user and group whose highest global permission is this one.
+func (o *bitbucketDCGlobalPermissionBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	permissions, annotation, err := o.client.ListBitbucketGlobalPermissions(ctx)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	var held []client.BitbucketPermission
+	for _, permission := range permissions {
+		if permission.Permission == resource.Id.Resource {
+			held = append(held, permission)
+		}
+	}
+
+	return bitbucketPermissionGrants(resource, held, func(string) string {
+		return globalPermissionAssignedEntitlement
+	}), "", annotation, nil
+}
+
+func newBitbucketDCGlobalPermissionBuilder(c *client.DataCenterClient) *bitbucketDCGlobalPermissionBuilder {
+	return &bitbucketDCGlobalPermissionBuilder{
+		resourceType: globalPermissionResourceType,
+		client:       c,
+	}
+}
+
+// bitbucketPermissionEntitlements returns an entitlement for every permission, held by users and groups.
+func bitbucketPermissionEntitlements(resource *v2.Resource, permissions []dataCenterPermission) []*v2.Entitlement {
+	entitlements := make([]*v2.Entitlement, 0, len(permissions))
+
+	for _, permission := range permissions {
+		entitlements = append(entitlements, entitlement.NewPermissionEntitlement(resource, permission.key,
+			entitlement.WithGrantableTo(userResourceType, groupResourceType),
+			entitlement.WithDescription(fmt.Sprintf("%s permission on the %s %s", permission.displayName, resource.DisplayName, resource.Id.ResourceType)),
+			entitlement.WithDisplayName(fmt.Sprintf("%s %s", resource.DisplayName, permission.displayName)),
+		))
+	}
+
+	return entitlements
+}
+
+// bitbucketPermissionGrants returns a grant to every holder of the permissions, of the entitlement returned by
+// entitlementName. Groups are expanded to their members and users granted by slug.
+func bitbucketPermissionGrants(resource *v2.Resource, permissions []client.BitbucketPermission, entitlementName func(permission string) string) []*v2.Grant {
+	var grants []*v2.Grant
+
+	for _, permission := range permissions {
+		name := entitlementName(permission.Permission)
+		if name == "" {
+			continue
+		}
+		switch {
+		case permission.Group != nil:
+			grants = append(grants, dataCenterGroupGrant(resource, name, permission.Group.Name))
+		case permission.User != nil:
+			principalID := &v2.ResourceId{
+				ResourceType: userResourceType.Id,
+				Resource:     permission.User.Slug,
+			}
+			grants = append(grants, grant.NewGrant(resource, name, principalID))
+		}
+	}
+
+	return grants
+}
+
+// bitbucketPermissionKey returns the permission of the list with the given key as its own entitlement name,
+// or an empty string for unknown permissions.
+func bitbucketPermissionKey(permissions []dataCenterPermission) func(string) string {
+	return func(key string) string {
+		for _, permission := range permissions {
+			if permission.key == key {
+				return key
+			}
+		}
+		return ""
+	}
+}
+
+// bitbucketHeldPermission returns the permission held by the user or group with the given name, or an empty
+// string when it holds none.
+func bitbucketHeldPermission(permissions []client.BitbucketPermission, group bool, name string) string {
+	for _, permission := range permissions {
+		switch {
+		case group && permission.Group != nil && permission.Group.Name == name:
+			return permission.Permission
+		case !group && permission.User != nil && permission.User.Name == name:
+			return permission.Permission
+		}
+	}
+
+	return ""
+}
+
+// bitbucketPermissionRank returns the position of the permission in the list, or -1 for unknown permissions.
+func bitbucketPermissionRank(permissions []dataCenterPermission, key string) int {
+	for i, permission := range permissions {
+		if permission.key == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// bitbucketPrincipal returns whether the principal is a group and the name permissions are set with, looking
+// users up by slug for their username.
+func bitbucketPrincipal(ctx context.Context, c *client.DataCenterClient, principal *v2.Resource) (bool, string, annotations.Annotations, error) {
+	switch principal.Id.ResourceType {
+	case groupResourceType.Id:
+		return true, principal.Id.Resource, nil, nil
+	case userResourceType.Id:
+		user, annotation, err := c.GetBitbucketUser(ctx, principal.Id.Resource)
+		if err != nil {
+			return false, "", annotation, err
+		}
+		return false, user.Name, annotation, nil
+	}
+
+	return false, "", nil, fmt.Errorf("baton-atlassian: only users and groups can be granted permissions")
+}
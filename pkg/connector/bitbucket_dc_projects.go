@@ -0,0 +1,171 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// bitbucketDCProjectBuilder reports the projects of a Bitbucket instance with an entitlement for every project
+// permission.
+type bitbucketDCProjectBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+}
+
+func (o *bitbucketDCProjectBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return projectResourceType
+}
+
+// List returns the projects of the Bitbucket instance.
+func (o *bitbucketDCProjectBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, projectResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	projects, nextPageToken, annotation, err := o.client.ListBitbucketProjects(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, project := range projects {
+		projectCopy := project
+		projectResource, err := parseIntoBitbucketDCProjectResource(ctx, &projectCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, projectResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+func (o *bitbucketDCProjectBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return bitbucketPermissionEntitlements(resource, bitbucketProjectPermissions), "", nil, nil
+}
+
+// Grants returns a grant to every user and group of the permission they hold on the project.
+func (o *bitbucketDCProjectBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	permissions, annotation, err := o.client.ListBitbucketProjectPermissions(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return bitbucketPermissionGrants(resource, permissions, bitbucketPermissionKey(bitbucketProjectPermissions)), "", annotation, nil
+}
+
+// Grant sets the permission of the user or group on the project, replacing a lower one it held. Holders have a
+// single permission per project, so granting a lower permission than the one held is refused rather than
+// downgrading the holder.
+func (o *bitbucketDCProjectBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	group, name, annotation, err := bitbucketPrincipal(ctx, o.client, principal)
+	if err != nil {
+		return annotation, err
+	}
+
+	projectKey := entitlement.Resource.Id.Resource
+	permissions, annotation, err := o.client.ListBitbucketProjectPermissions(ctx, projectKey)
+	if err != nil {
+		return annotation, err
+	}
+
+	held := bitbucketHeldPermission(permissions, group, name)
+	if held == entitlement.Slug {
+		annotation.Append(&v2.GrantAlreadyExists{})
+		return annotation, nil
+	}
+	if bitbucketPermissionRank(bitbucketProjectPermissions, held) > bitbucketPermissionRank(bitbucketProjectPermissions, entitlement.Slug) {
+		return annotation, fmt.Errorf("baton-atlassian: %s holds %s on project %s, revoke it before granting %s", name, held, projectKey, entitlement.Slug)
+	}
+
+	annotation, err = o.client.SetBitbucketProjectPermission(ctx, projectKey, group, name, entitlement.Slug)
+	if err != nil {
+		l.Error("failed to set project permission", zap.String("project_key", projectKey), zap.String("name", name), zap.Error(err))
+		return annotation, err
+	}
+
+	return annotation, nil
+}
+
+// Revoke removes the permission of the user or group on the project. Holders have a single permission per
+// project, so it is only removed while it is the permission of the grant.
+func (o *bitbucketDCProjectBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	group, name, annotation, err := bitbucketPrincipal(ctx, o.client, grant.Principal)
+	if err != nil {
+		return annotation, err
+	}
+
+	projectKey := grant.Entitlement.Resource.Id.Resource
+	permissions, annotation, err := o.client.ListBitbucketProjectPermissions(ctx, projectKey)
+	if err != nil {
+		return annotation, err
+	}
+	if bitbucketHeldPermission(permissions, group, name) != grant.Entitlement.Slug {
+		annotation.Append(&v2.GrantAlreadyRevoked{})
+		return annotation, nil
+	}
+
+	annotation, err = o.client.RevokeBitbucketProjectPermission(ctx, projectKey, group, name)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			annotation.Append(&v2.GrantAlreadyRevoked{})
+			return annotation, nil
+		}
+		l.Error("failed to revoke project permission", zap.String("project_key", projectKey), zap.String("name", name), zap.Error(err))
+		return annotation, err
+	}
+
+	return annotation, nil
+}
+
+func newBitbucketDCProjectBuilder(c *client.DataCenterClient) *bitbucketDCProjectBuilder {
+	return &bitbucketDCProjectBuilder{
+		resourceType: projectResourceType,
+		client:       c,
+	}
+}
+
+// parseIntoBitbucketDCProjectResource returns the project with its key as ID, as projects are addressed by key.
+func parseIntoBitbucketDCProjectResource(_ context.Context, project *client.BitbucketProject, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	ret, err := resource.NewResource(
+		project.Name,
+		projectResourceType,
+		project.Key,
+		resource.WithParentResourceID(parentResourceID),
+		resource.WithDescription(project.Description),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
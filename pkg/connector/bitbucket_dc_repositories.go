@@ -0,0 +1,198 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// bitbucketDCRepositoryBuilder reports the repositories of a Bitbucket instance, including those of personal
+// projects, with an entitlement for every repository permission.
+type bitbucketDCRepositoryBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+}
+
+func (o *bitbucketDCRepositoryBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return repositoryResourceType
+}
+
+// List returns the repositories of the Bitbucket instance.
+func (o *bitbucketDCRepositoryBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, repositoryResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	repositories, nextPageToken, annotation, err := o.client.ListBitbucketRepositories(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, repository := range repositories {
+		repositoryCopy := repository
+		repositoryResource, err := parseIntoBitbucketDCRepositoryResource(ctx, &repositoryCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, repositoryResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+func (o *bitbucketDCRepositoryBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return bitbucketPermissionEntitlements(resource, bitbucketRepositoryPermissions), "", nil, nil
+}
+
+// Grants returns a grant to every user and group of the permission they hold on the repository.
+func (o *bitbucketDCRepositoryBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	projectKey, repoSlug, err := parseRepositoryResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	permissions, annotation, err := o.client.ListBitbucketRepositoryPermissions(ctx, projectKey, repoSlug)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return bitbucketPermissionGrants(resource, permissions, bitbucketPermissionKey(bitbucketRepositoryPermissions)), "", annotation, nil
+}
+
+// Grant sets the permission of the user or group on the repository, replacing a lower one it held. Holders have
+// a single permission per repository, so granting a lower permission than the one held is refused rather than
+// downgrading the holder.
+func (o *bitbucketDCRepositoryBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	projectKey, repoSlug, err := parseRepositoryResourceID(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	group, name, annotation, err := bitbucketPrincipal(ctx, o.client, principal)
+	if err != nil {
+		return annotation, err
+	}
+
+	permissions, annotation, err := o.client.ListBitbucketRepositoryPermissions(ctx, projectKey, repoSlug)
+	if err != nil {
+		return annotation, err
+	}
+
+	held := bitbucketHeldPermission(permissions, group, name)
+	if held == entitlement.Slug {
+		annotation.Append(&v2.GrantAlreadyExists{})
+		return annotation, nil
+	}
+	if bitbucketPermissionRank(bitbucketRepositoryPermissions, held) > bitbucketPermissionRank(bitbucketRepositoryPermissions, entitlement.Slug) {
+		return annotation, fmt.Errorf("baton-atlassian: %s holds %s on repository %s, revoke it before granting %s", name, held, entitlement.Resource.Id.Resource, entitlement.Slug)
+	}
+
+	annotation, err = o.client.SetBitbucketRepositoryPermission(ctx, projectKey, repoSlug, group, name, entitlement.Slug)
+	if err != nil {
+		l.Error("failed to set repository permission", zap.String("repository", entitlement.Resource.Id.Resource), zap.String("name", name), zap.Error(err))
+		return annotation, err
+	}
+
+	return annotation, nil
+}
+
+// Revoke removes the permission of the user or group on the repository. Holders have a single permission per
+// repository, so it is only removed while it is the permission of the grant.
+func (o *bitbucketDCRepositoryBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	projectKey, repoSlug, err := parseRepositoryResourceID(grant.Entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	group, name, annotation, err := bitbucketPrincipal(ctx, o.client, grant.Principal)
+	if err != nil {
+		return annotation, err
+	}
+
+	permissions, annotation, err := o.client.ListBitbucketRepositoryPermissions(ctx, projectKey, repoSlug)
+	if err != nil {
+		return annotation, err
+	}
+	if bitbucketHeldPermission(permissions, group, name) != grant.Entitlement.Slug {
+		annotation.Append(&v2.GrantAlreadyRevoked{})
+		return annotation, nil
+	}
+
+	annotation, err = o.client.RevokeBitbucketRepositoryPermission(ctx, projectKey, repoSlug, group, name)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			annotation.Append(&v2.GrantAlreadyRevoked{})
+			return annotation, nil
+		}
+		l.Error("failed to revoke repository permission", zap.String("repository", grant.Entitlement.Resource.Id.Resource), zap.String("name", name), zap.Error(err))
+		return annotation, err
+	}
+
+	return annotation, nil
+}
+
+func newBitbucketDCRepositoryBuilder(c *client.DataCenterClient) *bitbucketDCRepositoryBuilder {
+	return &bitbucketDCRepositoryBuilder{
+		resourceType: repositoryResourceType,
+		client:       c,
+	}
+}
+
+func parseIntoBitbucketDCRepositoryResource(_ context.Context, repository *client.BitbucketRepository, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	ret, err := resource.NewResource(
+		fmt.Sprintf("%s/%s", repository.Project.Key, repository.Name),
+		repositoryResourceType,
+		repositoryResourceID(repository.Project.Key, repository.Slug),
+		resource.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// repositoryResourceID combines the project key and the repository slug, e.g. "PROJ/service". Personal
+// projects have keys starting with "~", which are kept.
+func repositoryResourceID(projectKey, repoSlug string) string {
+	return fmt.Sprintf("%s/%s", projectKey, repoSlug)
+}
+
+func parseRepositoryResourceID(resourceID string) (string, string, error) {
+	projectKey, repoSlug, ok := strings.Cut(resourceID, "/")
+	if !ok || projectKey == "" || repoSlug == "" {
+		return "", "", fmt.Errorf("baton-atlassian: invalid repository ID %q", resourceID)
+	}
+
+	return projectKey, repoSlug, nil
+}
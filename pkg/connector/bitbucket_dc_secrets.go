@@ -0,0 +1,231 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// bitbucketDCAccessTokenBuilder reports the HTTP access tokens of every user, as children of the user.
+type bitbucketDCAccessTokenBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+}
+
+func (o *bitbucketDCAccessTokenBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return accessTokenResourceType
+}
+
+// List returns the access tokens of the parent user.
+func (o *bitbucketDCAccessTokenBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	bag, pageToken, err := getToken(pToken, accessTokenResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	tokens, nextPageToken, annotation, err := o.client.ListBitbucketAccessTokens(ctx, parentResourceID.Resource, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, token := range tokens {
+		tokenCopy := token
+		tokenResource, err := parseIntoBitbucketDCAccessTokenResource(ctx, &tokenCopy, parentResourceID)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, tokenResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+// Entitlements always returns an empty slice for access tokens.
+func (o *bitbucketDCAccessTokenBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for access tokens since they don't have any entitlements.
+func (o *bitbucketDCAccessTokenBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func newBitbucketDCAccessTokenBuilder(c *client.DataCenterClient) *bitbucketDCAccessTokenBuilder {
+	return &bitbucketDCAccessTokenBuilder{
+		resourceType: accessTokenResourceType,
+		client:       c,
+	}
+}
+
+// bitbucketDCSSHKeyBuilder reports the SSH keys of every user, as children of the user.
+type bitbucketDCSSHKeyBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+}
+
+func (o *bitbucketDCSSHKeyBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return sshKeyResourceType
+}
+
+// List returns the SSH keys of the parent user. Keys are listed by username, so the user is looked up by slug
+// first.
+func (o *bitbucketDCSSHKeyBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	bag, pageToken, err := getToken(pToken, sshKeyResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	user, annotation, err := o.client.GetBitbucketUser(ctx, parentResourceID.Resource)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	keys, nextPageToken, annotation, err := o.client.ListBitbucketSSHKeys(ctx, user.Name, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, key := range keys {
+		keyCopy := key
+		keyResource, err := parseIntoBitbucketDCSSHKeyResource(ctx, &keyCopy, parentResourceID)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, keyResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+// Entitlements always returns an empty slice for SSH keys.
+func (o *bitbucketDCSSHKeyBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for SSH keys since they don't have any entitlements.
+func (o *bitbucketDCSSHKeyBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func newBitbucketDCSSHKeyBuilder(c *client.DataCenterClient) *bitbucketDCSSHKeyBuilder {
+	return &bitbucketDCSSHKeyBuilder{
+		resourceType: sshKeyResourceType,
+		client:       c,
+	}
+}
+
+// parseIntoBitbucketDCAccessTokenResource returns the access token, owned by its parent user, with the
+// permissions it was scoped to as description.
+func parseIntoBitbucketDCAccessTokenResource(_ context.Context, token *client.BitbucketAccessToken, userID *v2.ResourceId) (*v2.Resource, error) {
+	secretTraits := []resource.SecretTraitOption{
+		resource.WithSecretIdentityID(userID),
+		resource.WithSecretCreatedByID(userID),
+	}
+	if token.CreatedDate != 0 {
+		secretTraits = append(secretTraits, resource.WithSecretCreatedAt(time.UnixMilli(token.CreatedDate)))
+	}
+	if token.LastAuthenticated != 0 {
+		secretTraits = append(secretTraits, resource.WithSecretLastUsedAt(time.UnixMilli(token.LastAuthenticated)))
+	}
+	if token.ExpiryDate != 0 {
+		secretTraits = append(secretTraits, resource.WithSecretExpiresAt(time.UnixMilli(token.ExpiryDate)))
+	}
+
+	ret, err := resource.NewSecretResource(
+		token.Name,
+		accessTokenResourceType,
+		token.ID,
+		secretTraits,
+		resource.WithParentResourceID(userID),
+		resource.WithDescription(strings.Join(token.Permissions, ", ")),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// parseIntoBitbucketDCSSHKeyResource returns the SSH key, owned by its parent user. Keys expire a number of
+// days after they were created.
+func parseIntoBitbucketDCSSHKeyResource(_ context.Context, key *client.BitbucketSSHKey, userID *v2.ResourceId) (*v2.Resource, error) {
+	secretTraits := []resource.SecretTraitOption{
+		resource.WithSecretIdentityID(userID),
+		resource.WithSecretCreatedByID(userID),
+	}
+	if key.CreatedDate != 0 {
+		createdAt := time.UnixMilli(key.CreatedDate)
+		secretTraits = append(secretTraits, resource.WithSecretCreatedAt(createdAt))
+		if key.ExpiryDays != 0 {
+			secretTraits = append(secretTraits, resource.WithSecretExpiresAt(createdAt.AddDate(0, 0, key.ExpiryDays)))
+		}
+	}
+	if key.LastAuthenticated != 0 {
+		secretTraits = append(secretTraits, resource.WithSecretLastUsedAt(time.UnixMilli(key.LastAuthenticated)))
+	}
+
+	displayName := key.Label
+	if displayName == "" {
+		displayName = fmt.Sprintf("%s key %d", key.AlgorithmType, key.ID)
+	}
+
+	ret, err := resource.NewSecretResource(
+		displayName,
+		sshKeyResourceType,
+		strconv.FormatInt(key.ID, 10),
+		secretTraits,
+		resource.WithParentResourceID(userID),
+		resource.WithDescription(fmt.Sprintf("%s %d bits", key.AlgorithmType, key.BitLength)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,246 @@
+package connector
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newBitbucketDCConnector returns a Bitbucket Data Center connector sending its requests through the round
+// tripper.
+func newBitbucketDCConnector(transport *test.FixtureRoundTripper) *Connector {
+	return newDataCenterConnector(DataCenterProductBitbucket, test.NewFixtureDataCenterClient(transport, client.NewBearerAuth("pat")))
+}
+
+// Tests that NewDataCenter syncs a Bitbucket instance at the base URL with users identified by slug and their
+// access tokens, authenticated with the personal access token.
+func TestNewDataCenter_Bitbucket(t *testing.T) {
+	server, transport := test.NewFixtureServer(t, test.BitbucketDCFixtures)
+	ctx := context.Background()
+
+	c, err := NewDataCenter(ctx, DataCenterProductBitbucket, server.URL, client.NewBearerAuth("pat"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	syncers := c.ResourceSyncers(ctx)
+	resourceTypes := make([]string, 0, len(syncers))
+	for _, syncer := range syncers {
+		resourceTypes = append(resourceTypes, syncer.ResourceType(ctx).Id)
+	}
+	expected := []string{
+		userResourceType.Id,
+		groupResourceType.Id,
+		globalPermissionResourceType.Id,
+		projectResourceType.Id,
+		repositoryResourceType.Id,
+		accessTokenResourceType.Id,
+		sshKeyResourceType.Id,
+	}
+	if !slices.Equal(resourceTypes, expected) {
+		t.Fatalf("Expected resource types %v, got %v", expected, resourceTypes)
+	}
+
+	users, _ := listAll(t, syncers[0])
+	if len(users) != 2 || users[1].Id.Resource != "alice_example.com" {
+		t.Fatalf("Expected the users by slug, got %v", users)
+	}
+
+	tokens, _, _, err := syncers[5].List(ctx, users[1].Id, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(tokens) != 1 {
+		t.Fatalf("Expected alice to have 1 access token, got %d", len(tokens))
+	}
+	secretTrait := &v2.SecretTrait{}
+	annos := annotations.Annotations(tokens[0].Annotations)
+	if _, err := annos.Pick(secretTrait); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if secretTrait.IdentityId.Resource != "alice_example.com" || secretTrait.ExpiresAt.AsTime().UnixMilli() != 1800000000000 {
+		t.Errorf("Expected a token of alice expiring at 1800000000000, got %v", secretTrait)
+	}
+
+	if got := transport.Requests("GET /rest/api/1.0/admin/users")[0].Header.Get("Authorization"); got != "Bearer pat" {
+		t.Errorf("Expected requests to be authenticated with the personal access token, got %q", got)
+	}
+}
+
+// Tests that global, project and repository permissions are granted to the users and groups holding them.
+func TestBitbucketDCSyncers_Grants(t *testing.T) {
+	syncers := newBitbucketDCConnector(test.NewFixtureRoundTripper(test.BitbucketDCFixtures)).ResourceSyncers(context.Background())
+
+	_, permissionGrants := listAll(t, syncers[2])
+	if len(permissionGrants) != 2 {
+		t.Fatalf("Expected 2 global permission grants, got %d", len(permissionGrants))
+	}
+
+	projects, projectGrants := listAll(t, syncers[3])
+	if len(projectGrants) != 2 {
+		t.Fatalf("Expected 2 project grants, got %d", len(projectGrants))
+	}
+	for _, projectGrant := range projectGrants {
+		if projectGrant.Principal.Id.Resource == "alice_example.com" && projectGrant.Entitlement.Id != entitlement.NewEntitlementID(projects[0], "PROJECT_ADMIN") {
+			t.Errorf("Expected alice to administer the project, got %v", projectGrant)
+		}
+	}
+
+	repositories, repositoryGrants := listAll(t, syncers[4])
+	if len(repositories) != 1 || repositories[0].Id.Resource != "PROJ/service" || len(repositoryGrants) != 1 {
+		t.Fatalf("Expected a group to write to PROJ/service, got %v and %v", repositories, repositoryGrants)
+	}
+}
+
+// Tests that the permissions of a project are read from every page, both when listing its grants and when
+// finding the permission a holder already has.
+func TestBitbucketDCProjectBuilder_ReadsEveryPermissionPage(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(map[string]string{
+		"GET /rest/api/1.0/users/alice_example.com":                 "BitbucketDCUserAlice.json",
+		"GET /rest/api/1.0/projects/PROJ/permissions/users?start=0": "BitbucketDCProjectUserPermissionsFirstPage.json",
+		"GET /rest/api/1.0/projects/PROJ/permissions/users?start=1": "BitbucketDCProjectUserPermissionsLastPage.json",
+		"GET /rest/api/1.0/projects/PROJ/permissions/groups":        "BitbucketDCEmptyPage.json",
+	})
+	builder := newBitbucketDCProjectBuilder(test.NewFixtureDataCenterClient(transport, client.NewBearerAuth("pat")))
+	ctx := context.Background()
+
+	project, err := parseIntoBitbucketDCProjectResource(ctx, &client.BitbucketProject{Key: "PROJ", Name: "Project"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	grants, _, _, err := builder.Grants(ctx, project, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(grants) != 2 || grants[0].Principal.Id.Resource != "admin" || grants[1].Principal.Id.Resource != "alice_example.com" {
+		t.Fatalf("Expected grants to the holders of both pages, got %v", grants)
+	}
+
+	user, err := parseIntoBitbucketDCUserResource(ctx, &client.BitbucketUser{Name: "alice@example.com", Slug: "alice_example.com", DisplayName: "Alice"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	annos, err := builder.Grant(ctx, user, bitbucketPermissionEntitlements(project, bitbucketProjectPermissions)[2])
+	if err != nil || !annos.Contains(&v2.GrantAlreadyExists{}) {
+		t.Errorf("Expected the admin permission on the last page to be already granted, got %v", err)
+	}
+}
+
+// Tests that project and repository permissions are set and revoked by username and group name.
+func TestBitbucketDCProvisioning(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(test.BitbucketDCFixtures)
+	ctx := context.Background()
+	dataCenterClient := test.NewFixtureDataCenterClient(transport, client.NewBearerAuth("pat"))
+
+	user, err := parseIntoBitbucketDCUserResource(ctx, &client.BitbucketUser{Name: "alice@example.com", Slug: "alice_example.com", DisplayName: "Alice"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	group, err := parseIntoDataCenterGroupResource(ctx, "developers", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	repository, err := parseIntoBitbucketDCRepositoryResource(ctx, &client.BitbucketRepository{
+		Slug:    "service",
+		Name:    "Service",
+		Project: client.BitbucketProject{Key: "PROJ"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	repositoryBuilder := newBitbucketDCRepositoryBuilder(dataCenterClient)
+	adminEntitlement := bitbucketPermissionEntitlements(repository, bitbucketRepositoryPermissions)[2]
+
+	if _, err := repositoryBuilder.Grant(ctx, user, adminEntitlement); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	granted := transport.Requests("PUT /rest/api/1.0/projects/PROJ/repos/service/permissions/users?name=alice@example.com&permission=REPO_ADMIN")
+	if len(granted) != 1 {
+		t.Fatalf("Expected alice to be made an administrator of the repository once, got %d requests", len(granted))
+	}
+
+	writeEntitlement := bitbucketPermissionEntitlements(repository, bitbucketRepositoryPermissions)[1]
+	if _, err := repositoryBuilder.Revoke(ctx, &v2.Grant{Entitlement: writeEntitlement, Principal: group}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if revoked := transport.Requests("DELETE /rest/api/1.0/projects/PROJ/repos/service/permissions/groups?name=developers"); len(revoked) != 1 {
+		t.Fatalf("Expected the developers permission to be revoked once, got %d requests", len(revoked))
+	}
+}
+
+// Tests that granting and revoking a project permission leaves a holder's higher permission in place.
+func TestBitbucketDCProjectBuilder_KeepsHeldPermission(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(test.BitbucketDCFixtures)
+	ctx := context.Background()
+	dataCenterClient := test.NewFixtureDataCenterClient(transport, client.NewBearerAuth("pat"))
+
+	user, err := parseIntoBitbucketDCUserResource(ctx, &client.BitbucketUser{Name: "alice@example.com", Slug: "alice_example.com", DisplayName: "Alice"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	project, err := parseIntoBitbucketDCProjectResource(ctx, &client.BitbucketProject{Key: "PROJ", Name: "Project"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	projectBuilder := newBitbucketDCProjectBuilder(dataCenterClient)
+	entitlements := bitbucketPermissionEntitlements(project, bitbucketProjectPermissions)
+
+	if _, err := projectBuilder.Grant(ctx, user, entitlements[0]); err == nil {
+		t.Fatalf("Expected granting read to a project admin to fail")
+	}
+	annos, err := projectBuilder.Grant(ctx, user, entitlements[2])
+	if err != nil || !annos.Contains(&v2.GrantAlreadyExists{}) {
+		t.Fatalf("Expected the held admin permission to be already granted, got %v", err)
+	}
+	annos, err = projectBuilder.Revoke(ctx, &v2.Grant{Entitlement: entitlements[0], Principal: user})
+	if err != nil || !annos.Contains(&v2.GrantAlreadyRevoked{}) {
+		t.Fatalf("Expected a stale read grant to be already revoked, got %v", err)
+	}
+	if changes := len(transport.Requests("PUT /rest/api/1.0/projects/PROJ/permissions/users")) + len(transport.Requests("DELETE /rest/api/1.0/projects/PROJ/permissions/users")); changes != 0 {
+		t.Fatalf("Expected alice to still administer the project, got %d permission changes", changes)
+	}
+}
+
+// Tests that a permission removed since it was read is reported as already revoked, and that grants to users
+// who no longer exist fail with NotFound.
+func TestBitbucketDCProjectBuilder_MapsErrors(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(test.BitbucketDCFixtures)
+	transport.SetRoute("DELETE /rest/api/1.0/projects/PROJ/permissions/users", "404")
+	builder := newBitbucketDCProjectBuilder(test.NewFixtureDataCenterClient(transport, client.NewBearerAuth("pat")))
+	ctx := context.Background()
+
+	project, err := parseIntoBitbucketDCProjectResource(ctx, &client.BitbucketProject{Key: "PROJ", Name: "Project"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	entitlements := bitbucketPermissionEntitlements(project, bitbucketProjectPermissions)
+
+	alice, err := parseIntoBitbucketDCUserResource(ctx, &client.BitbucketUser{Name: "alice@example.com", Slug: "alice_example.com", DisplayName: "Alice"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	annos, err := builder.Revoke(ctx, &v2.Grant{Entitlement: entitlements[2], Principal: alice})
+	if err != nil || !annos.Contains(&v2.GrantAlreadyRevoked{}) {
+		t.Errorf("Expected a permission removed concurrently to be already revoked, got %v", err)
+	}
+
+	deleted, err := parseIntoBitbucketDCUserResource(ctx, &client.BitbucketUser{Name: "deleted", Slug: "deleted", DisplayName: "Deleted"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := builder.Grant(ctx, deleted, entitlements[0]); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected granting to a deleted user to fail with NotFound, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type bitbucketDCUserBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+}
+
+func (o *bitbucketDCUserBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return userResourceType
+}
+
+// List returns the users of the Bitbucket instance, with their access tokens and SSH keys as children.
+func (o *bitbucketDCUserBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	users, nextPageToken, annotation, err := o.client.ListBitbucketUsers(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, user := range users {
+		userCopy := user
+		userResource, err := parseIntoBitbucketDCUserResource(ctx, &userCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, userResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+// Entitlements always returns an empty slice for users.
+func (o *bitbucketDCUserBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for users since they don't have any entitlements.
+func (o *bitbucketDCUserBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func newBitbucketDCUserBuilder(c *client.DataCenterClient) *bitbucketDCUserBuilder {
+	return &bitbucketDCUserBuilder{
+		resourceType: userResourceType,
+		client:       c,
+	}
+}
+
+// parseIntoBitbucketDCUserResource returns the user with its slug as ID, as users are addressed by slug.
+func parseIntoBitbucketDCUserResource(_ context.Context, user *client.BitbucketUser, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	userStatus := v2.UserTrait_Status_STATUS_ENABLED
+	if !user.Active {
+		userStatus = v2.UserTrait_Status_STATUS_DISABLED
+	}
+
+	profile := map[string]interface{}{
+		"user_id":  user.ID,
+		"username": user.Name,
+		"slug":     user.Slug,
+		"email":    user.EmailAddress,
+		"active":   user.Active,
+		"type":     user.Type,
+	}
+
+	userTraits := []resource.UserTraitOption{
+		resource.WithUserProfile(profile),
+		resource.WithStatus(userStatus),
+		resource.WithUserLogin(user.Name),
+	}
+	if user.EmailAddress != "" {
+		userTraits = append(userTraits, resource.WithEmail(user.EmailAddress, true))
+	}
+
+	displayName := user.DisplayName
+	if displayName == "" {
+		displayName = user.Name
+	}
+
+	ret, err := resource.NewUserResource(
+		displayName,
+		userResourceType,
+		user.Slug,
+		userTraits,
+		resource.WithParentResourceID(parentResourceID),
+		resource.WithAnnotation(
+			&v2.ChildResourceType{ResourceTypeId: accessTokenResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: sshKeyResourceType.Id},
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
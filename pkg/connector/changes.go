@@ -0,0 +1,255 @@
+package connector
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	// watermarkETagPrefix marks ETags that hold the time a sync started rather than an HTTP ETag.
+	watermarkETagPrefix = "watermark:"
+
+	// auditLogDelay is how late an event may show up in the audit log. Every window of events starts this
+	// much before the watermark so that late events are not missed.
+	auditLogDelay = 5 * time.Minute
+
+	// eventObjectGroup is the normalized type of the groups audit log events act on.
+	eventObjectGroup = "group"
+
+	// changedEverything is recorded for events that may change the grants of any resource.
+	changedEverything = "*"
+)
+
+// eventObjectUsers are the normalized types of the accounts audit log events act on.
+var eventObjectUsers = []string{"user", "account"}
+
+// userRemovalActions are parts of the actions of events removing an account from the organization or
+// deactivating it. Those events only name the account, but end every group membership and product access
+// it held.
+var userRemovalActions = []string{"remove", "delete", "deactivat", "suspend"}
+
+// changeLog decides from the organization audit log which grants can be reused from the previous sync.
+//
+// Every sync stores its start time, the watermark, in the ETag of the groups and product roles it fully
+// listed. The SDK hands that ETag back on the next sync, and when the audit log has no event for the
+// resource since the watermark its grants are skipped with an ETag match. The ETag is the only state the
+// SDK keeps from one sync to the next: page tokens are dropped once a sync completes, so the watermark
+// cannot live in the pagination state. Watermarks older than maxAge fall back to a full listing, as the
+// audit log may no longer cover them. Events removing or deactivating an account only name the account, so
+// they make every group and product role count as changed.
+//
+// Team grants are out of scope and always re-read. They come from the directory the team and user listings
+// load in full anyway, and an ETag match reuses the grants of a single entitlement while teams have one per
+// membership role.
+type changeLog struct {
+	client *client.AtlassianClient
+	maxAge time.Duration
+	now    func() time.Time
+
+	mtx       sync.Mutex
+	syncStart time.Time
+	from      time.Time
+	changed   map[string]struct{}
+	failed    bool
+}
+
+func newChangeLog(c *client.AtlassianClient, maxAge time.Duration) *changeLog {
+	if maxAge <= 0 {
+		return nil
+	}
+
+	return &changeLog{
+		client: c,
+		maxAge: maxAge,
+		now:    time.Now,
+	}
+}
+
+// Reset starts a new sync. Only events up to the start of the sync are read, and its start time becomes the
+// next watermark.
+func (c *changeLog) Reset() {
+	if c == nil {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.syncStart = c.now()
+	c.from = time.Time{}
+	c.changed = nil
+	c.failed = false
+}
+
+// unchanged reports whether no audit log event touched any of keys since the watermark in etag. Keys are
+// object IDs, or normalized object types to match any object of that type.
+func (c *changeLog) unchanged(ctx context.Context, etag string, keys ...string) bool {
+	if c == nil || !strings.HasPrefix(etag, watermarkETagPrefix) {
+		return false
+	}
+
+	l := ctxzap.Extract(ctx)
+
+	watermark, err := time.Parse(time.RFC3339Nano, strings.TrimPrefix(etag, watermarkETagPrefix))
+	if err != nil {
+		l.Warn("ignoring invalid watermark", zap.String("etag", etag), zap.Error(err))
+		return false
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.start()
+	if c.failed {
+		return false
+	}
+	if c.syncStart.Sub(watermark) > c.maxAge {
+		l.Debug("watermark is too old, falling back to a full sync", zap.Time("watermark", watermark))
+		return false
+	}
+
+	if err := c.load(ctx, watermark.Add(-auditLogDelay)); err != nil {
+		l.Warn("error reading the audit log, falling back to a full sync", zap.Error(err))
+		c.failed = true
+		return false
+	}
+
+	if _, ok := c.changed[changedEverything]; ok {
+		return false
+	}
+	for _, key := range keys {
+		if _, ok := c.changed[key]; ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// annotate sets the ETag returned with a page of grants. Without a change log it falls back to the HTTP
+// ETag of the page.
+func (c *changeLog) annotate(annos annotations.Annotations, entitlementID string, nextPageToken string) annotations.Annotations {
+	if c == nil {
+		return scopeETag(annos, entitlementID, nextPageToken)
+	}
+
+	var ret annotations.Annotations
+	for _, a := range annos {
+		if !a.MessageIs(&v2.ETag{}) {
+			ret = append(ret, a)
+		}
+	}
+
+	if nextPageToken == "" {
+		c.mtx.Lock()
+		c.start()
+		watermark := c.syncStart
+		c.mtx.Unlock()
+
+		ret.Update(&v2.ETag{
+			Value:         watermarkETagPrefix + watermark.UTC().Format(time.RFC3339Nano),
+			EntitlementId: entitlementID,
+		})
+	}
+
+	return ret
+}
+
+// start sets the sync start time when Reset has not been called. It must be called with c.mtx held.
+func (c *changeLog) start() {
+	if c.syncStart.IsZero() {
+		c.syncStart = c.now()
+	}
+}
+
+// load reads the events between from and the start of the sync, reusing the events already read. It must
+// be called with c.mtx held.
+func (c *changeLog) load(ctx context.Context, from time.Time) error {
+	to := c.syncStart
+	if c.changed != nil {
+		if !from.Before(c.from) {
+			return nil
+		}
+		to = c.from
+	} else {
+		c.changed = make(map[string]struct{})
+	}
+
+	pageToken := ""
+	for {
+		events, nextPageToken, _, err := c.client.ListEvents(ctx, from, to, client.PageOptions{
+			PageSize:  client.ItemsPerPage,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			c.addObjects(event.Attributes.Context)
+			c.addObjects(event.Attributes.Container)
+			if removesUser(event) {
+				c.changed[changedEverything] = struct{}{}
+			}
+		}
+
+		if nextPageToken == "" {
+			break
+		}
+		pageToken = nextPageToken
+	}
+
+	c.from = from
+
+	return nil
+}
+
+func (c *changeLog) addObjects(objects []client.EventObject) {
+	for _, object := range objects {
+		c.changed[object.ID] = struct{}{}
+		c.changed[normalizeEventObjectType(object.Type)] = struct{}{}
+	}
+}
+
+// removesUser reports whether the event removes or deactivates an account without naming the groups and
+// products it loses access to.
+func removesUser(event client.Event) bool {
+	objects := append(slices.Clone(event.Attributes.Context), event.Attributes.Container...)
+	if len(objects) == 0 {
+		return false
+	}
+	for _, object := range objects {
+		if !slices.Contains(eventObjectUsers, normalizeEventObjectType(object.Type)) {
+			return false
+		}
+	}
+
+	action := strings.ToLower(event.Attributes.Action)
+	for _, removal := range userRemovalActions {
+		if strings.Contains(action, removal) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeEventObjectType(objectType string) string {
+	return strings.TrimSuffix(strings.ToLower(objectType), "s")
+}
+
+// conditionalETag returns the ETag to send in If-None-Match, leaving out watermarks.
+func conditionalETag(etag string) string {
+	if strings.HasPrefix(etag, watermarkETagPrefix) {
+		return ""
+	}
+	return etag
+}
@@ -0,0 +1,244 @@
+package connector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+// Tests that incremental syncs reuse the grants of groups the audit log reports as unchanged, and list the
+// grants of changed groups and of groups whose watermark is too old.
+func TestGroupBuilder_GrantsIncremental(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	var eventRequests, memberRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/events"):
+			eventRequests++
+			_, _ = w.Write([]byte(`{"data": [{"id": "event-1", "attributes": {"action": "group_member_added",
+				"context": [{"id": "group-2", "type": "groups"}]}}], "links": {}}`))
+		case strings.HasSuffix(r.URL.Path, "/memberships"):
+			memberRequests++
+			_, _ = w.Write([]byte(`{"data": [{"account_id": "` + test.UserIDs[0] + `"}], "links": {}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	atlassianClient := client.NewClient("", "", "admin-key", client.Endpoints{Admin: server.URL}, test.OrganizationID, "",
+		uhttp.NewBaseHttpClient(server.Client()))
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	changes := newChangeLog(atlassianClient, 24*time.Hour)
+	changes.now = func() time.Time { return now }
+	builder := newGroupBuilder(atlassianClient, changes)
+
+	grants := func(groupID string, watermark time.Time) ([]*v2.Grant, annotations.Annotations) {
+		groupResource, err := parseIntoGroupResource(context.Background(), &client.Group{ID: groupID, Name: groupID}, nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !watermark.IsZero() {
+			groupResource.Annotations = annotations.New(&v2.ETag{
+				Value:         watermarkETagPrefix + watermark.Format(time.RFC3339Nano),
+				EntitlementId: entitlement.NewEntitlementID(groupResource, groupMemberEntitlement),
+			})
+		}
+
+		list, _, annos, err := builder.Grants(context.Background(), groupResource, &pagination.Token{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return list, annos
+	}
+
+	// The first sync lists the grants and stores its start time as the watermark.
+	changes.Reset()
+	list, annos := grants("group-1", time.Time{})
+	if len(list) != 1 {
+		t.Fatalf("Expected 1 grant, got %d", len(list))
+	}
+	etag := &v2.ETag{}
+	if ok, err := annos.Pick(etag); err != nil || !ok || etag.Value != watermarkETagPrefix+now.Format(time.RFC3339Nano) {
+		t.Fatalf("Expected the sync start as watermark, got %v", annos)
+	}
+
+	now = now.Add(time.Hour)
+	changes.Reset()
+	previous := now.Add(-time.Hour)
+
+	list, annos = grants("group-1", previous)
+	if len(list) != 0 || !annos.Contains(&v2.ETagMatch{}) {
+		t.Errorf("Expected the grants of the unchanged group to be reused, got %d grants and %v", len(list), annos)
+	}
+
+	list, annos = grants("group-2", previous)
+	if len(list) != 1 || annos.Contains(&v2.ETagMatch{}) {
+		t.Errorf("Expected the grants of the changed group to be listed, got %d grants and %v", len(list), annos)
+	}
+
+	list, _ = grants("group-3", now.Add(-48*time.Hour))
+	if len(list) != 1 {
+		t.Errorf("Expected the grants of a group with an old watermark to be listed, got %d grants", len(list))
+	}
+
+	if eventRequests != 1 {
+		t.Errorf("Expected the audit log to be read once per sync, got %d requests", eventRequests)
+	}
+	if memberRequests != 3 {
+		t.Errorf("Expected 3 membership requests, got %d", memberRequests)
+	}
+}
+
+// Tests that incremental syncs list the grants of every group after an account is removed from the
+// organization, as the audit log event only names the account.
+func TestGroupBuilder_GrantsIncrementalAfterUserRemoval(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/events"):
+			_, _ = w.Write([]byte(`{"data": [{"id": "event-1", "attributes": {"action": "user_removed_from_org",
+				"context": [{"id": "` + test.UserIDs[0] + `", "type": "users"}]}}], "links": {}}`))
+		case strings.HasSuffix(r.URL.Path, "/memberships"):
+			_, _ = w.Write([]byte(`{"data": [{"account_id": "` + test.UserIDs[1] + `"}], "links": {}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	atlassianClient := client.NewClient("", "", "admin-key", client.Endpoints{Admin: server.URL}, test.OrganizationID, "",
+		uhttp.NewBaseHttpClient(server.Client()))
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	changes := newChangeLog(atlassianClient, 24*time.Hour)
+	changes.now = func() time.Time { return now }
+	changes.Reset()
+	builder := newGroupBuilder(atlassianClient, changes)
+
+	groupResource, err := parseIntoGroupResource(context.Background(), &client.Group{ID: "group-1", Name: "group-1"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	groupResource.Annotations = annotations.New(&v2.ETag{
+		Value:         watermarkETagPrefix + now.Add(-time.Hour).Format(time.RFC3339Nano),
+		EntitlementId: entitlement.NewEntitlementID(groupResource, groupMemberEntitlement),
+	})
+
+	list, _, annos, err := builder.Grants(context.Background(), groupResource, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(list) != 1 || annos.Contains(&v2.ETagMatch{}) {
+		t.Errorf("Expected the grants of the group to be listed again, got %d grants and %v", len(list), annos)
+	}
+}
+
+// Tests that incremental syncs list the grants of product roles named by an audit log event, and reuse the
+// grants of the other roles on the product.
+func TestProductRoleBuilder_GrantsIncremental(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	var roleRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/events"):
+			_, _ = w.Write([]byte(`{"data": [{"id": "event-1", "attributes": {"action": "product_role_granted",
+				"context": [{"id": "` + client.ProductRoleAdmin + `", "type": "roles"}]}}], "links": {}}`))
+		case strings.HasSuffix(r.URL.Path, "/directories/-/users"):
+			roleRequests++
+			_, _ = w.Write([]byte(`{"data": [{"accountId": "` + test.UserIDs[0] + `"}], "links": {}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	atlassianClient := client.NewClient("", "", "admin-key", client.Endpoints{Admin: server.URL}, test.OrganizationID, "",
+		uhttp.NewBaseHttpClient(server.Client()))
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	changes := newChangeLog(atlassianClient, 24*time.Hour)
+	changes.now = func() time.Time { return now }
+	changes.Reset()
+	builder := newProductRoleBuilder(atlassianClient, ProductAccessModeRoleAssignment, changes)
+
+	workspace := &client.Workspace{
+		ID:         "ari:cloud:confluence::site/site-1",
+		Attributes: client.WorkspaceAttributes{TypeKey: "confluence", HostURL: "https://acme.atlassian.net"},
+	}
+	for _, role := range productRoles {
+		roleResource, err := parseIntoProductRoleResource(context.Background(), workspace, role, nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		roleResource.Annotations = annotations.New(&v2.ETag{
+			Value:         watermarkETagPrefix + now.Add(-time.Hour).Format(time.RFC3339Nano),
+			EntitlementId: entitlement.NewEntitlementID(roleResource, productRoleAssignedEntitlement),
+		})
+
+		list, _, annos, err := builder.Grants(context.Background(), roleResource, &pagination.Token{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		changed := role.roleID == client.ProductRoleAdmin
+		if changed && (len(list) != 1 || annos.Contains(&v2.ETagMatch{})) {
+			t.Errorf("Expected the grants of the %s role named by the event to be listed, got %d grants and %v", role.key, len(list), annos)
+		}
+		if !changed && (len(list) != 0 || !annos.Contains(&v2.ETagMatch{})) {
+			t.Errorf("Expected the grants of the unchanged %s role to be reused, got %d grants and %v", role.key, len(list), annos)
+		}
+	}
+
+	if roleRequests != 1 {
+		t.Errorf("Expected only the changed role to be listed, got %d requests", roleRequests)
+	}
+}
+
+// Tests that team grants stay out of incremental syncs: they are always listed and never carry a watermark.
+func TestTeamBuilder_GrantsNotIncremental(t *testing.T) {
+	server, _ := newTeamsServer(t)
+	d := newTestDirectory(server, 0)
+	t.Cleanup(func() { _ = d.Invalidate() })
+	builder := newTeamBuilder(nil, d)
+
+	ctx := context.Background()
+	teams, _, err := d.Teams(ctx, 0, 1)
+	if err != nil || len(teams) != 1 {
+		t.Fatalf("Expected a team, got %d (%v)", len(teams), err)
+	}
+	teamResource, err := parseIntoTeamResource(ctx, &teams[0].Team, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	teamResource.Annotations = annotations.New(&v2.ETag{
+		Value:         watermarkETagPrefix + time.Now().Format(time.RFC3339Nano),
+		EntitlementId: entitlement.NewEntitlementID(teamResource, teamMembershipRoles[0]),
+	})
+
+	list, _, annos, err := builder.Grants(ctx, teamResource, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(list) == 0 {
+		t.Errorf("Expected the team grants to be listed")
+	}
+	if annos.Contains(&v2.ETagMatch{}) || annos.Contains(&v2.ETag{}) {
+		t.Errorf("Expected no ETag annotations on team grants, got %v", annos)
+	}
+}
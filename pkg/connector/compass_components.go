@@ -0,0 +1,147 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const compassComponentOwnerEntitlement = "owner"
+
+// compassComponentBuilder reports the Compass components of the site, owned by an Atlassian team whose members
+// can change the component's metadata and scorecards.
+type compassComponentBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.AtlassianClient
+}
+
+func (o *compassComponentBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return compassComponentResourceType
+}
+
+func (o *compassComponentBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, compassComponentResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	components, nextPageToken, annotation, err := o.client.ListCompassComponents(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, component := range components {
+		componentCopy := component
+		componentResource, err := parseIntoCompassComponentResource(ctx, &componentCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, componentResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+func (o *compassComponentBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	assigmentOptions := []entitlement.EntitlementOption{
+		entitlement.WithGrantableTo(teamResourceType),
+		entitlement.WithDescription(fmt.Sprintf("Owner of the %s component", resource.DisplayName)),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Component Owner", resource.DisplayName)),
+	}
+
+	return []*v2.Entitlement{
+		entitlement.NewPermissionEntitlement(resource, compassComponentOwnerEntitlement, assigmentOptions...),
+	}, "", nil, nil
+}
+
+// Grants returns a grant of ownership to the team owning the component, read from the component's profile.
+// The grant expands to every member of the team, whatever their role.
+func (o *compassComponentBuilder) Grants(_ context.Context, componentResource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	appTrait, err := resource.GetAppTrait(componentResource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	ownerID, ok := resource.GetProfileStringValue(appTrait.Profile, "owner_id")
+	if !ok || ownerID == "" {
+		return nil, "", nil, nil
+	}
+
+	teamID := &v2.ResourceId{
+		ResourceType: teamResourceType.Id,
+		Resource:     ownerID,
+	}
+	teamResource := &v2.Resource{Id: teamID}
+
+	entitlementIDs := make([]string, 0, len(teamMembershipRoles))
+	for _, role := range teamMembershipRoles {
+		entitlementIDs = append(entitlementIDs, entitlement.NewEntitlementID(teamResource, role))
+	}
+
+	return []*v2.Grant{
+		grant.NewGrant(componentResource, compassComponentOwnerEntitlement, teamID,
+			grant.WithAnnotation(&v2.GrantExpandable{
+				EntitlementIds: entitlementIDs,
+			}),
+		),
+	}, "", nil, nil
+}
+
+func newCompassComponentBuilder(c *client.AtlassianClient) *compassComponentBuilder {
+	return &compassComponentBuilder{
+		resourceType: compassComponentResourceType,
+		client:       c,
+	}
+}
+
+// parseIntoCompassComponentResource returns the component with its ARI as ID. The owning team is kept in the
+// profile for Grants.
+func parseIntoCompassComponentResource(_ context.Context, component *client.CompassComponent, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"component_id": component.ID,
+		"name":         component.Name,
+		"type_id":      component.TypeID,
+		"tier":         component.Tier(),
+		"owner_id":     component.OwnerID,
+	}
+
+	appTraits := []resource.AppTraitOption{
+		resource.WithAppProfile(profile),
+	}
+
+	ret, err := resource.NewAppResource(
+		component.Name,
+		compassComponentResourceType,
+		component.ID,
+		appTraits,
+		resource.WithParentResourceID(parentResourceID),
+		resource.WithDescription(component.Description),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
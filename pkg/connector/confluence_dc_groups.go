@@ -0,0 +1,156 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+type confluenceDCGroupBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+}
+
+func (o *confluenceDCGroupBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return groupResourceType
+}
+
+// List returns the groups of the Confluence instance.
+func (o *confluenceDCGroupBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, groupResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	groups, nextPageToken, annotation, err := o.client.ListConfluenceGroups(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, group := range groups {
+		groupResource, err := parseIntoDataCenterGroupResource(ctx, group.Name, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, groupResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+func (o *confluenceDCGroupBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return []*v2.Entitlement{dataCenterGroupMemberEntitlement(resource)}, "", nil, nil
+}
+
+func (o *confluenceDCGroupBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	members, nextPageToken, annotation, err := o.client.ListConfluenceGroupMembers(ctx, resource.Id.Resource, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, member := range members {
+		principalID := &v2.ResourceId{
+			ResourceType: userResourceType.Id,
+			Resource:     member.UserKey,
+		}
+		grants = append(grants, grant.NewGrant(resource, groupMemberEntitlement, principalID))
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return grants, nextPageToken, annotation, nil
+}
+
+// Grant adds the user to the group. Membership is changed by username, so the user is looked up by key first.
+func (o *confluenceDCGroupBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if principal.Id.ResourceType != userResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: only users can be granted group membership")
+	}
+
+	groupName := entitlement.Resource.Id.Resource
+	user, annotation, err := o.client.GetConfluenceUser(ctx, "", principal.Id.Resource)
+	if err != nil {
+		return annotation, err
+	}
+
+	annotation, err = o.client.AddConfluenceGroupMember(ctx, groupName, user.Username)
+	if err != nil {
+		l.Error("failed to add group member", zap.String("group_name", groupName), zap.String("username", user.Username), zap.Error(err))
+		return annotation, wrapProvisioningError(err)
+	}
+
+	return annotation, nil
+}
+
+// Revoke removes the user from the group.
+func (o *confluenceDCGroupBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	principal := grant.Principal
+	if principal.Id.ResourceType != userResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: only users can have group membership revoked")
+	}
+
+	groupName := grant.Entitlement.Resource.Id.Resource
+	user, annotation, err := o.client.GetConfluenceUser(ctx, "", principal.Id.Resource)
+	if err != nil {
+		return annotation, err
+	}
+
+	annotation, err = o.client.RemoveConfluenceGroupMember(ctx, groupName, user.Username)
+	if err != nil {
+		l.Error("failed to remove group member", zap.String("group_name", groupName), zap.String("username", user.Username), zap.Error(err))
+		return annotation, wrapProvisioningError(err)
+	}
+
+	return annotation, nil
+}
+
+func newConfluenceDCGroupBuilder(c *client.DataCenterClient) *confluenceDCGroupBuilder {
+	return &confluenceDCGroupBuilder{
+		resourceType: groupResourceType,
+		client:       c,
+	}
+}
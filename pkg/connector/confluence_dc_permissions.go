@@ -0,0 +1,218 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const globalPermissionAssignedEntitlement = "assigned"
+
+type dataCenterPermission struct {
+	key         string
+	displayName string
+}
+
+// confluenceGlobalPermissions are the global permissions of a Confluence instance.
+var confluenceGlobalPermissions = []dataCenterPermission{
+	{key: "USECONFLUENCE", displayName: "Can Use"},
+	{key: "PERSONALSPACE", displayName: "Personal Space"},
+	{key: "CREATESPACE", displayName: "Create Space"},
+	{key: "UPDATEUSERSTATUS", displayName: "Update User Status"},
+	{key: "PROFILEATTACHMENTS", displayName: "Attach Files to User Profile"},
+	{key: "ADMINISTRATECONFLUENCE", displayName: "Confluence Administrator"},
+	{key: "SYSTEMADMINISTRATOR", displayName: "System Administrator"},
+}
+
+// confluenceSpacePermissions are the permissions held on a Confluence space, each reported as an entitlement
+// of the space.
+var confluenceSpacePermissions = []dataCenterPermission{
+	{key: "VIEWSPACE", displayName: "View"},
+	{key: "REMOVEOWNCONTENT", displayName: "Delete Own"},
+	{key: "EDITSPACE", displayName: "Add Pages"},
+	{key: "REMOVEPAGE", displayName: "Delete Pages"},
+	{key: "EDITBLOG", displayName: "Add Blogs"},
+	{key: "REMOVEBLOG", displayName: "Delete Blogs"},
+	{key: "CREATEATTACHMENT", displayName: "Add Attachments"},
+	{key: "REMOVEATTACHMENT", displayName: "Delete Attachments"},
+	{key: "COMMENT", displayName: "Add Comments"},
+	{key: "REMOVECOMMENT", displayName: "Delete Comments"},
+	{key: "SETPAGEPERMISSIONS", displayName: "Restrict Pages"},
+	{key: "REMOVEMAIL", displayName: "Delete Mail"},
+	{key: "EXPORTSPACE", displayName: "Export Space"},
+	{key: "SETSPACEPERMISSIONS", displayName: "Space Admin"},
+}
+
+// confluenceDCGlobalPermissionBuilder reports the global permissions of a Confluence instance, held by users
+// and groups.
+type confluenceDCGlobalPermissionBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+}
+
+func (o *confluenceDCGlobalPermissionBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return globalPermissionResourceType
+}
+
+// List returns a global permission resource for every global permission in a single page.
+func (o *confluenceDCGlobalPermissionBuilder) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	resources := make([]*v2.Resource, 0, len(confluenceGlobalPermissions))
+
+	for _, permission := range confluenceGlobalPermissions {
+		permissionResource, err := parseIntoGlobalPermissionResource(ctx, permission, nil)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		resources = append(resources, permissionResource)
+	}
+
+	return resources, "", nil, nil
+}
+
+func (o *confluenceDCGlobalPermissionBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return []*v2.Entitlement{globalPermissionEntitlement(resource)}, "", nil, nil
+}
+
+// Grants returns a grant to every user and group holding the global permission.
+func (o *confluenceDCGlobalPermissionBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+
+	sets, annotation, err := o.client.ListConfluenceGlobalPermissions(ctx)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	users := newConfluenceUserKeys(o.client)
+	for _, set := range sets {
+		if set.Type != resource.Id.Resource {
+			continue
+		}
+		setGrants, err := confluencePermissionGrants(ctx, users, resource, globalPermissionAssignedEntitlement, set.Permissions)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		grants = append(grants, setGrants...)
+	}
+
+	return grants, "", annotation, nil
+}
+
+func newConfluenceDCGlobalPermissionBuilder(c *client.DataCenterClient) *confluenceDCGlobalPermissionBuilder {
+	return &confluenceDCGlobalPermissionBuilder{
+		resourceType: globalPermissionResourceType,
+		client:       c,
+	}
+}
+
+// globalPermissionEntitlement returns the entitlement of a global permission, held by users and groups.
+func globalPermissionEntitlement(resource *v2.Resource) *v2.Entitlement {
+	return entitlement.NewAssignmentEntitlement(resource, globalPermissionAssignedEntitlement,
+		entitlement.WithGrantableTo(userResourceType, groupResourceType),
+		entitlement.WithDescription(fmt.Sprintf("Assigned the %s global permission", resource.DisplayName)),
+		entitlement.WithDisplayName(resource.DisplayName),
+	)
+}
+
+// parseIntoGlobalPermissionResource returns the global permission with its key as ID, e.g. "CREATESPACE".
+func parseIntoGlobalPermissionResource(_ context.Context, permission dataCenterPermission, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"permission": permission.key,
+	}
+
+	roleTraits := []resource.RoleTraitOption{
+		resource.WithRoleProfile(profile),
+	}
+
+	ret, err := resource.NewRoleResource(
+		permission.displayName,
+		globalPermissionResourceType,
+		permission.key,
+		roleTraits,
+		resource.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// confluenceUserKeys resolves the usernames of permission holders to user keys, looking each username up
+// once.
+type confluenceUserKeys struct {
+	client *client.DataCenterClient
+	keys   map[string]string
+}
+
+func newConfluenceUserKeys(c *client.DataCenterClient) *confluenceUserKeys {
+	return &confluenceUserKeys{
+		client: c,
+		keys:   make(map[string]string),
+	}
+}
+
+// key returns the key of the user, or an empty string for a deleted user.
+func (u *confluenceUserKeys) key(ctx context.Context, username string) (string, error) {
+	if key, ok := u.keys[username]; ok {
+		return key, nil
+	}
+
+	user, _, err := u.client.GetConfluenceUser(ctx, username, "")
+	if err != nil {
+		if status.Code(err) != codes.NotFound {
+			return "", err
+		}
+		u.keys[username] = ""
+		return "", nil
+	}
+
+	u.keys[username] = user.UserKey
+	return user.UserKey, nil
+}
+
+// confluencePermissionGrants returns a grant of the entitlement to every holder of a permission. Groups are
+// expanded to their members and users granted by key.
+func confluencePermissionGrants(
+	ctx context.Context,
+	users *confluenceUserKeys,
+	resource *v2.Resource,
+	entitlementName string,
+	permissions []client.ConfluencePermission,
+) ([]*v2.Grant, error) {
+	l := ctxzap.Extract(ctx)
+	var grants []*v2.Grant
+
+	for _, permission := range permissions {
+		switch {
+		case permission.GroupName != "":
+			grants = append(grants, dataCenterGroupGrant(resource, entitlementName, permission.GroupName))
+		case permission.UserName != "":
+			key, err := users.key(ctx, permission.UserName)
+			if err != nil {
+				return nil, err
+			}
+			if key == "" {
+				l.Warn("skipping permission of a deleted user", zap.String("username", permission.UserName))
+				continue
+			}
+			principalID := &v2.ResourceId{
+				ResourceType: userResourceType.Id,
+				Resource:     key,
+			}
+			grants = append(grants, grant.NewGrant(resource, entitlementName, principalID))
+		}
+	}
+
+	return grants, nil
+}
@@ -0,0 +1,138 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// confluenceDCSpaceBuilder reports the spaces of a Confluence instance with an entitlement for every space
+// permission.
+type confluenceDCSpaceBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+}
+
+func (o *confluenceDCSpaceBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return spaceResourceType
+}
+
+// List returns the spaces of the Confluence instance.
+func (o *confluenceDCSpaceBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, spaceResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	spaces, nextPageToken, annotation, err := o.client.ListConfluenceSpaces(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, space := range spaces {
+		spaceCopy := space
+		spaceResource, err := parseIntoConfluenceDCSpaceResource(ctx, &spaceCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, spaceResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+// Entitlements returns an entitlement for every space permission, held by users and groups.
+func (o *confluenceDCSpaceBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	entitlements := make([]*v2.Entitlement, 0, len(confluenceSpacePermissions))
+
+	for _, permission := range confluenceSpacePermissions {
+		entitlements = append(entitlements, entitlement.NewPermissionEntitlement(resource, permission.key,
+			entitlement.WithGrantableTo(userResourceType, groupResourceType),
+			entitlement.WithDescription(fmt.Sprintf("%s permission on the %s space", permission.displayName, resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("%s %s", resource.DisplayName, permission.displayName)),
+		))
+	}
+
+	return entitlements, "", nil, nil
+}
+
+// Grants returns a grant to every user and group holding a permission on the space.
+func (o *confluenceDCSpaceBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+	var grants []*v2.Grant
+
+	sets, annotation, err := o.client.ListConfluenceSpacePermissions(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	users := newConfluenceUserKeys(o.client)
+	for _, set := range sets {
+		if !isConfluenceSpacePermission(set.Type) {
+			l.Debug("skipping unknown space permission", zap.String("permission", set.Type))
+			continue
+		}
+		setGrants, err := confluencePermissionGrants(ctx, users, resource, set.Type, set.Permissions)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		grants = append(grants, setGrants...)
+	}
+
+	return grants, "", annotation, nil
+}
+
+func newConfluenceDCSpaceBuilder(c *client.DataCenterClient) *confluenceDCSpaceBuilder {
+	return &confluenceDCSpaceBuilder{
+		resourceType: spaceResourceType,
+		client:       c,
+	}
+}
+
+func isConfluenceSpacePermission(key string) bool {
+	for _, permission := range confluenceSpacePermissions {
+		if permission.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIntoConfluenceDCSpaceResource returns the space with its key as ID, as permissions are read by key.
+func parseIntoConfluenceDCSpaceResource(_ context.Context, space *client.ConfluenceSpace, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	ret, err := resource.NewResource(
+		space.Name,
+		spaceResourceType,
+		space.Key,
+		resource.WithParentResourceID(parentResourceID),
+		resource.WithDescription(fmt.Sprintf("%s space %s", space.Type, space.Key)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
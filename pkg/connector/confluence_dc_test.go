@@ -0,0 +1,201 @@
+package connector
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newConfluenceDCConnector returns a Confluence Data Center connector sending its requests through the round
+// tripper.
+func newConfluenceDCConnector(transport *test.FixtureRoundTripper) *Connector {
+	return newDataCenterConnector(DataCenterProductConfluence, test.NewFixtureDataCenterClient(transport, client.NewBearerAuth("pat")))
+}
+
+// Tests that NewDataCenter syncs a Confluence instance at the base URL with users identified by key,
+// authenticated with the personal access token.
+func TestNewDataCenter_Confluence(t *testing.T) {
+	server, transport := test.NewFixtureServer(t, test.ConfluenceDCFixtures)
+	ctx := context.Background()
+
+	c, err := NewDataCenter(ctx, DataCenterProductConfluence, server.URL, client.NewBearerAuth("pat"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	syncers := c.ResourceSyncers(ctx)
+	resourceTypes := make([]string, 0, len(syncers))
+	for _, syncer := range syncers {
+		resourceTypes = append(resourceTypes, syncer.ResourceType(ctx).Id)
+	}
+	expected := []string{userResourceType.Id, groupResourceType.Id, globalPermissionResourceType.Id, spaceResourceType.Id}
+	if !slices.Equal(resourceTypes, expected) {
+		t.Fatalf("Expected resource types %v, got %v", expected, resourceTypes)
+	}
+
+	users, _ := listAll(t, syncers[0])
+	if len(users) != 3 || users[1].Id.Resource != "8a7f808a2" {
+		t.Fatalf("Expected the users by key, got %v", users)
+	}
+
+	if got := transport.Requests("GET /rest/api/user/list")[0].Header.Get("Authorization"); got != "Bearer pat" {
+		t.Errorf("Expected requests to be authenticated with the personal access token, got %q", got)
+	}
+}
+
+// Tests that group members are listed page after page by start until Confluence returns a short page.
+func TestConfluenceDCGroupBuilder_ListsMembersOfEveryPage(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(map[string]string{
+		"GET /rest/api/group/confluence-users/member?start=0&limit=2": "ConfluenceDCUsersFirstPage.json",
+		"GET /rest/api/group/confluence-users/member?start=2&limit=2": "ConfluenceDCUsersLastPage.json",
+	})
+	builder := newConfluenceDCGroupBuilder(test.NewFixtureDataCenterClient(transport, client.NewBearerAuth("pat")))
+
+	group, err := parseIntoDataCenterGroupResource(context.Background(), "confluence-users", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var members []string
+	pToken := &pagination.Token{Size: 2}
+	for pages := 1; ; pages++ {
+		grants, next, _, err := builder.Grants(context.Background(), group, pToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, g := range grants {
+			members = append(members, g.Principal.Id.Resource)
+		}
+		if next == "" {
+			if pages != 2 {
+				t.Errorf("Expected 2 pages, got %d", pages)
+			}
+			break
+		}
+		pToken = &pagination.Token{Size: 2, Token: next}
+	}
+
+	if !slices.Equal(members, []string{"8a7f808a1", "8a7f808a2", "8a7f808a3"}) {
+		t.Errorf("Expected the keys of the 3 members in order, got %v", members)
+	}
+}
+
+// Tests that global permissions are granted to groups and to users by key, skipping deleted users, and that
+// space permissions Confluence does not document are skipped.
+func TestConfluenceDCSyncers_PermissionGrants(t *testing.T) {
+	syncers := newConfluenceDCConnector(test.NewFixtureRoundTripper(test.ConfluenceDCFixtures)).ResourceSyncers(context.Background())
+
+	_, permissionGrants := listAll(t, syncers[2])
+	var systemAdministrators []string
+	for _, permissionGrant := range permissionGrants {
+		if permissionGrant.Entitlement.Resource.Id.Resource == "SYSTEMADMINISTRATOR" {
+			systemAdministrators = append(systemAdministrators, permissionGrant.Principal.Id.Resource)
+		}
+	}
+	if !slices.Equal(systemAdministrators, []string{"confluence-administrators", "8a7f808a2"}) {
+		t.Errorf("Expected system administrators to be a group and alice's key, got %v", systemAdministrators)
+	}
+
+	spaces, spaceGrants := listAll(t, syncers[3])
+	if len(spaces) != 1 || spaces[0].Id.Resource != "DOCS" {
+		t.Fatalf("Expected the DOCS space, got %v", spaces)
+	}
+	if len(spaceGrants) != 2 {
+		t.Fatalf("Expected 2 space grants, got %d", len(spaceGrants))
+	}
+	viewGrant := spaceGrants[0]
+	annos := annotations.Annotations(viewGrant.Annotations)
+	if viewGrant.Entitlement.Id != entitlement.NewEntitlementID(spaces[0], "VIEWSPACE") || !annos.Contains(&v2.GrantExpandable{}) {
+		t.Errorf("Expected an expandable view grant to a group, got %v", viewGrant)
+	}
+	if spaceGrants[1].Entitlement.Id != entitlement.NewEntitlementID(spaces[0], "SETSPACEPERMISSIONS") || spaceGrants[1].Principal.Id.Resource != "8a7f808a3" {
+		t.Errorf("Expected bob to administer the space, got %v", spaceGrants[1])
+	}
+}
+
+// Tests that the usernames holding space permissions are resolved to keys once per listing, that deleted
+// users are skipped, and that failed lookups and JSON-RPC errors fail the listing.
+func TestConfluenceDCSpaceBuilder_ResolvesUsernames(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(map[string]string{
+		"POST /rpc/json-rpc/confluenceservice-v2/getSpacePermissionSets": "ConfluenceDCSpacePermissionsUsers.json",
+		"GET /rest/api/user?username=bob":                                "ConfluenceDCUserBob.json",
+		"GET /rest/api/user?username=ghost":                              "404",
+	})
+	builder := newConfluenceDCSpaceBuilder(test.NewFixtureDataCenterClient(transport, client.NewBearerAuth("pat")))
+	ctx := context.Background()
+
+	space, err := parseIntoConfluenceDCSpaceResource(ctx, &client.ConfluenceSpace{ID: 98305, Key: "DOCS", Name: "Documentation", Type: "global"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	grants, _, _, err := builder.Grants(ctx, space, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(grants) != 2 || grants[0].Principal.Id.Resource != "8a7f808a3" || grants[1].Principal.Id.Resource != "8a7f808a3" {
+		t.Errorf("Expected bob to view and edit the space, got %v", grants)
+	}
+	for _, username := range []string{"bob", "ghost"} {
+		if requests := len(transport.Requests("GET /rest/api/user?username=" + username)); requests != 1 {
+			t.Errorf("Expected %s to be looked up once, got %d requests", username, requests)
+		}
+	}
+
+	transport.SetRoute("GET /rest/api/user?username=bob", "403")
+	_, _, _, err = builder.Grants(ctx, space, &pagination.Token{})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected a failed lookup to fail with PermissionDenied, got %v", err)
+	}
+
+	transport.SetRoute("POST /rpc/json-rpc/confluenceservice-v2/getSpacePermissionSets", "ConfluenceDCRPCFailed.json")
+	_, _, _, err = builder.Grants(ctx, space, &pagination.Token{})
+	if err == nil || !strings.Contains(err.Error(), "NotPermittedException") {
+		t.Errorf("Expected the JSON-RPC error to be reported, got %v", err)
+	}
+}
+
+// Tests that group membership is provisioned by the username of the user's key.
+func TestConfluenceDCGroupBuilder_Provisioning(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(test.ConfluenceDCFixtures)
+	ctx := context.Background()
+
+	builder := newConfluenceDCGroupBuilder(test.NewFixtureDataCenterClient(transport, client.NewBearerAuth("pat")))
+
+	group, err := parseIntoDataCenterGroupResource(ctx, "confluence-administrators", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	user, err := parseIntoConfluenceDCUserResource(ctx, &client.ConfluenceUser{Username: "alice", UserKey: "8a7f808a2", DisplayName: "Alice"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	memberEntitlement := dataCenterGroupMemberEntitlement(group)
+
+	if _, err := builder.Grant(ctx, user, memberEntitlement); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	added := transport.Requests("POST /rpc/json-rpc/confluenceservice-v2/addUserToGroup")
+	if len(added) != 1 || added[0].Body != `["alice","confluence-administrators"]` {
+		t.Fatalf("Expected alice to be added, got %v", added)
+	}
+
+	_, err = builder.Revoke(ctx, &v2.Grant{Entitlement: memberEntitlement, Principal: user})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	removed := transport.Requests("POST /rpc/json-rpc/confluenceservice-v2/removeUserFromGroup")
+	if len(removed) != 1 || removed[0].Body != `["alice","confluence-administrators"]` {
+		t.Fatalf("Expected alice to be removed, got %v", removed)
+	}
+}
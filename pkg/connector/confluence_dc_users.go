@@ -0,0 +1,109 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type confluenceDCUserBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+}
+
+func (o *confluenceDCUserBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return userResourceType
+}
+
+// List returns the users of the Confluence instance.
+func (o *confluenceDCUserBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	users, nextPageToken, annotation, err := o.client.ListConfluenceUsers(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, user := range users {
+		userCopy := user
+		userResource, err := parseIntoConfluenceDCUserResource(ctx, &userCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, userResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+// Entitlements always returns an empty slice for users.
+func (o *confluenceDCUserBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for users since they don't have any entitlements.
+func (o *confluenceDCUserBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func newConfluenceDCUserBuilder(c *client.DataCenterClient) *confluenceDCUserBuilder {
+	return &confluenceDCUserBuilder{
+		resourceType: userResourceType,
+		client:       c,
+	}
+}
+
+// parseIntoConfluenceDCUserResource returns the user with its key as ID, as the key outlives username changes.
+func parseIntoConfluenceDCUserResource(_ context.Context, user *client.ConfluenceUser, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"user_id":  user.UserKey,
+		"username": user.Username,
+		"type":     user.Type,
+	}
+
+	userTraits := []resource.UserTraitOption{
+		resource.WithUserProfile(profile),
+		resource.WithStatus(v2.UserTrait_Status_STATUS_ENABLED),
+		resource.WithUserLogin(user.Username),
+	}
+
+	displayName := user.DisplayName
+	if displayName == "" {
+		displayName = user.Username
+	}
+
+	ret, err := resource.NewUserResource(
+		displayName,
+		userResourceType,
+		user.UserKey,
+		userTraits,
+		resource.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
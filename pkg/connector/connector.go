@@ -2,8 +2,9 @@ package connector
 
 import (
 	"context"
-
 	"io"
+	"os"
+	"time"
 
 	"github.com/conductorone/baton-atlassian/pkg/client"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -14,15 +15,69 @@ import (
 )
 
 type Connector struct {
-	client *client.AtlassianClient
+	client            *client.AtlassianClient
+	directory         *directory
+	changes           *changeLog
+	authPolicies      *authPolicyMembership
+	productAccessMode string
+	dataCenter        *client.DataCenterClient
+	dataCenterProduct string
+	jiraUserKeys      *jiraDCUserKeys
+	opsgenie          *client.OpsgenieClient
+	trello            *client.TrelloClient
+	statuspage        *client.StatuspageClient
+	// statuspagePermissions keeps the page permissions of Statuspage team members for the current sync.
+	statuspagePermissions *statuspagePermissions
 }
 
 // ResourceSyncers returns a ResourceSyncer for each resource type that should be synced from the upstream service.
 func (d *Connector) ResourceSyncers(ctx context.Context) []connectorbuilder.ResourceSyncer {
-	return []connectorbuilder.ResourceSyncer{
-		newUserBuilder(d.client),
-		newTeamBuilder(d.client),
+	if d.dataCenter != nil {
+		return d.dataCenterSyncers(ctx)
+	}
+	if d.opsgenie != nil {
+		return d.opsgenieSyncers(ctx)
+	}
+	if d.trello != nil {
+		return d.trelloSyncers(ctx)
+	}
+	if d.statuspage != nil {
+		return d.statuspageSyncers(ctx)
+	}
+
+	// The API tokens of managed accounts are listed per user through the user management API.
+	var userChildren []*v2.ResourceType
+	if d.client != nil && d.client.HasAdminAccess() {
+		userChildren = append(userChildren, apiTokenResourceType)
+	}
+
+	syncers := []connectorbuilder.ResourceSyncer{
+		newUserBuilder(d.directory, d.authPolicies, userChildren...),
+		newTeamBuilder(d.client, d.directory),
+	}
+
+	// Exports name groups and products without their IDs, so they are reported by name.
+	if d.client == nil {
+		return append(syncers, newExportGroupBuilder(d.directory), newExportProductRoleBuilder(d.directory))
+	}
+
+	// Compass components belong to a site.
+	if d.client.CloudID() != "" {
+		syncers = append(syncers, newCompassComponentBuilder(d.client))
 	}
+
+	// Organization groups and product access are only available through the Admin API.
+	if d.client.HasAdminAccess() {
+		syncers = append(syncers,
+			newGroupBuilder(d.client, d.changes),
+			newProductRoleBuilder(d.client, d.productAccessMode, d.changes),
+			newAuthPolicyBuilder(d.client, d.authPolicies),
+			newAPIKeyBuilder(d.client),
+			newAPITokenBuilder(d.client),
+		)
+	}
+
+	return syncers
 }
 
 // Asset takes an input AssetRef and attempts to fetch it using the connector's authenticated http client
@@ -35,27 +90,71 @@ func (d *Connector) Asset(ctx context.Context, asset *v2.AssetRef) (string, io.R
 func (d *Connector) Metadata(ctx context.Context) (*v2.ConnectorMetadata, error) {
 	return &v2.ConnectorMetadata{
 		DisplayName: "Atlassian Connector",
-		Description: "Connector to sync teams and members from Atlassian",
+		Description: "Connector to sync teams, groups and members from Atlassian",
 	}, nil
 }
 
 // Validate is called to ensure that the connector is properly configured. It should exercise any API credentials
 // to be sure that they are valid.
+// Validate also runs at the start of every sync, so it drops the state kept for the previous one.
 func (d *Connector) Validate(ctx context.Context) (annotations.Annotations, error) {
+	if d.client != nil {
+		d.client.ResetCosts()
+	}
+	d.changes.Reset()
+	d.authPolicies.Reset()
+	d.jiraUserKeys.Reset()
+	d.statuspagePermissions.Reset()
+	if d.directory != nil {
+		if err := d.directory.Invalidate(); err != nil {
+			ctxzap.Extract(ctx).Warn("error invalidating directory cache", zap.Error(err))
+		}
+	}
+
 	return nil, nil
 }
 
-// New returns a new instance of the connector.
-func New(ctx context.Context, userEmail, apiToken, organizationID, siteID string) (*Connector, error) {
+// New returns a new instance of the connector. A positive incrementalMaxAge enables incremental syncs that
+// skip the grants the audit log reports as unchanged since a previous sync at most that old.
+func New(
+	ctx context.Context,
+	auth, adminAuth client.Authenticator,
+	endpoints client.Endpoints,
+	organizationID, siteID, productAccessMode string,
+	incrementalMaxAge time.Duration,
+	opts ...client.Option,
+) (*Connector, error) {
 	l := ctxzap.Extract(ctx)
 
-	atlassianClient, err := client.New(ctx, userEmail, apiToken, organizationID, siteID)
+	atlassianClient, err := client.New(ctx, auth, adminAuth, endpoints, organizationID, siteID, opts...)
 	if err != nil {
 		l.Error("error creating Atlassian client", zap.Error(err))
 		return nil, err
 	}
 
 	return &Connector{
-		client: atlassianClient,
+		client:            atlassianClient,
+		directory:         newDirectory(newDirectorySource(atlassianClient), defaultDirectoryMemoryLimit),
+		changes:           newChangeLog(atlassianClient, incrementalMaxAge),
+		authPolicies:      newAuthPolicyMembership(atlassianClient),
+		productAccessMode: productAccessMode,
+	}, nil
+}
+
+// NewFromExport returns a connector that syncs the users, teams, groups and product access of an
+// admin.atlassian.com export instead of calling the Atlassian APIs. usersPath is the "export users" CSV and
+// teamsPath a JSON array of teams with their members; either may be empty.
+func NewFromExport(_ context.Context, usersPath, teamsPath string) (*Connector, error) {
+	for _, path := range []string{usersPath, teamsPath} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Connector{
+		directory: newDirectory(newExportSource(usersPath, teamsPath), defaultDirectoryMemoryLimit),
 	}, nil
 }
@@ -0,0 +1,223 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// crowdGroupBuilder reports the groups of a Crowd directory. Groups can be nested, so the member entitlement
+// is granted to direct user members and to child groups, which are expanded to their own members.
+type crowdGroupBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+}
+
+func (o *crowdGroupBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return groupResourceType
+}
+
+// List returns the groups of the Crowd directory.
+func (o *crowdGroupBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, groupResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	groups, nextPageToken, annotation, err := o.client.ListCrowdGroups(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, group := range groups {
+		groupCopy := group
+		groupResource, err := parseIntoCrowdGroupResource(ctx, &groupCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, groupResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+func (o *crowdGroupBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(resource, groupMemberEntitlement,
+			entitlement.WithGrantableTo(userResourceType, groupResourceType),
+			entitlement.WithDescription(fmt.Sprintf("Member of %s group", resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("%s Group Member", resource.DisplayName)),
+		),
+	}, "", nil, nil
+}
+
+// Grants returns the pages of direct user members first, then the pages of child groups.
+func (o *crowdGroupBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+	var nextPageToken string
+	var annotation annotations.Annotations
+
+	_, bag, err := unmarshalSkipToken(pToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	if bag.Current() == nil {
+		bag.Push(pagination.PageState{ResourceTypeID: groupResourceType.Id})
+		bag.Push(pagination.PageState{ResourceTypeID: userResourceType.Id})
+	}
+
+	options := client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: bag.Current().Token,
+	}
+
+	switch bag.Current().ResourceTypeID {
+	case userResourceType.Id:
+		var users []client.CrowdUser
+		users, nextPageToken, annotation, err = o.client.ListCrowdGroupUsers(ctx, resource.Id.Resource, options)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		for _, user := range users {
+			principalID := &v2.ResourceId{
+				ResourceType: userResourceType.Id,
+				Resource:     user.Name,
+			}
+			grants = append(grants, grant.NewGrant(resource, groupMemberEntitlement, principalID))
+		}
+	case groupResourceType.Id:
+		var groups []client.CrowdGroup
+		groups, nextPageToken, annotation, err = o.client.ListCrowdGroupChildGroups(ctx, resource.Id.Resource, options)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		for _, group := range groups {
+			grants = append(grants, dataCenterGroupGrant(resource, groupMemberEntitlement, group.Name))
+		}
+	default:
+		return nil, "", nil, fmt.Errorf("baton-atlassian: unexpected page state %q", bag.Current().ResourceTypeID)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return grants, nextPageToken, annotation, nil
+}
+
+// Grant makes the user a direct member of the group.
+func (o *crowdGroupBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if principal.Id.ResourceType != userResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: only users can be granted group membership")
+	}
+
+	groupName := entitlement.Resource.Id.Resource
+	username := principal.Id.Resource
+
+	annotation, err := o.client.AddCrowdGroupMember(ctx, groupName, username)
+	if err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			annotation.Append(&v2.GrantAlreadyExists{})
+			return annotation, nil
+		}
+		l.Error("failed to add group member", zap.String("group_name", groupName), zap.String("username", username), zap.Error(err))
+		return annotation, err
+	}
+
+	return annotation, nil
+}
+
+// Revoke removes the user from the direct members of the group. Membership through a child group is kept.
+func (o *crowdGroupBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	principal := grant.Principal
+	if principal.Id.ResourceType != userResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: only users can have group membership revoked")
+	}
+
+	groupName := grant.Entitlement.Resource.Id.Resource
+	username := principal.Id.Resource
+
+	annotation, err := o.client.RemoveCrowdGroupMember(ctx, groupName, username)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			annotation.Append(&v2.GrantAlreadyRevoked{})
+			return annotation, nil
+		}
+		l.Error("failed to remove group member", zap.String("group_name", groupName), zap.String("username", username), zap.Error(err))
+		return annotation, err
+	}
+
+	return annotation, nil
+}
+
+func newCrowdGroupBuilder(c *client.DataCenterClient) *crowdGroupBuilder {
+	return &crowdGroupBuilder{
+		resourceType: groupResourceType,
+		client:       c,
+	}
+}
+
+// parseIntoCrowdGroupResource returns the group with its name as ID, as Crowd groups have no other identifier.
+func parseIntoCrowdGroupResource(_ context.Context, group *client.CrowdGroup, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"group_id":    group.Name,
+		"name":        group.Name,
+		"description": group.Description,
+		"active":      group.Active,
+	}
+
+	groupTraits := []resource.GroupTraitOption{
+		resource.WithGroupProfile(profile),
+	}
+
+	ret, err := resource.NewGroupResource(
+		group.Name,
+		groupResourceType,
+		group.Name,
+		groupTraits,
+		resource.WithParentResourceID(parentResourceID),
+		resource.WithDescription(group.Description),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,159 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+)
+
+// Tests that NewDataCenter syncs a Crowd server at the base URL, authenticated as the application, with users
+// named from their first and last names.
+func TestNewDataCenter_Crowd(t *testing.T) {
+	server, transport := test.NewFixtureServer(t, test.CrowdFixtures)
+	ctx := context.Background()
+
+	c, err := NewDataCenter(ctx, DataCenterProductCrowd, server.URL, client.NewBasicAuth("baton", "secret"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	syncers := c.ResourceSyncers(ctx)
+	resourceTypes := make([]string, 0, len(syncers))
+	for _, syncer := range syncers {
+		resourceTypes = append(resourceTypes, syncer.ResourceType(ctx).Id)
+	}
+	if expected := []string{userResourceType.Id, groupResourceType.Id}; !slices.Equal(resourceTypes, expected) {
+		t.Fatalf("Expected resource types %v, got %v", expected, resourceTypes)
+	}
+
+	users, _ := listAll(t, syncers[0])
+	if len(users) != 3 || users[1].DisplayName != "Alice Smith" {
+		t.Fatalf("Expected 3 users with alice named from her first and last name, got %v", users)
+	}
+
+	if got := transport.Requests("GET /rest/usermanagement/1/search")[0].Header.Get("Authorization"); got != "Basic YmF0b246c2VjcmV0" {
+		t.Errorf("Expected requests to be authenticated as the application, got %q", got)
+	}
+}
+
+// Tests that the grants of a group page through its direct user members, then through its child groups, which
+// are granted with expandable grants.
+func TestCrowdGroupBuilder_GrantsPageMembersThenChildGroups(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(map[string]string{
+		"GET /rest/usermanagement/1/group/user/direct?groupname=crowd-administrators&start-index=0":        "CrowdDevelopersMembers.json",
+		"GET /rest/usermanagement/1/group/user/direct?groupname=crowd-administrators&start-index=2":        "CrowdAdministratorsMembers.json",
+		"GET /rest/usermanagement/1/group/child-group/direct?groupname=crowd-administrators&start-index=0": "CrowdChildGroupsFirstPage.json",
+		"GET /rest/usermanagement/1/group/child-group/direct?groupname=crowd-administrators&start-index=2": "CrowdChildGroupsLastPage.json",
+	})
+	builder := newCrowdGroupBuilder(test.NewFixtureDataCenterClient(transport, client.NewBasicAuth("baton", "secret")))
+
+	group, err := parseIntoCrowdGroupResource(context.Background(), &client.CrowdGroup{Name: "crowd-administrators"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var users, groups []string
+	pToken := &pagination.Token{Size: 2}
+	for pages := 1; ; pages++ {
+		grants, next, _, err := builder.Grants(context.Background(), group, pToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, g := range grants {
+			annos := annotations.Annotations(g.Annotations)
+			switch g.Principal.Id.ResourceType {
+			case userResourceType.Id:
+				users = append(users, g.Principal.Id.Resource)
+			case groupResourceType.Id:
+				if !annos.Contains(&v2.GrantExpandable{}) {
+					t.Errorf("Expected an expandable grant to the child group, got %v", g)
+				}
+				groups = append(groups, g.Principal.Id.Resource)
+			}
+		}
+		if next == "" {
+			if pages != 4 {
+				t.Errorf("Expected 2 pages of members and 2 of child groups, got %d pages", pages)
+			}
+			break
+		}
+		pToken = &pagination.Token{Size: 2, Token: next}
+	}
+
+	if !slices.Equal(users, []string{"alice", "bob", "admin"}) {
+		t.Errorf("Expected the members of both pages, got %v", users)
+	}
+	if !slices.Equal(groups, []string{"developers", "testers", "contractors"}) {
+		t.Errorf("Expected the child groups of both pages, got %v", groups)
+	}
+}
+
+// Tests that membership is added and removed, that existing and missing memberships are reported as already
+// granted and revoked, and that deleting a user deactivates it.
+func TestCrowdProvisioning(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(test.CrowdFixtures)
+	ctx := context.Background()
+	dataCenterClient := test.NewFixtureDataCenterClient(transport, client.NewBasicAuth("baton", "secret"))
+	groupBuilder := newCrowdGroupBuilder(dataCenterClient)
+
+	group, err := parseIntoCrowdGroupResource(ctx, &client.CrowdGroup{Name: "crowd-administrators"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	user, err := parseIntoCrowdUserResource(ctx, &client.CrowdUser{Name: "alice", Active: true, FirstName: "Alice", LastName: "Smith"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	entitlements, _, _, err := groupBuilder.Entitlements(ctx, group, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := groupBuilder.Grant(ctx, user, entitlements[0]); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	added := transport.Requests("POST /rest/usermanagement/1/group/user/direct?groupname=crowd-administrators")
+	if len(added) != 1 || added[0].Body != `{"name":"alice"}` {
+		t.Fatalf("Expected alice to be added, got %v", added)
+	}
+	transport.SetRoute("POST /rest/usermanagement/1/group/user/direct?groupname=crowd-administrators", "409")
+	annos, err := groupBuilder.Grant(ctx, user, entitlements[0])
+	if err != nil || !annos.Contains(&v2.GrantAlreadyExists{}) {
+		t.Fatalf("Expected the second grant to already exist, got %v and %v", annos, err)
+	}
+
+	revoke := &v2.Grant{Entitlement: entitlements[0], Principal: user}
+	if _, err := groupBuilder.Revoke(ctx, revoke); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	transport.SetRoute("DELETE /rest/usermanagement/1/group/user/direct?groupname=crowd-administrators&username=alice", "404")
+	annos, err = groupBuilder.Revoke(ctx, revoke)
+	if err != nil || !annos.Contains(&v2.GrantAlreadyRevoked{}) {
+		t.Fatalf("Expected the second revoke to be already revoked, got %v and %v", annos, err)
+	}
+
+	// Deletions only reach builders that are resource managers.
+	var userManager connectorbuilder.ResourceManager = newCrowdUserBuilder(dataCenterClient)
+	if _, err := userManager.Delete(ctx, user.Id); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	updated := transport.Requests("PUT /rest/usermanagement/1/user?username=alice")
+	if len(updated) != 1 {
+		t.Fatalf("Expected alice to be updated once, got %d requests", len(updated))
+	}
+	var deactivated client.CrowdUser
+	if err := json.Unmarshal([]byte(updated[0].Body), &deactivated); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if deactivated.Active || deactivated.Email != "alice@example.com" {
+		t.Errorf("Expected alice to be deactivated and otherwise unchanged, got %v", deactivated)
+	}
+}
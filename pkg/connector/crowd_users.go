@@ -0,0 +1,150 @@
+package connector
+
+import (
+	"context"
+	"strings"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type crowdUserBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+}
+
+func (o *crowdUserBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return userResourceType
+}
+
+// List returns the active and inactive users of the Crowd directory.
+func (o *crowdUserBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	users, nextPageToken, annotation, err := o.client.ListCrowdUsers(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, user := range users {
+		userCopy := user
+		userResource, err := parseIntoCrowdUserResource(ctx, &userCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, userResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+// Entitlements always returns an empty slice for users.
+func (o *crowdUserBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for users since they don't have any entitlements.
+func (o *crowdUserBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Create always fails: Crowd users are created in the directory itself. It is implemented so that the SDK routes deletions to Delete.
+func (o *crowdUserBuilder) Create(_ context.Context, _ *v2.Resource) (*v2.Resource, annotations.Annotations, error) {
+	return nil, nil, status.Error(codes.Unimplemented, "baton-atlassian: users cannot be created")
+}
+
+// Delete deactivates the user rather than removing it, so that its history and memberships are kept. The user
+// is reported as disabled by the next sync.
+func (o *crowdUserBuilder) Delete(ctx context.Context, resourceId *v2.ResourceId) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	annotation, err := o.client.DeactivateCrowdUser(ctx, resourceId.Resource)
+	if err != nil {
+		l.Error("failed to deactivate user", zap.String("username", resourceId.Resource), zap.Error(err))
+		return annotation, err
+	}
+
+	return annotation, nil
+}
+
+func newCrowdUserBuilder(c *client.DataCenterClient) *crowdUserBuilder {
+	return &crowdUserBuilder{
+		resourceType: userResourceType,
+		client:       c,
+	}
+}
+
+// parseIntoCrowdUserResource returns the user with its username as ID, as memberships are kept by username.
+func parseIntoCrowdUserResource(_ context.Context, user *client.CrowdUser, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	userStatus := v2.UserTrait_Status_STATUS_ENABLED
+	if !user.Active {
+		userStatus = v2.UserTrait_Status_STATUS_DISABLED
+	}
+
+	profile := map[string]interface{}{
+		"user_id":    user.Name,
+		"username":   user.Name,
+		"first_name": user.FirstName,
+		"last_name":  user.LastName,
+		"email":      user.Email,
+		"active":     user.Active,
+	}
+	if user.Key != "" {
+		profile["key"] = user.Key
+	}
+
+	userTraits := []resource.UserTraitOption{
+		resource.WithUserProfile(profile),
+		resource.WithStatus(userStatus),
+		resource.WithUserLogin(user.Name),
+	}
+	if user.Email != "" {
+		userTraits = append(userTraits, resource.WithEmail(user.Email, true))
+	}
+
+	displayName := user.DisplayName
+	if displayName == "" {
+		displayName = strings.TrimSpace(user.FirstName + " " + user.LastName)
+	}
+	if displayName == "" {
+		displayName = user.Name
+	}
+
+	ret, err := resource.NewUserResource(
+		displayName,
+		userResourceType,
+		user.Name,
+		userTraits,
+		resource.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,100 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	// DataCenterProductJira syncs a Jira Data Center or Server instance.
+	DataCenterProductJira = "jira"
+	// DataCenterProductConfluence syncs a Confluence Data Center or Server instance.
+	DataCenterProductConfluence = "confluence"
+	// DataCenterProductBitbucket syncs a Bitbucket Data Center or Server instance.
+	DataCenterProductBitbucket = "bitbucket"
+	// DataCenterProductCrowd syncs the users and groups of a Crowd server, authenticated as a Crowd application.
+	DataCenterProductCrowd = "crowd"
+)
+
+// DataCenterProducts lists the self-hosted products the connector can sync.
+var DataCenterProducts = []string{
+	DataCenterProductJira,
+	DataCenterProductConfluence,
+	DataCenterProductBitbucket,
+	DataCenterProductCrowd,
+}
+
+// NewDataCenter returns a connector that syncs a self-hosted Data Center or Server instance of product at
+// baseURL instead of Atlassian cloud. Users, groups and roles are reported with the cloud resource types.
+func NewDataCenter(ctx context.Context, product, baseURL string, auth client.Authenticator) (*Connector, error) {
+	l := ctxzap.Extract(ctx)
+
+	switch product {
+	case DataCenterProductJira, DataCenterProductConfluence, DataCenterProductBitbucket, DataCenterProductCrowd:
+	default:
+		return nil, fmt.Errorf("baton-atlassian: unsupported data center product %q", product)
+	}
+
+	dataCenterClient, err := client.NewDataCenterClient(ctx, baseURL, auth)
+	if err != nil {
+		l.Error("error creating Atlassian Data Center client", zap.Error(err))
+		return nil, err
+	}
+
+	return newDataCenterConnector(product, dataCenterClient), nil
+}
+
+// newDataCenterConnector returns a connector syncing product through the client, with the state its syncers
+// keep for the duration of a sync.
+func newDataCenterConnector(product string, c *client.DataCenterClient) *Connector {
+	connector := &Connector{
+		dataCenter:        c,
+		dataCenterProduct: product,
+	}
+	if product == DataCenterProductJira {
+		connector.jiraUserKeys = newJiraDCUserKeys(c)
+	}
+
+	return connector
+}
+
+// dataCenterSyncers returns the syncers of the Data Center product.
+func (d *Connector) dataCenterSyncers(_ context.Context) []connectorbuilder.ResourceSyncer {
+	switch d.dataCenterProduct {
+	case DataCenterProductJira:
+		return []connectorbuilder.ResourceSyncer{
+			newJiraDCUserBuilder(d.dataCenter),
+			newJiraDCGroupBuilder(d.dataCenter),
+			newJiraDCApplicationRoleBuilder(d.dataCenter),
+			newJiraDCProjectRoleBuilder(d.dataCenter, d.jiraUserKeys),
+		}
+	case DataCenterProductConfluence:
+		return []connectorbuilder.ResourceSyncer{
+			newConfluenceDCUserBuilder(d.dataCenter),
+			newConfluenceDCGroupBuilder(d.dataCenter),
+			newConfluenceDCGlobalPermissionBuilder(d.dataCenter),
+			newConfluenceDCSpaceBuilder(d.dataCenter),
+		}
+	case DataCenterProductBitbucket:
+		return []connectorbuilder.ResourceSyncer{
+			newBitbucketDCUserBuilder(d.dataCenter),
+			newBitbucketDCGroupBuilder(d.dataCenter),
+			newBitbucketDCGlobalPermissionBuilder(d.dataCenter),
+			newBitbucketDCProjectBuilder(d.dataCenter),
+			newBitbucketDCRepositoryBuilder(d.dataCenter),
+			newBitbucketDCAccessTokenBuilder(d.dataCenter),
+			newBitbucketDCSSHKeyBuilder(d.dataCenter),
+		}
+	case DataCenterProductCrowd:
+		return []connectorbuilder.ResourceSyncer{
+			newCrowdUserBuilder(d.dataCenter),
+			newCrowdGroupBuilder(d.dataCenter),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,410 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// defaultDirectoryMemoryLimit is the number of teams and of users the directory keeps in memory before
+// spilling the rest to a temporary file.
+const defaultDirectoryMemoryLimit = 10000
+
+// directoryTeam is a team together with all of its members.
+type directoryTeam struct {
+	Team    client.Team         `json:"team"`
+	Members []client.MemberEdge `json:"members"`
+}
+
+// directoryUser is a user of the directory together with what its source reports beyond the account itself:
+// the users CSV of an export has the status, the last time the user was seen, and the names of the groups
+// and products the user has access to. Unset fields leave the user enabled and without a last login, as for
+// an API sync.
+type directoryUser struct {
+	client.Member
+	Status    v2.UserTrait_Status_Status `json:"status,omitempty"`
+	LastLogin time.Time                  `json:"lastLogin"`
+	Groups    []string                   `json:"groups,omitempty"`
+	Products  []string                   `json:"products,omitempty"`
+}
+
+// directorySource lists the teams of the organization with their members. The Atlassian client reads them
+// from the Teams query, an exportSource from the files of an admin.atlassian.com export.
+type directorySource interface {
+	ListTeams(ctx context.Context, options client.PageOptions) ([]client.TeamEdge, string, annotations.Annotations, error)
+}
+
+// userSource is implemented by directory sources that also list the users outside of any team.
+type userSource interface {
+	ListUsers(ctx context.Context) ([]directoryUser, error)
+}
+
+// adminDirectorySource lists the teams of the organization through the Teams query and all of its users
+// through the Admin API, so that users who belong to no team are synced too. Group members, product role
+// holders and API token owners refer to them.
+type adminDirectorySource struct {
+	*client.AtlassianClient
+}
+
+// newDirectorySource returns the client itself when it has no Admin API access, and otherwise a source that
+// also lists the users of the organization.
+func newDirectorySource(c *client.AtlassianClient) directorySource {
+	if !c.HasAdminAccess() {
+		return c
+	}
+	return &adminDirectorySource{AtlassianClient: c}
+}
+
+// ListUsers returns every user of the organization.
+func (s *adminDirectorySource) ListUsers(ctx context.Context) ([]directoryUser, error) {
+	var members []directoryUser
+
+	pageToken := ""
+	for {
+		users, nextPageToken, _, err := s.ListDirectoryUsers(ctx, client.PageOptions{
+			PageSize:  client.ItemsPerPage,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range users {
+			members = append(members, directoryUser{Member: client.Member{
+				ID:        userResourceID(user.AccountID),
+				AccountID: user.AccountID,
+				Name:      user.Name,
+			}})
+		}
+
+		if nextPageToken == "" {
+			return members, nil
+		}
+		pageToken = nextPageToken
+	}
+}
+
+// directory caches the teams, team members and users of the organization for the duration of a sync. It is
+// populated from its source the first time a builder needs it and shared by all builders, so every team
+// and member is fetched once per sync. Invalidate clears it before the next sync.
+type directory struct {
+	source      directorySource
+	memoryLimit int
+
+	mtx     sync.Mutex
+	loaded  bool
+	teamIDs []string
+	teams   *spillStore[directoryTeam]
+	userIDs []string
+	users   *spillStore[directoryUser]
+}
+
+func newDirectory(source directorySource, memoryLimit int) *directory {
+	return &directory{
+		source:      source,
+		memoryLimit: memoryLimit,
+	}
+}
+
+// Teams returns up to limit teams starting at offset, and the offset of the next page or 0 when there are
+// no more teams.
+func (d *directory) Teams(ctx context.Context, offset, limit int) ([]directoryTeam, int, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if err := d.load(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	return getPage(d.teams, d.teamIDs, offset, limit)
+}
+
+// Team returns the team with the given ID.
+func (d *directory) Team(ctx context.Context, teamID string) (directoryTeam, bool, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if err := d.load(ctx); err != nil {
+		return directoryTeam{}, false, err
+	}
+
+	return d.teams.get(teamID)
+}
+
+// Users returns up to limit users starting at offset, and the offset of the next page or 0 when there are
+// no more users. Users that belong to several teams are returned once.
+func (d *directory) Users(ctx context.Context, offset, limit int) ([]directoryUser, int, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if err := d.load(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	return getPage(d.users, d.userIDs, offset, limit)
+}
+
+// User returns the user with the given account ID.
+func (d *directory) User(ctx context.Context, accountID string) (directoryUser, bool, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if err := d.load(ctx); err != nil {
+		return directoryUser{}, false, err
+	}
+
+	return d.users.get(accountID)
+}
+
+// Invalidate drops the cached directory and removes its temporary files. The next lookup reloads it.
+func (d *directory) Invalidate() error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	return d.reset()
+}
+
+func (d *directory) reset() error {
+	var err error
+	if d.teams != nil {
+		err = errors.Join(err, d.teams.close())
+	}
+	if d.users != nil {
+		err = errors.Join(err, d.users.close())
+	}
+
+	d.loaded = false
+	d.teamIDs = nil
+	d.teams = nil
+	d.userIDs = nil
+	d.users = nil
+
+	return err
+}
+
+// load fetches every team with its members, then the users of sources that also list the users outside of
+// any team. It must be called with d.mtx held.
+func (d *directory) load(ctx context.Context) error {
+	if d.loaded {
+		return nil
+	}
+
+	d.teams = newSpillStore[directoryTeam](d.memoryLimit)
+	d.users = newSpillStore[directoryUser](d.memoryLimit)
+
+	pageToken := ""
+	for {
+		teams, nextPageToken, _, err := d.source.ListTeams(ctx, client.PageOptions{
+			PageSize:  client.ItemsPerPage,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return errors.Join(err, d.reset())
+		}
+
+		for _, teamEdge := range teams {
+			if err := d.addTeam(teamEdge.Node.Team); err != nil {
+				return errors.Join(err, d.reset())
+			}
+		}
+
+		if nextPageToken == "" {
+			break
+		}
+		pageToken = nextPageToken
+	}
+
+	if source, ok := d.source.(userSource); ok {
+		users, err := source.ListUsers(ctx)
+		if err != nil {
+			return errors.Join(err, d.reset())
+		}
+		for _, user := range users {
+			if err := d.setUser(user); err != nil {
+				return errors.Join(err, d.reset())
+			}
+		}
+	}
+
+	d.loaded = true
+
+	ctxzap.Extract(ctx).Debug(
+		"loaded directory",
+		zap.Int("teams", len(d.teamIDs)),
+		zap.Int("users", len(d.userIDs)),
+	)
+
+	return nil
+}
+
+func (d *directory) addTeam(team client.TeamEdgeNodeTeam) error {
+	if _, ok, err := d.teams.get(team.ID); err != nil || ok {
+		return err
+	}
+
+	err := d.teams.put(team.ID, directoryTeam{
+		Team:    team.Team,
+		Members: team.Members.Edges,
+	})
+	if err != nil {
+		return err
+	}
+	d.teamIDs = append(d.teamIDs, team.ID)
+
+	for _, member := range team.Members.Edges {
+		if err := d.addUser(directoryUser{Member: member.Node.Member}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addUser adds the user unless it is already in the directory, as members of several teams are listed once
+// per team.
+func (d *directory) addUser(user directoryUser) error {
+	if _, ok, err := d.users.get(user.AccountID); err != nil || ok {
+		return err
+	}
+
+	if err := d.users.put(user.AccountID, user); err != nil {
+		return err
+	}
+	d.userIDs = append(d.userIDs, user.AccountID)
+
+	return nil
+}
+
+// setUser adds the user, or replaces the team member with the same account ID with the user source's more
+// complete record.
+func (d *directory) setUser(user directoryUser) error {
+	_, ok, err := d.users.get(user.AccountID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return d.addUser(user)
+	}
+
+	return d.users.put(user.AccountID, user)
+}
+
+func getPage[T any](store *spillStore[T], keys []string, offset, limit int) ([]T, int, error) {
+	if offset >= len(keys) {
+		return nil, 0, nil
+	}
+
+	end := min(offset+limit, len(keys))
+	values := make([]T, 0, end-offset)
+	for _, key := range keys[offset:end] {
+		value, _, err := store.get(key)
+		if err != nil {
+			return nil, 0, err
+		}
+		values = append(values, value)
+	}
+
+	if end == len(keys) {
+		return values, 0, nil
+	}
+	return values, end, nil
+}
+
+// spillStore keeps up to limit values in memory and appends the rest, JSON encoded, to a temporary file.
+// Only the offsets of the spilled values stay in memory.
+type spillStore[T any] struct {
+	limit  int
+	memory map[string]T
+	spans  map[string]span
+	file   *os.File
+	size   int64
+}
+
+type span struct {
+	offset int64
+	length int
+}
+
+func newSpillStore[T any](limit int) *spillStore[T] {
+	return &spillStore[T]{
+		limit:  limit,
+		memory: make(map[string]T),
+		spans:  make(map[string]span),
+	}
+}
+
+func (s *spillStore[T]) put(key string, value T) error {
+	if _, ok := s.memory[key]; ok || len(s.memory) < s.limit {
+		s.memory[key] = value
+		return nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if s.file == nil {
+		s.file, err = os.CreateTemp("", "baton-atlassian-directory-*.json")
+		if err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.WriteAt(data, s.size)
+	if err != nil {
+		return err
+	}
+	s.spans[key] = span{offset: s.size, length: n}
+	s.size += int64(n)
+
+	return nil
+}
+
+func (s *spillStore[T]) get(key string) (T, bool, error) {
+	var value T
+
+	if value, ok := s.memory[key]; ok {
+		return value, true, nil
+	}
+
+	sp, ok := s.spans[key]
+	if !ok {
+		return value, false, nil
+	}
+
+	data := make([]byte, sp.length)
+	if _, err := s.file.ReadAt(data, sp.offset); err != nil {
+		return value, false, err
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, false, err
+	}
+
+	return value, true, nil
+}
+
+// spilled returns the number of values stored in the temporary file.
+func (s *spillStore[T]) spilled() int {
+	return len(s.spans)
+}
+
+func (s *spillStore[T]) close() error {
+	if s.file == nil {
+		return nil
+	}
+
+	name := s.file.Name()
+	err := s.file.Close()
+	s.file = nil
+	return errors.Join(err, os.Remove(name))
+}
@@ -0,0 +1,139 @@
+package connector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/test"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+// newTeamsServer serves the Teams mock response and counts the requests it receives.
+func newTeamsServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	body, err := ReadFile("Teams.json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func newTestDirectory(server *httptest.Server, memoryLimit int) *directory {
+	atlassianClient := client.NewClient("", "", "", client.Endpoints{GraphQL: server.URL}, test.OrganizationID, "", uhttp.NewBaseHttpClient(server.Client()))
+	return newDirectory(atlassianClient, memoryLimit)
+}
+
+// Run with -race: builders share the directory and may look it up concurrently.
+func TestDirectory_ConcurrentLookupsLoadOnce(t *testing.T) {
+	server, requests := newTeamsServer(t)
+	// A limit of one entry spills every other team and user to disk.
+	d := newTestDirectory(server, 1)
+	t.Cleanup(func() { _ = d.Invalidate() })
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			teams, _, err := d.Teams(ctx, 0, 10)
+			if err != nil || len(teams) != 2 {
+				t.Errorf("Expected 2 teams, got %d (%v)", len(teams), err)
+			}
+			users, _, err := d.Users(ctx, 0, 10)
+			if err != nil || len(users) != 2 {
+				t.Errorf("Expected 2 users, got %d (%v)", len(users), err)
+			}
+			team, ok, err := d.Team(ctx, "ari:cloud:identity::team/teamTest2")
+			if err != nil || !ok || len(team.Members) != 2 {
+				t.Errorf("Expected team 2 with 2 members, got %+v (%v)", team, err)
+			}
+			user, ok, err := d.User(ctx, test.UserIDs[1])
+			if err != nil || !ok || user.Name != "User 2" {
+				t.Errorf("Expected User 2, got %+v (%v)", user, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The teams come with all of their members in a single request.
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected the directory to be loaded once with 1 request, got %d", got)
+	}
+	if d.teams.spilled() != 1 || d.users.spilled() != 1 {
+		t.Errorf("Expected one team and one user to spill, got %d and %d", d.teams.spilled(), d.users.spilled())
+	}
+}
+
+func TestDirectory_InvalidateReloads(t *testing.T) {
+	server, requests := newTeamsServer(t)
+	d := newTestDirectory(server, 1)
+
+	ctx := context.Background()
+	if _, _, err := d.Teams(ctx, 0, 10); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	spillFile := d.teams.file.Name()
+
+	if err := d.Invalidate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := os.Stat(spillFile); !os.IsNotExist(err) {
+		t.Errorf("Expected the spill file to be removed, got %v", err)
+	}
+
+	if _, _, err := d.Teams(ctx, 0, 10); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { _ = d.Invalidate() })
+
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected the directory to be loaded twice with 2 requests, got %d", got)
+	}
+}
+
+func TestUserBuilder_ListPagesDeduplicatedUsers(t *testing.T) {
+	server, _ := newTeamsServer(t)
+	d := newTestDirectory(server, defaultDirectoryMemoryLimit)
+	builder := newUserBuilder(d, nil)
+
+	ctx := context.Background()
+	var ids []string
+	pToken := &pagination.Token{Size: 1}
+	for {
+		resources, nextPageToken, _, err := builder.List(ctx, nil, pToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, r := range resources {
+			ids = append(ids, r.Id.Resource)
+		}
+		if nextPageToken == "" {
+			break
+		}
+		pToken = &pagination.Token{Size: 1, Token: nextPageToken}
+	}
+
+	// User 1 belongs to both teams but is listed once.
+	if len(ids) != 2 || ids[0] != userResourceID(test.UserIDs[0]) || ids[1] != userResourceID(test.UserIDs[1]) {
+		t.Errorf("Unexpected users %v", ids)
+	}
+}
@@ -0,0 +1,244 @@
+package connector
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errExportReadOnly is returned by provisioning calls when syncing from an export.
+var errExportReadOnly = status.Error(codes.Unimplemented, "baton-atlassian: provisioning is not available when syncing from an export")
+
+const (
+	exportUserIDColumn     = "user id"
+	exportUserNameColumn   = "user name"
+	exportUserStatusColumn = "user status"
+	exportGroupsColumn     = "groups"
+	// exportLastSeenPrefix starts the per-product "Last seen in <product> - <site>" columns.
+	exportLastSeenPrefix = "last seen"
+	// exportProductSeparator separates the product from the site in the per-product "<product> - <site>"
+	// access columns.
+	exportProductSeparator = " - "
+)
+
+// exportNoAccessValues are the values of the product access columns of users without access to the product.
+var exportNoAccessValues = []string{"", "no", "false", "no access"}
+
+// exportLastSeenLayouts are the date formats of the last seen columns. Other values, such as "Never", leave
+// the user without a last login.
+var exportLastSeenLayouts = []string{time.RFC3339, time.DateOnly}
+
+// exportSource serves the directory from the files of an admin.atlassian.com export instead of the API: the
+// "export users" CSV and a JSON array of teams with their members. Users and teams get the same resource IDs
+// and profiles as in an API sync.
+type exportSource struct {
+	usersPath string
+	teamsPath string
+}
+
+// exportTeam is a team in the teams JSON export.
+type exportTeam struct {
+	ID                 string         `json:"id"`
+	OrganizationID     string         `json:"organizationId"`
+	DisplayName        string         `json:"displayName"`
+	Description        string         `json:"description"`
+	MembershipSettings string         `json:"membershipSettings"`
+	Members            []exportMember `json:"members"`
+}
+
+type exportMember struct {
+	AccountID string `json:"accountId"`
+	Name      string `json:"name"`
+	Role      string `json:"role"`
+}
+
+func newExportSource(usersPath, teamsPath string) *exportSource {
+	return &exportSource{
+		usersPath: usersPath,
+		teamsPath: teamsPath,
+	}
+}
+
+// ListTeams returns every team of the export in a single page.
+func (s *exportSource) ListTeams(_ context.Context, _ client.PageOptions) ([]client.TeamEdge, string, annotations.Annotations, error) {
+	if s.teamsPath == "" {
+		return nil, "", nil, nil
+	}
+
+	data, err := os.ReadFile(s.teamsPath)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	var teams []exportTeam
+	if err := json.Unmarshal(data, &teams); err != nil {
+		return nil, "", nil, fmt.Errorf("baton-atlassian: invalid teams export %s: %w", s.teamsPath, err)
+	}
+
+	edges := make([]client.TeamEdge, 0, len(teams))
+	for _, team := range teams {
+		if team.ID == "" {
+			return nil, "", nil, fmt.Errorf("baton-atlassian: team %q in %s has no id", team.DisplayName, s.teamsPath)
+		}
+
+		var edge client.TeamEdge
+		edge.Node.Team.Team = client.Team{
+			ID:                 team.ID,
+			OrganizationID:     team.OrganizationID,
+			DisplayName:        team.DisplayName,
+			Description:        team.Description,
+			MembershipSettings: team.MembershipSettings,
+		}
+		for _, member := range team.Members {
+			role := member.Role
+			if role == "" {
+				role = teamMembershipRoles[0]
+			}
+			edge.Node.Team.Members.Edges = append(edge.Node.Team.Members.Edges, client.MemberEdge{
+				Node: client.MemberEdgeNode{
+					Member: exportUser(member.AccountID, member.Name),
+					Role:   role,
+				},
+			})
+		}
+		edges = append(edges, edge)
+	}
+
+	return edges, "", nil, nil
+}
+
+// ListUsers returns the users of the users CSV export with their status, the last time they were seen in
+// any product, the groups of the comma-separated groups column and the products of the "<product> - <site>"
+// columns they have access to.
+func (s *exportSource) ListUsers(_ context.Context) ([]directoryUser, error) {
+	if s.usersPath == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(s.usersPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("baton-atlassian: invalid users export %s: %w", s.usersPath, err)
+	}
+
+	idColumn, nameColumn, statusColumn, groupsColumn := -1, -1, -1, -1
+	var lastSeenColumns []int
+	productColumns := make(map[int]string)
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		column := strings.ToLower(name)
+		switch {
+		case column == exportUserIDColumn:
+			idColumn = i
+		case column == exportUserNameColumn:
+			nameColumn = i
+		case column == exportUserStatusColumn:
+			statusColumn = i
+		case column == exportGroupsColumn:
+			groupsColumn = i
+		case strings.HasPrefix(column, exportLastSeenPrefix):
+			lastSeenColumns = append(lastSeenColumns, i)
+		case strings.Contains(column, exportProductSeparator):
+			productColumns[i] = name
+		}
+	}
+	if idColumn < 0 || nameColumn < 0 {
+		return nil, fmt.Errorf("baton-atlassian: users export %s needs %q and %q columns", s.usersPath, "User id", "User name")
+	}
+
+	var users []directoryUser
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("baton-atlassian: invalid users export %s: %w", s.usersPath, err)
+		}
+		if idColumn >= len(record) || record[idColumn] == "" {
+			continue
+		}
+
+		user := directoryUser{Member: exportUser(record[idColumn], exportField(record, nameColumn))}
+		user.Status = exportUserStatus(exportField(record, statusColumn))
+		for _, column := range lastSeenColumns {
+			if lastSeen, ok := parseExportLastSeen(exportField(record, column)); ok && lastSeen.After(user.LastLogin) {
+				user.LastLogin = lastSeen
+			}
+		}
+		for _, group := range strings.Split(exportField(record, groupsColumn), ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				user.Groups = append(user.Groups, group)
+			}
+		}
+		for column, product := range productColumns {
+			if !slices.Contains(exportNoAccessValues, strings.ToLower(exportField(record, column))) {
+				user.Products = append(user.Products, product)
+			}
+		}
+		slices.Sort(user.Products)
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// exportField returns the value of the column, or an empty string when the record or the header lacks it.
+func exportField(record []string, column int) string {
+	if column < 0 || column >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[column])
+}
+
+// exportUserStatus maps the "User status" column to the user trait status. Users without a status are left
+// enabled, as in an API sync.
+func exportUserStatus(status string) v2.UserTrait_Status_Status {
+	switch strings.ToLower(status) {
+	case "", "active":
+		return v2.UserTrait_Status_STATUS_UNSPECIFIED
+	case "deleted", "closed":
+		return v2.UserTrait_Status_STATUS_DELETED
+	default:
+		return v2.UserTrait_Status_STATUS_DISABLED
+	}
+}
+
+func parseExportLastSeen(value string) (time.Time, bool) {
+	for _, layout := range exportLastSeenLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// exportUser returns the user as the Teams query would.
+func exportUser(accountID, name string) client.Member {
+	return client.Member{
+		AccountID: accountID,
+		ID:        userResourceID(accountID),
+		Name:      name,
+	}
+}
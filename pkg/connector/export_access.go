@@ -0,0 +1,234 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// exportGroupBuilder reports the groups named in the groups column of the users CSV export. The export has
+// no group IDs, so groups are identified by name, as in Data Center modes.
+type exportGroupBuilder struct {
+	resourceType *v2.ResourceType
+	directory    *directory
+}
+
+func (o *exportGroupBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return groupResourceType
+}
+
+// List returns every group a user of the export is a member of.
+func (o *exportGroupBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	return listExportAccess(ctx, o.directory, groupResourceType, pToken, exportUserGroups, func(name string) (*v2.Resource, error) {
+		return parseIntoDataCenterGroupResource(ctx, name, nil)
+	})
+}
+
+func (o *exportGroupBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return []*v2.Entitlement{dataCenterGroupMemberEntitlement(resource)}, "", nil, nil
+}
+
+// Grants returns a grant to every user of the export listing the group.
+func (o *exportGroupBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return listExportAccessGrants(ctx, o.directory, resource, groupMemberEntitlement, pToken, exportUserGroups)
+}
+
+func newExportGroupBuilder(d *directory) *exportGroupBuilder {
+	return &exportGroupBuilder{
+		resourceType: groupResourceType,
+		directory:    d,
+	}
+}
+
+// exportProductRoleBuilder reports the user role of every product named by a product access column of the
+// users CSV export. The export has no workspace IDs, so products are identified by the column name, such as
+// "user:Jira - acme". It only tells whether users can use the product, not whether they administer it.
+type exportProductRoleBuilder struct {
+	resourceType *v2.ResourceType
+	directory    *directory
+}
+
+func (o *exportProductRoleBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return productRoleResourceType
+}
+
+// List returns the user role of every product a user of the export has access to.
+func (o *exportProductRoleBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	return listExportAccess(ctx, o.directory, productRoleResourceType, pToken, exportUserProducts, func(name string) (*v2.Resource, error) {
+		return parseIntoExportProductRoleResource(ctx, name, nil)
+	})
+}
+
+func (o *exportProductRoleBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	assigmentOptions := []entitlement.EntitlementOption{
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDescription(fmt.Sprintf("Assigned the %s product role", resource.DisplayName)),
+		entitlement.WithDisplayName(resource.DisplayName),
+	}
+
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(resource, productRoleAssignedEntitlement, assigmentOptions...),
+	}, "", nil, nil
+}
+
+// Grants returns a grant to every user of the export with access to the product.
+func (o *exportProductRoleBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return listExportAccessGrants(ctx, o.directory, resource, productRoleAssignedEntitlement, pToken, exportUserProducts)
+}
+
+func newExportProductRoleBuilder(d *directory) *exportProductRoleBuilder {
+	return &exportProductRoleBuilder{
+		resourceType: productRoleResourceType,
+		directory:    d,
+	}
+}
+
+// parseIntoExportProductRoleResource returns the user role of the product named by a product access column,
+// such as "Jira - acme".
+func parseIntoExportProductRoleResource(_ context.Context, product string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	productName, site, _ := strings.Cut(product, " - ")
+	role := productRoles[0]
+
+	profile := map[string]interface{}{
+		"product": productName,
+		"site":    site,
+		"role_id": role.roleID,
+	}
+
+	roleTraits := []resource.RoleTraitOption{
+		resource.WithRoleProfile(profile),
+	}
+
+	ret, err := resource.NewRoleResource(
+		fmt.Sprintf("%s %s on %s", productName, role.displayName, site),
+		productRoleResourceType,
+		productRoleResourceID(role, product),
+		roleTraits,
+		resource.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func exportUserGroups(user directoryUser) []string {
+	return user.Groups
+}
+
+func exportUserProducts(user directoryUser) []string {
+	return user.Products
+}
+
+// listExportAccess returns a page of the groups or products, as given by access, that users of the export
+// hold, sorted by name.
+func listExportAccess(
+	ctx context.Context,
+	d *directory,
+	resourceType *v2.ResourceType,
+	pToken *pagination.Token,
+	access func(directoryUser) []string,
+	parse func(name string) (*v2.Resource, error),
+) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, offset, err := getOffsetToken(pToken, resourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	var names []string
+	for next := 0; ; {
+		users, nextOffset, err := d.Users(ctx, next, getPageSize(nil))
+		if err != nil {
+			return nil, "", nil, err
+		}
+		for _, user := range users {
+			names = append(names, access(user)...)
+		}
+		if nextOffset == 0 {
+			break
+		}
+		next = nextOffset
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	end := min(offset+getPageSize(pToken), len(names))
+	for _, name := range names[min(offset, end):end] {
+		r, err := parse(name)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		resources = append(resources, r)
+	}
+
+	next := 0
+	if end < len(names) {
+		next = end
+	}
+	nextPageToken, err := marshalOffsetToken(bag, next)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return resources, nextPageToken, nil, nil
+}
+
+// listExportAccessGrants returns the grants of the entitlement to a page of the users of the export whose
+// access, as given by access, includes the resource.
+func listExportAccessGrants(
+	ctx context.Context,
+	d *directory,
+	resource *v2.Resource,
+	entitlementName string,
+	pToken *pagination.Token,
+	access func(directoryUser) []string,
+) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+
+	bag, offset, err := getOffsetToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	name := resource.Id.Resource
+	if resource.Id.ResourceType == productRoleResourceType.Id {
+		_, name, err = parseProductRoleResourceID(name)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	users, next, err := d.Users(ctx, offset, getPageSize(pToken))
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, user := range users {
+		if !slices.Contains(access(user), name) {
+			continue
+		}
+		principalID := &v2.ResourceId{
+			ResourceType: userResourceType.Id,
+			Resource:     user.ID,
+		}
+		grants = append(grants, grant.NewGrant(resource, entitlementName, principalID))
+	}
+
+	nextPageToken, err := marshalOffsetToken(bag, next)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return grants, nextPageToken, nil, nil
+}
@@ -0,0 +1,209 @@
+package connector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-atlassian/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// listAll returns every resource of the syncer and the grants of each of them.
+func listAll(t *testing.T, syncer connectorbuilder.ResourceSyncer) ([]*v2.Resource, []*v2.Grant) {
+	t.Helper()
+
+	ctx := context.Background()
+	var resources []*v2.Resource
+	var grants []*v2.Grant
+
+	token := &pagination.Token{Size: 1}
+	for {
+		page, next, _, err := syncer.List(ctx, nil, token)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resources = append(resources, page...)
+		if next == "" {
+			break
+		}
+		token = &pagination.Token{Size: 1, Token: next}
+	}
+
+	for _, resource := range resources {
+		token = &pagination.Token{}
+		for {
+			page, next, _, err := syncer.Grants(ctx, resource, token)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			grants = append(grants, page...)
+			if next == "" {
+				break
+			}
+			token = &pagination.Token{Token: next}
+		}
+	}
+
+	return resources, grants
+}
+
+// protoJSON renders the message with sorted map keys, as the profiles packed in annotations may be marshalled
+// in any order.
+func protoJSON(t *testing.T, m proto.Message) string {
+	t.Helper()
+
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return string(data)
+}
+
+// Tests that syncing from an export produces the same users, teams and team grants as the API, apart from
+// the last logins only the export has.
+func TestNewFromExport_MatchesAPISync(t *testing.T) {
+	server, _ := newTeamsServer(t)
+	api := newTestDirectory(server, defaultDirectoryMemoryLimit)
+	t.Cleanup(func() { _ = api.Invalidate() })
+
+	connector, err := NewFromExport(context.Background(), "../../test/mockResponses/ExportUsers.csv", "../../test/mockResponses/ExportTeams.json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { _ = connector.directory.Invalidate() })
+
+	syncers := connector.ResourceSyncers(context.Background())
+	if len(syncers) != 4 {
+		t.Fatalf("Expected user, team, group and product role syncers, got %d", len(syncers))
+	}
+
+	for _, pair := range []struct {
+		api    connectorbuilder.ResourceSyncer
+		export connectorbuilder.ResourceSyncer
+	}{
+		{newUserBuilder(api, nil), syncers[0]},
+		{newTeamBuilder(nil, api), syncers[1]},
+	} {
+		apiResources, apiGrants := listAll(t, pair.api)
+		exportResources, exportGrants := listAll(t, pair.export)
+		for _, r := range exportResources {
+			clearLastLogin(t, r)
+		}
+
+		if len(exportResources) != len(apiResources) || len(exportGrants) != len(apiGrants) {
+			t.Fatalf("Expected %d resources and %d grants, got %d and %d",
+				len(apiResources), len(apiGrants), len(exportResources), len(exportGrants))
+		}
+		for i := range apiResources {
+			if want, got := protoJSON(t, apiResources[i]), protoJSON(t, exportResources[i]); want != got {
+				t.Errorf("Expected resource %s, got %s", want, got)
+			}
+		}
+		for i := range apiGrants {
+			if want, got := protoJSON(t, apiGrants[i]), protoJSON(t, exportGrants[i]); want != got {
+				t.Errorf("Expected grant %s, got %s", want, got)
+			}
+		}
+	}
+}
+
+// clearLastLogin removes the last login from the user trait of the resource, if it has one.
+func clearLastLogin(t *testing.T, r *v2.Resource) {
+	t.Helper()
+
+	trait := &v2.UserTrait{}
+	annos := annotations.Annotations(r.Annotations)
+	ok, err := annos.Pick(trait)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !ok {
+		return
+	}
+	trait.LastLogin = nil
+	annos.Update(trait)
+	r.Annotations = annos
+}
+
+// Tests that the status and the latest last seen date of the users CSV end up in the user trait.
+func TestExportSource_ListUsersStatusAndLastSeen(t *testing.T) {
+	connector, err := NewFromExport(context.Background(), "../../test/mockResponses/ExportUsersStatus.csv", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { _ = connector.directory.Invalidate() })
+
+	users, _ := listAll(t, connector.ResourceSyncers(context.Background())[0])
+	if len(users) != 4 {
+		t.Fatalf("Expected 4 users, got %d", len(users))
+	}
+
+	expected := []struct {
+		status    v2.UserTrait_Status_Status
+		lastLogin time.Time
+	}{
+		{v2.UserTrait_Status_STATUS_ENABLED, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)},
+		{v2.UserTrait_Status_STATUS_DISABLED, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{v2.UserTrait_Status_STATUS_DISABLED, time.Time{}},
+		{v2.UserTrait_Status_STATUS_DELETED, time.Time{}},
+	}
+	for i, user := range users {
+		trait := &v2.UserTrait{}
+		annos := annotations.Annotations(user.Annotations)
+		if _, err := annos.Pick(trait); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if trait.GetStatus().GetStatus() != expected[i].status {
+			t.Errorf("Expected %s to be %s, got %s", user.DisplayName, expected[i].status, trait.GetStatus().GetStatus())
+		}
+		if lastLogin := trait.GetLastLogin(); (lastLogin == nil) != expected[i].lastLogin.IsZero() ||
+			(lastLogin != nil && !lastLogin.AsTime().Equal(expected[i].lastLogin)) {
+			t.Errorf("Expected %s to have last logged in at %s, got %v", user.DisplayName, expected[i].lastLogin, trait.GetLastLogin())
+		}
+	}
+}
+
+// Tests that the groups column and the product access columns of the users CSV become group memberships and
+// product user roles, identified by name.
+func TestNewFromExport_GroupsAndProductAccess(t *testing.T) {
+	connector, err := NewFromExport(context.Background(), "../../test/mockResponses/ExportUsersAccess.csv", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { _ = connector.directory.Invalidate() })
+
+	syncers := connector.ResourceSyncers(context.Background())
+
+	groups, groupGrants := listAll(t, syncers[2])
+	if len(groups) != 2 || groups[0].Id.Resource != "jira-users" || groups[1].Id.Resource != "site-admins" {
+		t.Fatalf("Expected the jira-users and site-admins groups, got %v", groups)
+	}
+	members := make(map[string][]string)
+	for _, groupGrant := range groupGrants {
+		members[groupGrant.Entitlement.Resource.Id.Resource] = append(members[groupGrant.Entitlement.Resource.Id.Resource], groupGrant.Principal.Id.Resource)
+	}
+	if len(members["jira-users"]) != 2 || len(members["site-admins"]) != 1 {
+		t.Errorf("Expected 2 jira-users members and 1 site-admins member, got %v", members)
+	}
+
+	roles, roleGrants := listAll(t, syncers[3])
+	if len(roles) != 2 || roles[0].Id.Resource != "user:Confluence - acme" || roles[1].Id.Resource != "user:Jira - acme" {
+		t.Fatalf("Expected the user roles of Confluence and Jira, got %v", roles)
+	}
+	if roles[1].DisplayName != "Jira User on acme" {
+		t.Errorf("Expected the role to be named like an API sync, got %q", roles[1].DisplayName)
+	}
+	holders := make(map[string]string)
+	for _, roleGrant := range roleGrants {
+		holders[roleGrant.Entitlement.Resource.Id.Resource] += roleGrant.Principal.Id.Resource
+	}
+	if holders["user:Jira - acme"] != userResourceID(test.UserIDs[0]) || holders["user:Confluence - acme"] != userResourceID(test.UserIDs[1]) {
+		t.Errorf("Expected User 1 to use Jira and User 2 Confluence, got %v", holders)
+	}
+}
@@ -0,0 +1,257 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const groupMemberEntitlement = "member"
+
+type groupBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.AtlassianClient
+	changes      *changeLog
+}
+
+func (o *groupBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return groupResourceType
+}
+
+// List returns the organization groups from the Admin API.
+func (o *groupBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, groupResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	groups, nextPageToken, annotation, err := o.client.ListGroups(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, group := range groups {
+		groupCopy := group
+		groupResource, err := parseIntoGroupResource(ctx, &groupCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, groupResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+func (o *groupBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	assigmentOptions := []entitlement.EntitlementOption{
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDescription(fmt.Sprintf("Member of %s group", resource.DisplayName)),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Group Member", resource.DisplayName)),
+	}
+
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(resource, groupMemberEntitlement, assigmentOptions...),
+	}, "", nil, nil
+}
+
+func (o *groupBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	entitlementID := entitlement.NewEntitlementID(resource, groupMemberEntitlement)
+	etag := previousETag(resource, pageToken, entitlementID)
+	if o.changes.unchanged(ctx, etag, resource.Id.Resource) {
+		return nil, "", etagMatch(entitlementID), nil
+	}
+
+	members, nextPageToken, annotation, err := o.client.ListGroupMembers(ctx, resource.Id.Resource, client.PageOptions{
+		PageSize:    pToken.Size,
+		PageToken:   pageToken,
+		IfNoneMatch: conditionalETag(etag),
+	})
+	if errors.Is(err, client.ErrNotModified) {
+		return nil, "", etagMatch(entitlementID), nil
+	}
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, member := range members {
+		principalID := &v2.ResourceId{
+			ResourceType: userResourceType.Id,
+			Resource:     userResourceID(member.AccountID),
+		}
+		grants = append(grants, grant.NewGrant(resource, groupMemberEntitlement, principalID))
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return grants, nextPageToken, o.changes.annotate(annotation, entitlementID, nextPageToken), nil
+}
+
+// Grant adds the principal to the group. Groups synced from an identity provider through SCIM are refused.
+func (o *groupBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if principal.Id.ResourceType != userResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: only users can be granted group membership")
+	}
+
+	groupID := entitlement.Resource.Id.Resource
+	accountID := accountIDFromUserResourceID(principal.Id.Resource)
+
+	annotation, err := o.client.AddGroupMember(ctx, groupID, accountID)
+	if err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			annotation.Append(&v2.GrantAlreadyExists{})
+			return annotation, nil
+		}
+		l.Error("failed to add group member", zap.String("group_id", groupID), zap.String("account_id", accountID), zap.Error(err))
+		return annotation, wrapProvisioningError(err)
+	}
+
+	return annotation, nil
+}
+
+// Revoke removes the principal from the group. Groups synced from an identity provider through SCIM are refused.
+func (o *groupBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	principal := grant.Principal
+	if principal.Id.ResourceType != userResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: only users can have group membership revoked")
+	}
+
+	groupID := grant.Entitlement.Resource.Id.Resource
+	accountID := accountIDFromUserResourceID(principal.Id.Resource)
+
+	annotation, err := o.client.RemoveGroupMember(ctx, groupID, accountID)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			annotation.Append(&v2.GrantAlreadyRevoked{})
+			return annotation, nil
+		}
+		l.Error("failed to remove group member", zap.String("group_id", groupID), zap.String("account_id", accountID), zap.Error(err))
+		return annotation, wrapProvisioningError(err)
+	}
+
+	return annotation, nil
+}
+
+// Create creates a group in the organization directory.
+func (o *groupBuilder) Create(ctx context.Context, groupResource *v2.Resource) (*v2.Resource, annotations.Annotations, error) {
+	if groupResource.DisplayName == "" {
+		return nil, nil, fmt.Errorf("baton-atlassian: group name is required")
+	}
+
+	input := client.GroupInput{
+		Name:        groupResource.DisplayName,
+		Description: groupResource.Description,
+	}
+
+	groupTrait, err := resource.GetGroupTrait(groupResource)
+	if err == nil {
+		if description, ok := resource.GetProfileStringValue(groupTrait.Profile, "description"); ok && description != "" {
+			input.Description = description
+		}
+	}
+
+	group, annotation, err := o.client.CreateGroup(ctx, input)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	ret, err := parseIntoGroupResource(ctx, group, groupResource.ParentResourceId)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return ret, annotation, nil
+}
+
+// Delete deletes the group. Groups synced from an identity provider through SCIM are refused.
+func (o *groupBuilder) Delete(ctx context.Context, resourceId *v2.ResourceId) (annotations.Annotations, error) {
+	if resourceId.ResourceType != groupResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: non-group resource passed to group delete: %s", resourceId.ResourceType)
+	}
+
+	annotation, err := o.client.DeleteGroup(ctx, resourceId.Resource)
+	if err != nil {
+		return annotation, wrapProvisioningError(err)
+	}
+
+	return annotation, nil
+}
+
+func newGroupBuilder(c *client.AtlassianClient, changes *changeLog) *groupBuilder {
+	return &groupBuilder{
+		resourceType: groupResourceType,
+		client:       c,
+		changes:      changes,
+	}
+}
+
+func parseIntoGroupResource(_ context.Context, group *client.Group, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"group_id":     group.ID,
+		"name":         group.Name,
+		"description":  group.Description,
+		"scim_managed": group.IsSCIMManaged(),
+	}
+
+	groupTraits := []resource.GroupTraitOption{
+		resource.WithGroupProfile(profile),
+	}
+
+	ret, err := resource.NewGroupResource(
+		group.Name,
+		groupResourceType,
+		group.ID,
+		groupTraits,
+		resource.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,143 @@
+package connector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Tests that membership changes on groups synced from an identity provider are refused before any mutation is sent.
+func TestGroupBuilder_GrantSCIMManagedGroup(t *testing.T) {
+	var capturedMethods []string
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		capturedMethods = append(capturedMethods, req.Method)
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`{"id": "group-1", "name": "okta-engineering", "resourceOwnerType": "EXT_SCIM"}`)),
+		}
+		resp.Header.Set("Content-Type", "application/json")
+		return resp, nil
+	})
+
+	baseHttpClient := uhttp.NewBaseHttpClient(&http.Client{Transport: mockTransport})
+	builder := newGroupBuilder(client.NewClient("", "", "admin-key", client.Endpoints{}, test.OrganizationID, "", baseHttpClient), nil)
+
+	groupResource, err := parseIntoGroupResource(context.Background(), &client.Group{ID: "group-1", Name: "okta-engineering"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	principal := &v2.Resource{Id: &v2.ResourceId{ResourceType: userResourceType.Id, Resource: userResourceID(test.UserIDs[0])}}
+	memberEntitlement := &v2.Entitlement{Id: "group:group-1:member", Resource: groupResource}
+
+	_, err = builder.Grant(context.Background(), principal, memberEntitlement)
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Expected failed precondition error, got %v", err)
+	}
+
+	if len(capturedMethods) != 1 || capturedMethods[0] != http.MethodGet {
+		t.Errorf("Expected only the group lookup request, got %v", capturedMethods)
+	}
+}
+
+// Tests that the grants of an unchanged group are skipped using the ETag stored by the previous sync.
+func TestGroupBuilder_GrantsETag(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`{"data": [{"accountId": "` + test.UserIDs[0] + `"}], "links": {}}`)),
+		}
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			resp.StatusCode = http.StatusNotModified
+			resp.Body = io.NopCloser(strings.NewReader(""))
+			return resp, nil
+		}
+		resp.Header.Set("Content-Type", "application/json")
+		resp.Header.Set("ETag", `"v1"`)
+		return resp, nil
+	})
+
+	baseHttpClient := uhttp.NewBaseHttpClient(&http.Client{Transport: mockTransport})
+	builder := newGroupBuilder(client.NewClient("", "", "admin-key", client.Endpoints{}, test.OrganizationID, "", baseHttpClient), nil)
+
+	groupResource, err := parseIntoGroupResource(context.Background(), &client.Group{ID: "group-1", Name: "engineering"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	entitlementID := entitlement.NewEntitlementID(groupResource, groupMemberEntitlement)
+
+	grants, _, annos, err := builder.Grants(context.Background(), groupResource, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(grants) != 1 {
+		t.Fatalf("Expected 1 grant, got %d", len(grants))
+	}
+	etag := &v2.ETag{}
+	if ok, err := annos.Pick(etag); err != nil || !ok {
+		t.Fatalf("Expected an ETag annotation, got %v", annos)
+	}
+	if etag.EntitlementId != entitlementID {
+		t.Errorf("Expected the ETag to be scoped to %s, got %s", entitlementID, etag.EntitlementId)
+	}
+
+	// The syncer stores the ETag on the resource and sends it back on the next sync.
+	groupResource.Annotations = annotations.New(etag)
+
+	grants, _, annos, err = builder.Grants(context.Background(), groupResource, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(grants) != 0 {
+		t.Errorf("Expected no grants, got %d", len(grants))
+	}
+	match := &v2.ETagMatch{}
+	if ok, err := annos.Pick(match); err != nil || !ok || match.EntitlementId != entitlementID {
+		t.Errorf("Expected an ETag match for %s, got %v", entitlementID, annos)
+	}
+}
+
+// Tests that groups are listed with the page size of the sync as the Admin API limit.
+func TestGroupBuilder_ListPageSize(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	var capturedQuery string
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		capturedQuery = req.URL.RawQuery
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`{"data": [], "links": {}}`)),
+		}
+		resp.Header.Set("Content-Type", "application/json")
+		return resp, nil
+	})
+
+	baseHttpClient := uhttp.NewBaseHttpClient(&http.Client{Transport: mockTransport})
+	builder := newGroupBuilder(client.NewClient("", "", "admin-key", client.Endpoints{}, test.OrganizationID, "", baseHttpClient), nil)
+
+	if _, _, _, err := builder.List(context.Background(), nil, &pagination.Token{Size: 25}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if capturedQuery != "limit=25" {
+		t.Errorf("Expected the page size as limit, got %q", capturedQuery)
+	}
+}
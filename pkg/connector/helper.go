@@ -1,10 +1,16 @@
 package connector
 
 import (
+	"errors"
 	"strconv"
+	"strings"
 
+	"github.com/conductorone/baton-atlassian/pkg/client"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
 	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func getToken(pToken *pagination.Token, resourceType *v2.ResourceType) (*pagination.Bag, string, error) {
@@ -27,6 +33,43 @@ func getToken(pToken *pagination.Token, resourceType *v2.ResourceType) (*paginat
 	return bag, pageToken, nil
 }
 
+// getOffsetToken returns the offset into a directory listing stored in the page token.
+func getOffsetToken(pToken *pagination.Token, resourceType *v2.ResourceType) (*pagination.Bag, int, error) {
+	bag, pageToken, err := getToken(pToken, resourceType)
+	if err != nil || pageToken == "" {
+		return bag, 0, err
+	}
+
+	offset, err := strconv.Atoi(pageToken)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return bag, offset, nil
+}
+
+// marshalOffsetToken stores the offset of the next page of a directory listing, 0 meaning there is none.
+func marshalOffsetToken(bag *pagination.Bag, next int) (string, error) {
+	nextPageToken := ""
+	if next > 0 {
+		nextPageToken = strconv.Itoa(next)
+	}
+
+	if err := bag.Next(nextPageToken); err != nil {
+		return "", err
+	}
+
+	return bag.Marshal()
+}
+
+// getPageSize returns the requested page size, or the client's default when none was requested.
+func getPageSize(pToken *pagination.Token) int {
+	if pToken == nil || pToken.Size <= 0 || pToken.Size > client.ItemsPerPage {
+		return client.ItemsPerPage
+	}
+	return pToken.Size
+}
+
 func unmarshalSkipToken(token *pagination.Token) (int32, *pagination.Bag, error) {
 	b := &pagination.Bag{}
 	err := b.Unmarshal(token.Token)
@@ -44,3 +87,81 @@ func unmarshalSkipToken(token *pagination.Token) (int32, *pagination.Bag, error)
 	}
 	return skip, b, nil
 }
+
+const userARIPrefix = "ari:cloud:identity::user/"
+
+// userResourceID returns the user resource ID, an Atlassian identity ARI, for an account ID.
+func userResourceID(accountID string) string {
+	return userARIPrefix + accountID
+}
+
+// accountIDFromUserResourceID returns the account ID embedded in a user resource ID.
+func accountIDFromUserResourceID(resourceID string) string {
+	return strings.TrimPrefix(resourceID, userARIPrefix)
+}
+
+// wrapProvisioningError surfaces refusals that retrying cannot fix, such as SCIM-managed groups and exhausted
+// product licenses, with distinct status codes so callers can tell them apart from transient API failures.
+func wrapProvisioningError(err error) error {
+	switch {
+	case errors.Is(err, client.ErrGroupManagedBySCIM):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, client.ErrLicenseLimitReached):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return err
+}
+
+// previousETag returns the ETag stored on the resource by the previous sync for the grants of the given
+// entitlement. Only the first page of grants can be skipped, so later pages never get one.
+func previousETag(resource *v2.Resource, pageToken string, entitlementID string) string {
+	if pageToken != "" {
+		return ""
+	}
+
+	etag := &v2.ETag{}
+	annos := annotations.Annotations(resource.GetAnnotations())
+	ok, err := annos.Pick(etag)
+	if err != nil || !ok || etag.GetEntitlementId() != entitlementID {
+		return ""
+	}
+
+	return etag.GetValue()
+}
+
+// etagMatch tells the syncer to reuse the grants of the previous sync for the entitlement.
+func etagMatch(entitlementID string) annotations.Annotations {
+	return annotations.New(&v2.ETagMatch{EntitlementId: entitlementID})
+}
+
+// scopeETag ties the ETag returned with a page of grants to the entitlement. The ETag is dropped when the
+// grants span several pages, as a match on the first page says nothing about the others.
+func scopeETag(annos annotations.Annotations, entitlementID string, nextPageToken string) annotations.Annotations {
+	etag := &v2.ETag{}
+	ok, err := annos.Pick(etag)
+	if err != nil || !ok {
+		return annos
+	}
+
+	var ret annotations.Annotations
+	for _, a := range annos {
+		if !a.MessageIs(etag) {
+			ret = append(ret, a)
+		}
+	}
+
+	if nextPageToken == "" {
+		ret.Update(&v2.ETag{Value: etag.GetValue(), EntitlementId: entitlementID})
+	}
+
+	return ret
+}
+
+// updateRateLimit keeps the rate limit reported by annos in annotation, for builders that make several
+// requests per page.
+func updateRateLimit(annotation *annotations.Annotations, annos annotations.Annotations) {
+	rateLimit := &v2.RateLimitDescription{}
+	if ok, err := annos.Pick(rateLimit); err == nil && ok {
+		annotation.Update(rateLimit)
+	}
+}
@@ -0,0 +1,134 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// jiraDCApplicationRoleBuilder reports the application roles of a Jira instance, such as Jira Software, as the
+// user product role of the application. Application access comes from groups, so the roles are granted to
+// groups and expanded to their members.
+type jiraDCApplicationRoleBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+}
+
+func (o *jiraDCApplicationRoleBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return productRoleResourceType
+}
+
+// List returns a product role resource for every application role in a single page.
+func (o *jiraDCApplicationRoleBuilder) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	roles, annotation, err := o.client.ListJiraApplicationRoles(ctx)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, role := range roles {
+		roleCopy := role
+		roleResource, err := parseIntoJiraDCApplicationRoleResource(ctx, &roleCopy, o.client.BaseURL(), nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, roleResource)
+	}
+
+	return resources, "", annotation, nil
+}
+
+func (o *jiraDCApplicationRoleBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	assigmentOptions := []entitlement.EntitlementOption{
+		entitlement.WithGrantableTo(userResourceType, groupResourceType),
+		entitlement.WithDescription(fmt.Sprintf("Assigned the %s product role", resource.DisplayName)),
+		entitlement.WithDisplayName(resource.DisplayName),
+	}
+
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(resource, productRoleAssignedEntitlement, assigmentOptions...),
+	}, "", nil, nil
+}
+
+// Grants returns a grant to every group of the application role. The roles are read again, as the instance
+// has no listing of the groups of a single role.
+func (o *jiraDCApplicationRoleBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+
+	_, key, err := parseProductRoleResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	roles, annotation, err := o.client.ListJiraApplicationRoles(ctx)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, role := range roles {
+		if role.Key != key {
+			continue
+		}
+		for _, groupName := range role.Groups {
+			grants = append(grants, dataCenterGroupGrant(resource, productRoleAssignedEntitlement, groupName))
+		}
+	}
+
+	return grants, "", annotation, nil
+}
+
+func newJiraDCApplicationRoleBuilder(c *client.DataCenterClient) *jiraDCApplicationRoleBuilder {
+	return &jiraDCApplicationRoleBuilder{
+		resourceType: productRoleResourceType,
+		client:       c,
+	}
+}
+
+// parseIntoJiraDCApplicationRoleResource returns the user product role of the application, with the
+// application key in place of the cloud workspace ID, e.g. "user:jira-software".
+func parseIntoJiraDCApplicationRoleResource(_ context.Context, role *client.JiraApplicationRole, baseURL string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	site := baseURL
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		site = u.Host
+	}
+
+	capacity := role.NumberOfSeats
+	if role.HasUnlimitedSeats {
+		capacity = 0
+	}
+
+	profile := map[string]interface{}{
+		"workspace_id": role.Key,
+		"product":      role.Key,
+		"site":         site,
+		"role_id":      client.ProductRoleUser,
+		"usage":        role.UserCount,
+		"capacity":     capacity,
+	}
+
+	roleTraits := []resource.RoleTraitOption{
+		resource.WithRoleProfile(profile),
+	}
+
+	userRole := productRoles[0]
+	ret, err := resource.NewRoleResource(
+		fmt.Sprintf("%s %s on %s", role.Name, userRole.displayName, site),
+		productRoleResourceType,
+		productRoleResourceID(userRole, role.Key),
+		roleTraits,
+		resource.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
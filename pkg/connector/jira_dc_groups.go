@@ -0,0 +1,142 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type jiraDCGroupBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+}
+
+func (o *jiraDCGroupBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return groupResourceType
+}
+
+// List returns every group of the Jira instance in a single page.
+func (o *jiraDCGroupBuilder) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	groups, annotation, err := o.client.ListJiraGroups(ctx)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, group := range groups {
+		groupResource, err := parseIntoDataCenterGroupResource(ctx, group.Name, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, groupResource)
+	}
+
+	return resources, "", annotation, nil
+}
+
+func (o *jiraDCGroupBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return []*v2.Entitlement{dataCenterGroupMemberEntitlement(resource)}, "", nil, nil
+}
+
+func (o *jiraDCGroupBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	members, nextPageToken, annotation, err := o.client.ListJiraGroupMembers(ctx, resource.Id.Resource, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, member := range members {
+		principalID := &v2.ResourceId{
+			ResourceType: userResourceType.Id,
+			Resource:     member.Key,
+		}
+		grants = append(grants, grant.NewGrant(resource, groupMemberEntitlement, principalID))
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return grants, nextPageToken, annotation, nil
+}
+
+func newJiraDCGroupBuilder(c *client.DataCenterClient) *jiraDCGroupBuilder {
+	return &jiraDCGroupBuilder{
+		resourceType: groupResourceType,
+		client:       c,
+	}
+}
+
+// dataCenterGroupMemberEntitlement returns the member entitlement of a Data Center group, the same as the
+// one of an organization group.
+func dataCenterGroupMemberEntitlement(resource *v2.Resource) *v2.Entitlement {
+	return entitlement.NewAssignmentEntitlement(resource, groupMemberEntitlement,
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDescription(fmt.Sprintf("Member of %s group", resource.DisplayName)),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Group Member", resource.DisplayName)),
+	)
+}
+
+// parseIntoDataCenterGroupResource returns the group with its name as ID, as Data Center groups have no other
+// identifier.
+func parseIntoDataCenterGroupResource(_ context.Context, name string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"group_id": name,
+		"name":     name,
+	}
+
+	groupTraits := []resource.GroupTraitOption{
+		resource.WithGroupProfile(profile),
+	}
+
+	ret, err := resource.NewGroupResource(
+		name,
+		groupResourceType,
+		name,
+		groupTraits,
+		resource.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// dataCenterGroupGrant grants the entitlement to every member of the group.
+func dataCenterGroupGrant(resource *v2.Resource, entitlementName, groupName string) *v2.Grant {
+	groupID := &v2.ResourceId{
+		ResourceType: groupResourceType.Id,
+		Resource:     groupName,
+	}
+	groupResource := &v2.Resource{Id: groupID}
+
+	return grant.NewGrant(resource, entitlementName, groupID,
+		grant.WithAnnotation(&v2.GrantExpandable{
+			EntitlementIds: []string{entitlement.NewEntitlementID(groupResource, groupMemberEntitlement)},
+		}),
+	)
+}
@@ -0,0 +1,240 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const projectRoleAssignedEntitlement = "assigned"
+
+// jiraDCProjectRoleBuilder reports every project role of every Jira project, held by users and groups.
+type jiraDCProjectRoleBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+	userKeys     *jiraDCUserKeys
+}
+
+func (o *jiraDCProjectRoleBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return projectRoleResourceType
+}
+
+// List returns a project role resource for every role of a page of the projects. Jira lists every project
+// in one response, so pages are offsets into that listing.
+func (o *jiraDCProjectRoleBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, offset, err := getOffsetToken(pToken, projectRoleResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	roles, annotation, err := o.client.ListJiraProjectRoles(ctx)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	projects, annotation, err := o.client.ListJiraProjects(ctx)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	end := min(offset+getPageSize(pToken), len(projects))
+	for _, project := range projects[min(offset, end):end] {
+		projectCopy := project
+		for _, role := range roles {
+			roleCopy := role
+			roleResource, err := parseIntoJiraDCProjectRoleResource(ctx, &projectCopy, &roleCopy, nil)
+			if err != nil {
+				return nil, "", annotation, err
+			}
+			resources = append(resources, roleResource)
+		}
+	}
+
+	next := 0
+	if end < len(projects) {
+		next = end
+	}
+	nextPageToken, err := marshalOffsetToken(bag, next)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+func (o *jiraDCProjectRoleBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	assigmentOptions := []entitlement.EntitlementOption{
+		entitlement.WithGrantableTo(userResourceType, groupResourceType),
+		entitlement.WithDescription(fmt.Sprintf("Assigned the %s project role", resource.DisplayName)),
+		entitlement.WithDisplayName(resource.DisplayName),
+	}
+
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(resource, projectRoleAssignedEntitlement, assigmentOptions...),
+	}, "", nil, nil
+}
+
+// Grants returns a grant to every user and group holding the role in the project. Role actors only carry the
+// username, so users are looked up for their key, once per sync.
+func (o *jiraDCProjectRoleBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+	var grants []*v2.Grant
+
+	roleID, projectID, err := parseProjectRoleResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	role, annotation, err := o.client.GetJiraProjectRole(ctx, projectID, roleID)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, actor := range role.Actors {
+		switch actor.Type {
+		case client.JiraRoleActorGroup:
+			grants = append(grants, dataCenterGroupGrant(resource, projectRoleAssignedEntitlement, actor.Name))
+		case client.JiraRoleActorUser:
+			key, ok, annos, err := o.userKeys.Key(ctx, actor.Name)
+			updateRateLimit(&annotation, annos)
+			if err != nil {
+				return nil, "", annotation, err
+			}
+			if !ok {
+				l.Warn("skipping project role actor of a deleted user", zap.String("username", actor.Name))
+				continue
+			}
+			principalID := &v2.ResourceId{
+				ResourceType: userResourceType.Id,
+				Resource:     key,
+			}
+			grants = append(grants, grant.NewGrant(resource, projectRoleAssignedEntitlement, principalID))
+		}
+	}
+
+	return grants, "", annotation, nil
+}
+
+func newJiraDCProjectRoleBuilder(c *client.DataCenterClient, userKeys *jiraDCUserKeys) *jiraDCProjectRoleBuilder {
+	return &jiraDCProjectRoleBuilder{
+		resourceType: projectRoleResourceType,
+		client:       c,
+		userKeys:     userKeys,
+	}
+}
+
+// jiraDCUserKeys resolves usernames to the keys Jira users are identified by, for the project role actors
+// that only carry the username. Every username is looked up once and kept for the rest of the sync; Reset
+// clears it before the next one.
+type jiraDCUserKeys struct {
+	client *client.DataCenterClient
+
+	mtx  sync.Mutex
+	keys map[string]string
+}
+
+func newJiraDCUserKeys(c *client.DataCenterClient) *jiraDCUserKeys {
+	return &jiraDCUserKeys{
+		client: c,
+	}
+}
+
+// Reset drops the resolved keys.
+func (k *jiraDCUserKeys) Reset() {
+	if k == nil {
+		return
+	}
+
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+
+	k.keys = nil
+}
+
+// Key returns the key of the user with the username, and false when the user no longer exists. The
+// annotations are those of the lookup, or empty when the key was already resolved.
+func (k *jiraDCUserKeys) Key(ctx context.Context, username string) (string, bool, annotations.Annotations, error) {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+
+	if key, ok := k.keys[username]; ok {
+		return key, key != "", nil, nil
+	}
+
+	user, annotation, err := k.client.GetJiraUser(ctx, username)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return "", false, annotation, err
+	}
+
+	key := ""
+	if err == nil {
+		key = user.Key
+	}
+	if k.keys == nil {
+		k.keys = make(map[string]string)
+	}
+	k.keys[username] = key
+
+	return key, key != "", annotation, nil
+}
+
+func parseIntoJiraDCProjectRoleResource(_ context.Context, project *client.JiraProject, role *client.JiraProjectRole, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"project_id":   project.ID,
+		"project_key":  project.Key,
+		"project_name": project.Name,
+		"role_id":      role.ID,
+		"role_name":    role.Name,
+	}
+
+	roleTraits := []resource.RoleTraitOption{
+		resource.WithRoleProfile(profile),
+	}
+
+	ret, err := resource.NewRoleResource(
+		fmt.Sprintf("%s %s", project.Key, role.Name),
+		projectRoleResourceType,
+		projectRoleResourceID(role.ID, project.ID),
+		roleTraits,
+		resource.WithParentResourceID(parentResourceID),
+		resource.WithDescription(role.Description),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// projectRoleResourceID combines the role ID and the project ID, like product role IDs, e.g. "10002:10000".
+func projectRoleResourceID(roleID int64, projectID string) string {
+	return fmt.Sprintf("%d:%s", roleID, projectID)
+}
+
+func parseProjectRoleResourceID(resourceID string) (int64, string, error) {
+	roleID, projectID, ok := strings.Cut(resourceID, ":")
+	if ok && projectID != "" {
+		id, err := strconv.ParseInt(roleID, 10, 64)
+		if err == nil {
+			return id, projectID, nil
+		}
+	}
+
+	return 0, "", fmt.Errorf("baton-atlassian: invalid project role ID %q", resourceID)
+}
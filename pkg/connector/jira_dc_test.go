@@ -0,0 +1,178 @@
+package connector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newJiraDCConnector returns a Jira Data Center connector sending its requests through the round tripper.
+func newJiraDCConnector(transport *test.FixtureRoundTripper) *Connector {
+	return newDataCenterConnector(DataCenterProductJira, test.NewFixtureDataCenterClient(transport, client.NewBearerAuth("pat")))
+}
+
+// Tests that NewDataCenter syncs a Jira instance at the base URL with the cloud resource types, authenticated
+// with the personal access token.
+func TestNewDataCenter_Jira(t *testing.T) {
+	server, transport := test.NewFixtureServer(t, test.JiraDCFixtures)
+	ctx := context.Background()
+
+	c, err := NewDataCenter(ctx, DataCenterProductJira, server.URL, client.NewBearerAuth("pat"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	syncers := c.ResourceSyncers(ctx)
+	resourceTypes := make([]string, 0, len(syncers))
+	for _, syncer := range syncers {
+		resourceTypes = append(resourceTypes, syncer.ResourceType(ctx).Id)
+	}
+	expected := []string{userResourceType.Id, groupResourceType.Id, productRoleResourceType.Id, projectRoleResourceType.Id}
+	if len(resourceTypes) != len(expected) {
+		t.Fatalf("Expected resource types %v, got %v", expected, resourceTypes)
+	}
+	for i := range expected {
+		if resourceTypes[i] != expected[i] {
+			t.Fatalf("Expected resource types %v, got %v", expected, resourceTypes)
+		}
+	}
+
+	users, _ := listAll(t, syncers[0])
+	if len(users) != 3 {
+		t.Fatalf("Expected 3 users, got %d", len(users))
+	}
+	userTrait, err := resource.GetUserTrait(users[2])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if users[2].Id.Resource != "bob" || userTrait.Status.Status != v2.UserTrait_Status_STATUS_DISABLED {
+		t.Errorf("Expected bob to be disabled, got %s with status %s", users[2].Id.Resource, userTrait.Status.Status)
+	}
+	if directoryID, ok := resource.GetProfileInt64Value(userTrait.Profile, "directory_id"); !ok || directoryID != 1 {
+		t.Errorf("Expected directory 1, got %d", directoryID)
+	}
+
+	if got := transport.Requests("GET /rest/api/2/user/search")[0].Header.Get("Authorization"); got != "Bearer pat" {
+		t.Errorf("Expected requests to be authenticated with the personal access token, got %q", got)
+	}
+}
+
+// Tests that users are listed page after page until Jira returns a short page.
+func TestJiraDCUserBuilder_ListsEveryPage(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(map[string]string{
+		"GET /rest/api/2/user/search?startAt=0&maxResults=2": "JiraDCUsersFirstPage.json",
+		"GET /rest/api/2/user/search?startAt=2&maxResults=2": "JiraDCUsersLastPage.json",
+	})
+	builder := newJiraDCUserBuilder(test.NewFixtureDataCenterClient(transport, client.NewBearerAuth("pat")))
+
+	var keys []string
+	pToken := &pagination.Token{Size: 2}
+	for pages := 1; ; pages++ {
+		users, next, _, err := builder.List(context.Background(), nil, pToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, user := range users {
+			keys = append(keys, user.Id.Resource)
+		}
+		if next == "" {
+			if pages != 2 {
+				t.Errorf("Expected 2 pages, got %d", pages)
+			}
+			break
+		}
+		pToken = &pagination.Token{Size: 2, Token: next}
+	}
+
+	if len(keys) != 3 || keys[0] != "JIRAUSER10000" || keys[2] != "bob" {
+		t.Errorf("Expected the keys of the 3 users in order, got %v", keys)
+	}
+}
+
+// Tests that application roles are granted to their groups with expandable grants, and project roles to
+// users by key and to groups.
+func TestJiraDCSyncers_Grants(t *testing.T) {
+	syncers := newJiraDCConnector(test.NewFixtureRoundTripper(test.JiraDCFixtures)).ResourceSyncers(context.Background())
+
+	roles, roleGrants := listAll(t, syncers[2])
+	if len(roles) != 1 || roles[0].Id.Resource != "user:jira-software" {
+		t.Fatalf("Expected the user role of jira-software, got %v", roles)
+	}
+	if len(roleGrants) != 2 {
+		t.Fatalf("Expected the role to be granted to 2 groups, got %d", len(roleGrants))
+	}
+	for _, roleGrant := range roleGrants {
+		annos := annotations.Annotations(roleGrant.Annotations)
+		if roleGrant.Principal.Id.ResourceType != groupResourceType.Id || !annos.Contains(&v2.GrantExpandable{}) {
+			t.Errorf("Expected an expandable grant to a group, got %v", roleGrant)
+		}
+	}
+
+	projectRoles, projectRoleGrants := listAll(t, syncers[3])
+	if len(projectRoles) != 1 || projectRoles[0].Id.Resource != "10002:10000" {
+		t.Fatalf("Expected the Administrators role of PROJ, got %v", projectRoles)
+	}
+	if len(projectRoleGrants) != 2 || projectRoleGrants[0].Principal.Id.Resource != "JIRAUSER10100" {
+		t.Errorf("Expected grants to alice's key and a group, got %v", projectRoleGrants)
+	}
+}
+
+// Tests that project roles are listed one page of projects at a time, that every role actor is looked up
+// once per sync for its key, and that actors of deleted users are skipped.
+func TestJiraDCProjectRoleBuilder_PagesAndResolvesUsersOnce(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(map[string]string{
+		"GET /rest/api/2/role":                     "JiraDCRoles.json",
+		"GET /rest/api/2/project":                  "JiraDCProjectsTwo.json",
+		"GET /rest/api/2/project/10000/role/10002": "JiraDCProjectRole.json",
+		"GET /rest/api/2/project/10001/role/10002": "JiraDCProjectRoleDeletedActor.json",
+		"GET /rest/api/2/user?username=alice":      "JiraDCUser.json",
+		"GET /rest/api/2/user?username=ghost":      "404",
+	})
+	c := newJiraDCConnector(transport)
+	builder := c.ResourceSyncers(context.Background())[3]
+
+	roles, grants := listAll(t, builder)
+	if len(roles) != 2 || roles[0].Id.Resource != "10002:10000" || roles[1].Id.Resource != "10002:10001" {
+		t.Fatalf("Expected the Administrators role of both projects, got %v", roles)
+	}
+	if requests := len(transport.Requests("GET /rest/api/2/project")); requests != 2 {
+		t.Errorf("Expected a page per project, got %d project listings", requests)
+	}
+
+	principals := make(map[string]int)
+	for _, g := range grants {
+		principals[g.Principal.Id.Resource]++
+	}
+	if principals["JIRAUSER10100"] != 2 || principals["jira-administrators"] != 1 || len(principals) != 2 {
+		t.Errorf("Expected alice in both projects and the group in one, got %v", principals)
+	}
+	if requests := len(transport.Requests("GET /rest/api/2/user?username=alice")); requests != 1 {
+		t.Errorf("Expected alice to be looked up once, got %d requests", requests)
+	}
+
+	for _, role := range roles {
+		if _, _, _, err := builder.Grants(context.Background(), role, &pagination.Token{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if requests := len(transport.Requests("GET /rest/api/2/user?username=ghost")); requests != 1 {
+		t.Errorf("Expected the deleted user to be looked up once per sync, got %d requests", requests)
+	}
+
+	if _, err := c.Validate(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	transport.SetRoute("GET /rest/api/2/user?username=alice", "403")
+	_, _, _, err := builder.Grants(context.Background(), roles[0], &pagination.Token{})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected a failed lookup after a new sync starts to fail with PermissionDenied, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type jiraDCUserBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.DataCenterClient
+}
+
+func (o *jiraDCUserBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return userResourceType
+}
+
+// List returns the active and inactive users of the Jira instance.
+func (o *jiraDCUserBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	users, nextPageToken, annotation, err := o.client.ListJiraUsers(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, user := range users {
+		userCopy := user
+		userResource, err := parseIntoJiraDCUserResource(ctx, &userCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, userResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+// Entitlements always returns an empty slice for users.
+func (o *jiraDCUserBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for users since they don't have any entitlements.
+func (o *jiraDCUserBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func newJiraDCUserBuilder(c *client.DataCenterClient) *jiraDCUserBuilder {
+	return &jiraDCUserBuilder{
+		resourceType: userResourceType,
+		client:       c,
+	}
+}
+
+// parseIntoJiraDCUserResource returns the user with its key as ID, as the key outlives username changes.
+func parseIntoJiraDCUserResource(_ context.Context, user *client.JiraUser, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	userStatus := v2.UserTrait_Status_STATUS_ENABLED
+	if !user.Active {
+		userStatus = v2.UserTrait_Status_STATUS_DISABLED
+	}
+
+	profile := map[string]interface{}{
+		"user_id":  user.Key,
+		"username": user.Name,
+		"email":    user.EmailAddress,
+		"active":   user.Active,
+	}
+	if user.DirectoryID != 0 {
+		profile["directory_id"] = user.DirectoryID
+	}
+
+	userTraits := []resource.UserTraitOption{
+		resource.WithUserProfile(profile),
+		resource.WithStatus(userStatus),
+		resource.WithUserLogin(user.Name),
+	}
+	if user.EmailAddress != "" {
+		userTraits = append(userTraits, resource.WithEmail(user.EmailAddress, true))
+	}
+
+	displayName := user.DisplayName
+	if displayName == "" {
+		displayName = user.Name
+	}
+
+	ret, err := resource.NewUserResource(
+		displayName,
+		userResourceType,
+		user.Key,
+		userTraits,
+		resource.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,36 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// NewOpsgenie returns a connector that syncs an Opsgenie account through the Opsgenie REST API at baseURL, or
+// client.DefaultOpsgenieURL if it is empty, instead of Atlassian cloud.
+func NewOpsgenie(ctx context.Context, baseURL, apiKey string) (*Connector, error) {
+	l := ctxzap.Extract(ctx)
+
+	opsgenieClient, err := client.NewOpsgenieClient(ctx, baseURL, apiKey)
+	if err != nil {
+		l.Error("error creating Opsgenie client", zap.Error(err))
+		return nil, err
+	}
+
+	return &Connector{
+		opsgenie: opsgenieClient,
+	}, nil
+}
+
+// opsgenieSyncers returns the syncers of an Opsgenie account.
+func (d *Connector) opsgenieSyncers(_ context.Context) []connectorbuilder.ResourceSyncer {
+	return []connectorbuilder.ResourceSyncer{
+		newOpsgenieUserBuilder(d.opsgenie),
+		newOpsgenieAccountRoleBuilder(d.opsgenie),
+		newOpsgenieTeamBuilder(d.opsgenie),
+		newOpsgenieScheduleBuilder(d.opsgenie),
+	}
+}
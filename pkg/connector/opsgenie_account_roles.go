@@ -0,0 +1,142 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const accountRoleAssignedEntitlement = "assigned"
+
+// opsgenieAccountRoleBuilder reports the built-in and custom account roles of an Opsgenie account. Every user
+// holds exactly one.
+type opsgenieAccountRoleBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.OpsgenieClient
+}
+
+func (o *opsgenieAccountRoleBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return accountRoleResourceType
+}
+
+// List returns the built-in roles followed by the custom roles in a single page.
+func (o *opsgenieAccountRoleBuilder) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	customRoles, annotation, err := o.client.ListCustomRoles(ctx)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, name := range client.OpsgenieRoles {
+		roleResource, err := parseIntoOpsgenieAccountRoleResource(ctx, name, false, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, roleResource)
+	}
+
+	for _, role := range customRoles {
+		roleResource, err := parseIntoOpsgenieAccountRoleResource(ctx, role.Name, true, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, roleResource)
+	}
+
+	return resources, "", annotation, nil
+}
+
+func (o *opsgenieAccountRoleBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	assigmentOptions := []entitlement.EntitlementOption{
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDescription(fmt.Sprintf("Assigned the %s account role", resource.DisplayName)),
+		entitlement.WithDisplayName(resource.DisplayName),
+	}
+
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(resource, accountRoleAssignedEntitlement, assigmentOptions...),
+	}, "", nil, nil
+}
+
+// Grants pages through the users and returns a grant to those holding the role. Users carry their role, and
+// there is no listing of the users of a single role.
+func (o *opsgenieAccountRoleBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	users, nextPageToken, annotation, err := o.client.ListUsers(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, user := range users {
+		if user.Role.Name != resource.Id.Resource {
+			continue
+		}
+		principalID := &v2.ResourceId{
+			ResourceType: userResourceType.Id,
+			Resource:     user.ID,
+		}
+		grants = append(grants, grant.NewGrant(resource, accountRoleAssignedEntitlement, principalID))
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return grants, nextPageToken, annotation, nil
+}
+
+func newOpsgenieAccountRoleBuilder(c *client.OpsgenieClient) *opsgenieAccountRoleBuilder {
+	return &opsgenieAccountRoleBuilder{
+		resourceType: accountRoleResourceType,
+		client:       c,
+	}
+}
+
+// parseIntoOpsgenieAccountRoleResource returns the role with its name as ID, as users only reference their
+// role by name reliably.
+func parseIntoOpsgenieAccountRoleResource(_ context.Context, name string, custom bool, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"role_name": name,
+		"custom":    custom,
+	}
+
+	roleTraits := []resource.RoleTraitOption{
+		resource.WithRoleProfile(profile),
+	}
+
+	ret, err := resource.NewRoleResource(
+		name,
+		accountRoleResourceType,
+		name,
+		roleTraits,
+		resource.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
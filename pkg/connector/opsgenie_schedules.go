@@ -0,0 +1,127 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const scheduleParticipantEntitlement = "participant"
+
+// opsgenieScheduleBuilder reports the on-call schedules of an Opsgenie account, whose rotations have users and
+// teams as participants.
+type opsgenieScheduleBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.OpsgenieClient
+}
+
+func (o *opsgenieScheduleBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return scheduleResourceType
+}
+
+// List returns every schedule of the Opsgenie account in a single page.
+func (o *opsgenieScheduleBuilder) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	schedules, annotation, err := o.client.ListSchedules(ctx)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, schedule := range schedules {
+		scheduleCopy := schedule
+		scheduleResource, err := parseIntoOpsgenieScheduleResource(ctx, &scheduleCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, scheduleResource)
+	}
+
+	return resources, "", annotation, nil
+}
+
+func (o *opsgenieScheduleBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	assigmentOptions := []entitlement.EntitlementOption{
+		entitlement.WithGrantableTo(userResourceType, teamResourceType),
+		entitlement.WithDescription(fmt.Sprintf("Participant in a rotation of the %s schedule", resource.DisplayName)),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Participant", resource.DisplayName)),
+	}
+
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(resource, scheduleParticipantEntitlement, assigmentOptions...),
+	}, "", nil, nil
+}
+
+// Grants returns a grant to every user and team taking part in a rotation of the schedule, once however many
+// rotations they are in. Teams are expanded to their members.
+func (o *opsgenieScheduleBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+
+	schedule, annotation, err := o.client.GetSchedule(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	seen := make(map[string]bool)
+	for _, rotation := range schedule.Rotations {
+		for _, participant := range rotation.Participants {
+			if seen[participant.Type+":"+participant.ID] {
+				continue
+			}
+			seen[participant.Type+":"+participant.ID] = true
+
+			switch participant.Type {
+			case client.OpsgenieParticipantUser:
+				principalID := &v2.ResourceId{
+					ResourceType: userResourceType.Id,
+					Resource:     participant.ID,
+				}
+				grants = append(grants, grant.NewGrant(resource, scheduleParticipantEntitlement, principalID))
+			case client.OpsgenieParticipantTeam:
+				teamID := &v2.ResourceId{
+					ResourceType: teamResourceType.Id,
+					Resource:     participant.ID,
+				}
+				teamResource := &v2.Resource{Id: teamID}
+				entitlementIDs := make([]string, 0, len(opsgenieTeamRoles))
+				for _, role := range opsgenieTeamRoles {
+					entitlementIDs = append(entitlementIDs, entitlement.NewEntitlementID(teamResource, role))
+				}
+				grants = append(grants, grant.NewGrant(resource, scheduleParticipantEntitlement, teamID,
+					grant.WithAnnotation(&v2.GrantExpandable{EntitlementIds: entitlementIDs}),
+				))
+			}
+		}
+	}
+
+	return grants, "", annotation, nil
+}
+
+func newOpsgenieScheduleBuilder(c *client.OpsgenieClient) *opsgenieScheduleBuilder {
+	return &opsgenieScheduleBuilder{
+		resourceType: scheduleResourceType,
+		client:       c,
+	}
+}
+
+func parseIntoOpsgenieScheduleResource(_ context.Context, schedule *client.OpsgenieSchedule, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	ret, err := resource.NewResource(
+		schedule.Name,
+		scheduleResourceType,
+		schedule.ID,
+		resource.WithParentResourceID(parentResourceID),
+		resource.WithDescription(schedule.Description),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,205 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// opsgenieTeamRoles are the roles of Opsgenie team members, each reported as an entitlement of the team.
+var opsgenieTeamRoles = []string{client.OpsgenieTeamRoleAdmin, client.OpsgenieTeamRoleUser}
+
+type opsgenieTeamBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.OpsgenieClient
+}
+
+func (o *opsgenieTeamBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return teamResourceType
+}
+
+// List returns every team of the Opsgenie account in a single page.
+func (o *opsgenieTeamBuilder) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	teams, annotation, err := o.client.ListTeams(ctx)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, team := range teams {
+		teamCopy := team
+		teamResource, err := parseIntoOpsgenieTeamResource(ctx, &teamCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, teamResource)
+	}
+
+	return resources, "", annotation, nil
+}
+
+// Entitlements returns an entitlement for every team member role.
+func (o *opsgenieTeamBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	entitlements := make([]*v2.Entitlement, 0, len(opsgenieTeamRoles))
+
+	for _, role := range opsgenieTeamRoles {
+		entitlements = append(entitlements, entitlement.NewPermissionEntitlement(resource, role,
+			entitlement.WithGrantableTo(userResourceType),
+			entitlement.WithDescription(fmt.Sprintf("Team member with the %s role on the %s team", role, resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("%s Team %s", resource.DisplayName, role)),
+		))
+	}
+
+	return entitlements, "", nil, nil
+}
+
+// Grants returns a grant of its role to every member of the team.
+func (o *opsgenieTeamBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+
+	team, annotation, err := o.client.GetTeam(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, member := range team.Members {
+		principalID := &v2.ResourceId{
+			ResourceType: userResourceType.Id,
+			Resource:     member.User.ID,
+		}
+		grants = append(grants, grant.NewGrant(resource, member.Role, principalID))
+	}
+
+	return grants, "", annotation, nil
+}
+
+// Grant adds the user to the team with the role of the entitlement. Members have a single role per team, so a
+// member holding the user role is added again with the admin role, while granting the user role to an admin
+// is refused rather than downgrading the member.
+func (o *opsgenieTeamBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if principal.Id.ResourceType != userResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: only users can be granted team membership")
+	}
+
+	teamID := entitlement.Resource.Id.Resource
+	userID := principal.Id.Resource
+
+	team, annotation, err := o.client.GetTeam(ctx, teamID)
+	if err != nil {
+		return annotation, err
+	}
+
+	held := opsgenieTeamMemberRole(team, userID)
+	if held == entitlement.Slug {
+		annotation.Append(&v2.GrantAlreadyExists{})
+		return annotation, nil
+	}
+	if held == client.OpsgenieTeamRoleAdmin {
+		return annotation, fmt.Errorf("baton-atlassian: %s is an admin of team %s, revoke it before granting %s", userID, teamID, entitlement.Slug)
+	}
+
+	annotation, err = o.client.AddTeamMember(ctx, teamID, userID, entitlement.Slug)
+	if err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			annotation.Append(&v2.GrantAlreadyExists{})
+			return annotation, nil
+		}
+		l.Error("failed to add team member", zap.String("team_id", teamID), zap.String("user_id", userID), zap.Error(err))
+		return annotation, err
+	}
+
+	return annotation, nil
+}
+
+// Revoke removes the user from the team. Members have a single role per team, so they are only removed while
+// it is the role of the grant.
+func (o *opsgenieTeamBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	principal := grant.Principal
+	if principal.Id.ResourceType != userResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: only users can have team membership revoked")
+	}
+
+	teamID := grant.Entitlement.Resource.Id.Resource
+	userID := principal.Id.Resource
+
+	team, annotation, err := o.client.GetTeam(ctx, teamID)
+	if err != nil {
+		return annotation, err
+	}
+	if opsgenieTeamMemberRole(team, userID) != grant.Entitlement.Slug {
+		annotation.Append(&v2.GrantAlreadyRevoked{})
+		return annotation, nil
+	}
+
+	annotation, err = o.client.RemoveTeamMember(ctx, teamID, userID)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			annotation.Append(&v2.GrantAlreadyRevoked{})
+			return annotation, nil
+		}
+		l.Error("failed to remove team member", zap.String("team_id", teamID), zap.String("user_id", userID), zap.Error(err))
+		return annotation, err
+	}
+
+	return annotation, nil
+}
+
+func newOpsgenieTeamBuilder(c *client.OpsgenieClient) *opsgenieTeamBuilder {
+	return &opsgenieTeamBuilder{
+		resourceType: teamResourceType,
+		client:       c,
+	}
+}
+
+func parseIntoOpsgenieTeamResource(_ context.Context, team *client.OpsgenieTeam, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"team_id":     team.ID,
+		"name":        team.Name,
+		"description": team.Description,
+	}
+
+	groupTraits := []resource.GroupTraitOption{
+		resource.WithGroupProfile(profile),
+	}
+
+	ret, err := resource.NewGroupResource(
+		team.Name,
+		teamResourceType,
+		team.ID,
+		groupTraits,
+		resource.WithParentResourceID(parentResourceID),
+		resource.WithDescription(team.Description),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// opsgenieTeamMemberRole returns the role of the user on the team, or an empty string when it is not a member.
+func opsgenieTeamMemberRole(team *client.OpsgenieTeam, userID string) string {
+	for _, member := range team.Members {
+		if member.User.ID == userID {
+			return member.Role
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,188 @@
+package connector
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+)
+
+// Tests that NewOpsgenie syncs the Opsgenie account at the base URL, authenticated with the API key.
+func TestNewOpsgenie(t *testing.T) {
+	server, transport := test.NewFixtureServer(t, test.OpsgenieFixtures)
+	ctx := context.Background()
+
+	c, err := NewOpsgenie(ctx, server.URL, "key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	syncers := c.ResourceSyncers(ctx)
+	resourceTypes := make([]string, 0, len(syncers))
+	for _, syncer := range syncers {
+		resourceTypes = append(resourceTypes, syncer.ResourceType(ctx).Id)
+	}
+	expected := []string{userResourceType.Id, accountRoleResourceType.Id, teamResourceType.Id, scheduleResourceType.Id}
+	if !slices.Equal(resourceTypes, expected) {
+		t.Fatalf("Expected resource types %v, got %v", expected, resourceTypes)
+	}
+
+	users, _ := listAll(t, syncers[0])
+	if len(users) != 3 || users[0].Id.Resource != "u-1" {
+		t.Fatalf("Expected the 3 users by ID, got %v", users)
+	}
+
+	if got := transport.Requests("GET /v2/users")[0].Header.Get("Authorization"); got != "GenieKey key" {
+		t.Errorf("Expected requests to be authenticated with the API key, got %q", got)
+	}
+}
+
+// Tests that users are listed page after page by offset until Opsgenie returns a short page.
+func TestOpsgenieUserBuilder_ListsEveryPage(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(map[string]string{
+		"GET /v2/users?offset=0&limit=2": "OpsgenieUsersFirstPage.json",
+		"GET /v2/users?offset=2&limit=2": "OpsgenieUsersLastPage.json",
+	})
+	builder := newOpsgenieUserBuilder(test.NewFixtureOpsgenieClient(transport))
+
+	var ids []string
+	pToken := &pagination.Token{Size: 2}
+	for pages := 1; ; pages++ {
+		users, next, _, err := builder.List(context.Background(), nil, pToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, user := range users {
+			ids = append(ids, user.Id.Resource)
+		}
+		if next == "" {
+			if pages != 2 {
+				t.Errorf("Expected 2 pages, got %d", pages)
+			}
+			break
+		}
+		pToken = &pagination.Token{Size: 2, Token: next}
+	}
+
+	if !slices.Equal(ids, []string{"u-1", "u-2", "u-3"}) {
+		t.Errorf("Expected the 3 users in order, got %v", ids)
+	}
+}
+
+// Tests that account roles, team roles and schedule participation are granted to the users and teams holding
+// them.
+func TestOpsgenieSyncers_Grants(t *testing.T) {
+	syncers := (&Connector{opsgenie: test.NewFixtureOpsgenieClient(test.NewFixtureRoundTripper(test.OpsgenieFixtures))}).ResourceSyncers(context.Background())
+
+	roles, roleGrants := listAll(t, syncers[1])
+	if len(roles) != len(client.OpsgenieRoles)+1 || roles[len(roles)-1].Id.Resource != "Responder" {
+		t.Fatalf("Expected the built-in roles and the custom Responder role, got %v", roles)
+	}
+	if len(roleGrants) != 3 {
+		t.Fatalf("Expected every user to hold one role, got %d grants", len(roleGrants))
+	}
+
+	_, teamGrants := listAll(t, syncers[2])
+	if len(teamGrants) != 2 || teamGrants[0].Principal.Id.Resource != "u-2" {
+		t.Fatalf("Expected alice and bob to be team members, got %v", teamGrants)
+	}
+
+	_, scheduleGrants := listAll(t, syncers[3])
+	if len(scheduleGrants) != 2 {
+		t.Fatalf("Expected alice once and the team as participants, got %v", scheduleGrants)
+	}
+	teamGrant := scheduleGrants[1]
+	annos := annotations.Annotations(teamGrant.Annotations)
+	if teamGrant.Principal.Id.ResourceType != teamResourceType.Id || !annos.Contains(&v2.GrantExpandable{}) {
+		t.Errorf("Expected an expandable grant to the team, got %v", teamGrant)
+	}
+}
+
+// Tests that team membership is granted with the role of the entitlement, and that a member holding the user
+// role is promoted and removed.
+func TestOpsgenieTeamBuilder_Provisioning(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(test.OpsgenieFixtures)
+	ctx := context.Background()
+	builder := newOpsgenieTeamBuilder(test.NewFixtureOpsgenieClient(transport))
+
+	team, err := parseIntoOpsgenieTeamResource(ctx, &client.OpsgenieTeam{ID: "t-1", Name: "Operations"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	owner, err := parseIntoOpsgenieUserResource(ctx, &client.OpsgenieUser{ID: "u-1", Username: "owner@example.com", FullName: "Owner", Role: client.OpsgenieRole{ID: "Owner", Name: "Owner"}}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	bob, err := parseIntoOpsgenieUserResource(ctx, &client.OpsgenieUser{ID: "u-3", Username: "bob@example.com", FullName: "Bob", Role: client.OpsgenieRole{ID: "r-1", Name: "Responder"}}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	entitlements, _, _, err := builder.Entitlements(ctx, team, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := builder.Grant(ctx, owner, entitlements[0]); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := builder.Grant(ctx, bob, entitlements[0]); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	added := transport.Requests("POST /v2/teams/t-1/members")
+	if len(added) != 2 || added[0].Body != `{"user":{"id":"u-1"},"role":"admin"}` || added[1].Body != `{"user":{"id":"u-3"},"role":"admin"}` {
+		t.Fatalf("Expected the owner to be added and bob to be promoted as admins, got %v", added)
+	}
+
+	revoke := &v2.Grant{Entitlement: entitlements[1], Principal: bob}
+	if _, err := builder.Revoke(ctx, revoke); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if removed := transport.Requests("DELETE /v2/teams/t-1/members/u-3"); len(removed) != 1 {
+		t.Fatalf("Expected bob to be removed once, got %d requests", len(removed))
+	}
+	transport.SetRoute("DELETE /v2/teams/t-1/members/u-3", "404")
+	annos, err := builder.Revoke(ctx, revoke)
+	if err != nil || !annos.Contains(&v2.GrantAlreadyRevoked{}) {
+		t.Fatalf("Expected a member removed concurrently to be already revoked, got %v and %v", annos, err)
+	}
+}
+
+// Tests that granting and revoking a team role leaves the admin role of a member in place.
+func TestOpsgenieTeamBuilder_KeepsHeldRole(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(test.OpsgenieFixtures)
+	ctx := context.Background()
+	builder := newOpsgenieTeamBuilder(test.NewFixtureOpsgenieClient(transport))
+
+	team, err := parseIntoOpsgenieTeamResource(ctx, &client.OpsgenieTeam{ID: "t-1", Name: "Operations"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	alice, err := parseIntoOpsgenieUserResource(ctx, &client.OpsgenieUser{ID: "u-2", Username: "alice@example.com", FullName: "Alice", Role: client.OpsgenieRole{ID: "Admin", Name: "Admin"}}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	entitlements, _, _, err := builder.Entitlements(ctx, team, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := builder.Grant(ctx, alice, entitlements[1]); err == nil {
+		t.Fatalf("Expected granting the user role to a team admin to fail")
+	}
+	annos, err := builder.Grant(ctx, alice, entitlements[0])
+	if err != nil || !annos.Contains(&v2.GrantAlreadyExists{}) {
+		t.Fatalf("Expected the held admin role to be already granted, got %v", err)
+	}
+	annos, err = builder.Revoke(ctx, &v2.Grant{Entitlement: entitlements[1], Principal: alice})
+	if err != nil || !annos.Contains(&v2.GrantAlreadyRevoked{}) {
+		t.Fatalf("Expected a stale user role grant to be already revoked, got %v", err)
+	}
+	if changes := len(transport.Requests("POST /v2/teams/t-1/members")) + len(transport.Requests("DELETE /v2/teams/t-1/members/u-2")); changes != 0 {
+		t.Fatalf("Expected alice to still administer the team, got %d membership changes", changes)
+	}
+}
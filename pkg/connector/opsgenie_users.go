@@ -0,0 +1,119 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type opsgenieUserBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.OpsgenieClient
+}
+
+func (o *opsgenieUserBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return userResourceType
+}
+
+// List returns the users of the Opsgenie account.
+func (o *opsgenieUserBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	users, nextPageToken, annotation, err := o.client.ListUsers(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, user := range users {
+		userCopy := user
+		userResource, err := parseIntoOpsgenieUserResource(ctx, &userCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, userResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+// Entitlements always returns an empty slice for users.
+func (o *opsgenieUserBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for users since they don't have any entitlements.
+func (o *opsgenieUserBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func newOpsgenieUserBuilder(c *client.OpsgenieClient) *opsgenieUserBuilder {
+	return &opsgenieUserBuilder{
+		resourceType: userResourceType,
+		client:       c,
+	}
+}
+
+// parseIntoOpsgenieUserResource returns the user with its Opsgenie ID. The username is the user's email.
+func parseIntoOpsgenieUserResource(_ context.Context, user *client.OpsgenieUser, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	userStatus := v2.UserTrait_Status_STATUS_ENABLED
+	if user.Blocked {
+		userStatus = v2.UserTrait_Status_STATUS_DISABLED
+	}
+
+	profile := map[string]interface{}{
+		"user_id":   user.ID,
+		"username":  user.Username,
+		"email":     user.Username,
+		"role":      user.Role.Name,
+		"blocked":   user.Blocked,
+		"verified":  user.Verified,
+		"time_zone": user.TimeZone,
+	}
+
+	userTraits := []resource.UserTraitOption{
+		resource.WithUserProfile(profile),
+		resource.WithStatus(userStatus),
+		resource.WithUserLogin(user.Username),
+		resource.WithEmail(user.Username, true),
+	}
+
+	displayName := user.FullName
+	if displayName == "" {
+		displayName = user.Username
+	}
+
+	ret, err := resource.NewUserResource(
+		displayName,
+		userResourceType,
+		user.ID,
+		userTraits,
+		resource.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
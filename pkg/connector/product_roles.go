@@ -0,0 +1,368 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ProductAccessModeRoleAssignment grants product access by assigning product roles through the Admin API.
+	ProductAccessModeRoleAssignment = "role-assignment"
+	// ProductAccessModeDefaultGroup grants product user access by managing the product's default access group.
+	ProductAccessModeDefaultGroup = "default-group"
+
+	productRoleAssignedEntitlement = "assigned"
+)
+
+type productRole struct {
+	key         string
+	roleID      string
+	displayName string
+}
+
+var productRoles = []productRole{
+	{key: "user", roleID: client.ProductRoleUser, displayName: "User"},
+	{key: "admin", roleID: client.ProductRoleAdmin, displayName: "Admin"},
+}
+
+var productDisplayNames = map[string]string{
+	"jira-software":          "Jira",
+	"jira-servicedesk":       "Jira Service Management",
+	"jira-product-discovery": "Jira Product Discovery",
+	"confluence":             "Confluence",
+	"bitbucket":              "Bitbucket",
+	"compass":                "Compass",
+	"opsgenie":               "Opsgenie",
+	"statuspage":             "Statuspage",
+	"trello":                 "Trello",
+}
+
+type productRoleBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.AtlassianClient
+	accessMode   string
+	changes      *changeLog
+}
+
+func (o *productRoleBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return productRoleResourceType
+}
+
+// List returns a product role resource for every role of every product instance in the organization.
+func (o *productRoleBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, productRoleResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	workspaces, nextPageToken, annotation, err := o.client.ListWorkspaces(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, workspace := range workspaces {
+		workspaceCopy := workspace
+		for _, role := range productRoles {
+			roleResource, err := parseIntoProductRoleResource(ctx, &workspaceCopy, role, nil)
+			if err != nil {
+				return nil, "", annotation, err
+			}
+			resources = append(resources, roleResource)
+		}
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+func (o *productRoleBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	assigmentOptions := []entitlement.EntitlementOption{
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDescription(fmt.Sprintf("Assigned the %s product role", resource.DisplayName)),
+		entitlement.WithDisplayName(resource.DisplayName),
+	}
+
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(resource, productRoleAssignedEntitlement, assigmentOptions...),
+	}, "", nil, nil
+}
+
+func (o *productRoleBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+
+	role, workspaceID, err := parseProductRoleResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	entitlementID := entitlement.NewEntitlementID(resource, productRoleAssignedEntitlement)
+	etag := previousETag(resource, pageToken, entitlementID)
+	// Access changes through events on the product, on the role itself, or on any group, as group
+	// membership grants product access.
+	if o.changes.unchanged(ctx, etag, workspaceID, role.roleID, eventObjectGroup) {
+		return nil, "", etagMatch(entitlementID), nil
+	}
+
+	users, nextPageToken, annotation, err := o.client.ListProductRoleUsers(ctx, workspaceID, role.roleID, client.PageOptions{
+		PageSize:    pToken.Size,
+		PageToken:   pageToken,
+		IfNoneMatch: conditionalETag(etag),
+	})
+	if errors.Is(err, client.ErrNotModified) {
+		return nil, "", etagMatch(entitlementID), nil
+	}
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, user := range users {
+		principalID := &v2.ResourceId{
+			ResourceType: userResourceType.Id,
+			Resource:     userResourceID(user.AccountID),
+		}
+		grants = append(grants, grant.NewGrant(resource, productRoleAssignedEntitlement, principalID))
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return grants, nextPageToken, o.changes.annotate(annotation, entitlementID, nextPageToken), nil
+}
+
+// Grant gives the principal the product role. Granting access to an already assigned role succeeds, even on
+// a full product, and granting new user access on a product without free seats fails with a
+// ResourceExhausted error.
+func (o *productRoleBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if principal.Id.ResourceType != userResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: only users can be granted product access")
+	}
+
+	role, workspaceID, err := parseProductRoleResourceID(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+	accountID := accountIDFromUserResourceID(principal.Id.Resource)
+
+	held, annotation, err := o.holdsRole(ctx, role, workspaceID, accountID)
+	if err != nil {
+		l.Error("failed to look up product access", zap.String("workspace_id", workspaceID), zap.String("role", role.key), zap.String("account_id", accountID), zap.Error(err))
+		return annotation, err
+	}
+	if held {
+		annotation.Append(&v2.GrantAlreadyExists{})
+		return annotation, nil
+	}
+
+	if o.usesDefaultGroup(role) {
+		annotation, err = o.addToDefaultGroup(ctx, workspaceID, accountID)
+	} else {
+		annotation, err = o.client.AssignProductRole(ctx, workspaceID, role.roleID, accountID)
+	}
+	if err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			annotation.Append(&v2.GrantAlreadyExists{})
+			return annotation, nil
+		}
+		l.Error("failed to grant product access", zap.String("workspace_id", workspaceID), zap.String("role", role.key), zap.String("account_id", accountID), zap.Error(err))
+		return annotation, wrapProvisioningError(err)
+	}
+
+	return annotation, nil
+}
+
+// Revoke removes the product role from the principal. Revoking a role the principal no longer holds succeeds.
+func (o *productRoleBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	principal := grant.Principal
+	if principal.Id.ResourceType != userResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: only users can have product access revoked")
+	}
+
+	role, workspaceID, err := parseProductRoleResourceID(grant.Entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+	accountID := accountIDFromUserResourceID(principal.Id.Resource)
+
+	var annotation annotations.Annotations
+	if o.usesDefaultGroup(role) {
+		annotation, err = o.removeFromDefaultGroups(ctx, workspaceID, accountID)
+	} else {
+		annotation, err = o.client.RevokeProductRole(ctx, workspaceID, role.roleID, accountID)
+	}
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			annotation.Append(&v2.GrantAlreadyRevoked{})
+			return annotation, nil
+		}
+		l.Error("failed to revoke product access", zap.String("workspace_id", workspaceID), zap.String("role", role.key), zap.String("account_id", accountID), zap.Error(err))
+		return annotation, wrapProvisioningError(err)
+	}
+
+	return annotation, nil
+}
+
+// usesDefaultGroup reports whether access for the role is managed through the product's default access
+// group. Admin roles are always assigned directly.
+func (o *productRoleBuilder) usesDefaultGroup(role productRole) bool {
+	return o.accessMode == ProductAccessModeDefaultGroup && role.roleID == client.ProductRoleUser
+}
+
+// holdsRole reports whether the account already has the role, through one of the product's default access
+// groups when access is managed by group.
+func (o *productRoleBuilder) holdsRole(ctx context.Context, role productRole, workspaceID, accountID string) (bool, annotations.Annotations, error) {
+	if !o.usesDefaultGroup(role) {
+		return o.client.HasProductRole(ctx, workspaceID, role.roleID, accountID)
+	}
+
+	workspace, annotation, err := o.client.GetWorkspace(ctx, workspaceID)
+	if err != nil {
+		return false, annotation, err
+	}
+
+	for _, groupID := range workspace.Attributes.DefaultAccessGroupIDs {
+		member, _, err := o.client.IsGroupMember(ctx, groupID, accountID)
+		if err != nil {
+			return false, annotation, err
+		}
+		if member {
+			return true, annotation, nil
+		}
+	}
+
+	return false, annotation, nil
+}
+
+func (o *productRoleBuilder) addToDefaultGroup(ctx context.Context, workspaceID, accountID string) (annotations.Annotations, error) {
+	workspace, annotation, err := o.client.CheckSeatAvailable(ctx, workspaceID)
+	if err != nil {
+		return annotation, err
+	}
+
+	if len(workspace.Attributes.DefaultAccessGroupIDs) == 0 {
+		return annotation, fmt.Errorf("baton-atlassian: product %s has no default access group", workspaceID)
+	}
+
+	return o.client.AddGroupMember(ctx, workspace.Attributes.DefaultAccessGroupIDs[0], accountID)
+}
+
+func (o *productRoleBuilder) removeFromDefaultGroups(ctx context.Context, workspaceID, accountID string) (annotations.Annotations, error) {
+	workspace, annotation, err := o.client.GetWorkspace(ctx, workspaceID)
+	if err != nil {
+		return annotation, err
+	}
+
+	var errs []error
+	for _, groupID := range workspace.Attributes.DefaultAccessGroupIDs {
+		_, err := o.client.RemoveGroupMember(ctx, groupID, accountID)
+		if err != nil && status.Code(err) != codes.NotFound {
+			errs = append(errs, err)
+		}
+	}
+
+	return annotation, errors.Join(errs...)
+}
+
+func newProductRoleBuilder(c *client.AtlassianClient, accessMode string, changes *changeLog) *productRoleBuilder {
+	return &productRoleBuilder{
+		resourceType: productRoleResourceType,
+		client:       c,
+		accessMode:   accessMode,
+		changes:      changes,
+	}
+}
+
+func parseIntoProductRoleResource(_ context.Context, workspace *client.Workspace, role productRole, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	productName, ok := productDisplayNames[workspace.Attributes.TypeKey]
+	if !ok {
+		productName = workspace.Attributes.TypeKey
+	}
+	site := strings.TrimPrefix(workspace.Attributes.HostURL, "https://")
+
+	profile := map[string]interface{}{
+		"workspace_id": workspace.ID,
+		"product":      workspace.Attributes.TypeKey,
+		"site":         site,
+		"role_id":      role.roleID,
+		"usage":        workspace.Attributes.Usage,
+		"capacity":     workspace.Attributes.Capacity,
+	}
+
+	roleTraits := []resource.RoleTraitOption{
+		resource.WithRoleProfile(profile),
+	}
+
+	ret, err := resource.NewRoleResource(
+		fmt.Sprintf("%s %s on %s", productName, role.displayName, site),
+		productRoleResourceType,
+		productRoleResourceID(role, workspace.ID),
+		roleTraits,
+		resource.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// productRoleResourceID combines the role key and the workspace ARI, e.g. "user:ari:cloud:confluence::site/<id>".
+func productRoleResourceID(role productRole, workspaceID string) string {
+	return fmt.Sprintf("%s:%s", role.key, workspaceID)
+}
+
+func parseProductRoleResourceID(resourceID string) (productRole, string, error) {
+	key, workspaceID, ok := strings.Cut(resourceID, ":")
+	if ok {
+		for _, role := range productRoles {
+			if role.key == key {
+				return role, workspaceID, nil
+			}
+		}
+	}
+
+	return productRole{}, "", fmt.Errorf("baton-atlassian: invalid product role ID %q", resourceID)
+}
@@ -0,0 +1,111 @@
+package connector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Tests that granting product access on a product without free seats fails before the role is assigned.
+func TestProductRoleBuilder_GrantLicenseLimit(t *testing.T) {
+	builder, capturedRequests := newFullProductRoleBuilder(`{"data": [], "links": {}}`)
+	roleResource := newFullProductRoleResource(t)
+
+	principal := &v2.Resource{Id: &v2.ResourceId{ResourceType: userResourceType.Id, Resource: userResourceID(test.UserIDs[0])}}
+	assignedEntitlement := &v2.Entitlement{Id: "product_role:user:assigned", Resource: roleResource}
+
+	_, err := builder.Grant(context.Background(), principal, assignedEntitlement)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected resource exhausted error, got %v", err)
+	}
+
+	for _, request := range *capturedRequests {
+		if request.Method != http.MethodGet {
+			t.Errorf("Expected only lookups, got %s %s", request.Method, request.URL.Path)
+		}
+	}
+}
+
+// Tests that granting a product role the user already holds succeeds on a full product, without a seat check.
+func TestProductRoleBuilder_GrantAlreadyHeldOnFullProduct(t *testing.T) {
+	holder := `{"data": [{"accountId": "` + test.UserIDs[0] + `", "name": "User 1"}], "links": {}}`
+	builder, capturedRequests := newFullProductRoleBuilder(holder)
+	roleResource := newFullProductRoleResource(t)
+
+	principal := &v2.Resource{Id: &v2.ResourceId{ResourceType: userResourceType.Id, Resource: userResourceID(test.UserIDs[0])}}
+	assignedEntitlement := &v2.Entitlement{Id: "product_role:user:assigned", Resource: roleResource}
+
+	annos, err := builder.Grant(context.Background(), principal, assignedEntitlement)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !annos.Contains(&v2.GrantAlreadyExists{}) {
+		t.Errorf("Expected the grant to be reported as already existing")
+	}
+
+	if len(*capturedRequests) != 1 {
+		t.Fatalf("Expected only the assignment lookup, got %d requests", len(*capturedRequests))
+	}
+	query := (*capturedRequests)[0].URL.Query()
+	if query.Get("accountIds") != test.UserIDs[0] || query.Get("roleIds") != client.ProductRoleUser {
+		t.Errorf("Unexpected assignment lookup %s", query.Encode())
+	}
+}
+
+// newFullProductRoleBuilder returns a product role builder for a Confluence site with every seat in use, whose
+// role holders lookups answer with holders, and the requests it sends.
+func newFullProductRoleBuilder(holders string) (*productRoleBuilder, *[]*http.Request) {
+	workspace := `{"data": {"id": "ari:cloud:confluence::site/site-1", "type": "workspace", "attributes": {"name": "acme", ` +
+		`"typeKey": "confluence", "hostUrl": "https://acme.atlassian.net", "usage": 10, "capacity": 10}}}`
+
+	var capturedRequests []*http.Request
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		capturedRequests = append(capturedRequests, req)
+
+		body := workspace
+		if strings.HasSuffix(req.URL.Path, "/directories/-/users") {
+			body = holders
+		}
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+		resp.Header.Set("Content-Type", "application/json")
+		return resp, nil
+	})
+
+	baseHttpClient := uhttp.NewBaseHttpClient(&http.Client{Transport: mockTransport})
+	c := client.NewClient("", "", "admin-key", client.Endpoints{}, test.OrganizationID, "", baseHttpClient)
+	return newProductRoleBuilder(c, ProductAccessModeRoleAssignment, nil), &capturedRequests
+}
+
+func newFullProductRoleResource(t *testing.T) *v2.Resource {
+	t.Helper()
+
+	roleResource, err := parseIntoProductRoleResource(context.Background(), &client.Workspace{
+		ID: "ari:cloud:confluence::site/site-1",
+		Attributes: client.WorkspaceAttributes{
+			TypeKey: "confluence",
+			HostURL: "https://acme.atlassian.net",
+		},
+	}, productRoles[0], nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if roleResource.DisplayName != "Confluence User on acme.atlassian.net" {
+		t.Errorf("Unexpected display name %s", roleResource.DisplayName)
+	}
+
+	return roleResource
+}
@@ -15,3 +15,121 @@ var teamResourceType = &v2.ResourceType{
 	DisplayName: "Team",
 	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_GROUP},
 }
+
+var groupResourceType = &v2.ResourceType{
+	Id:          "group",
+	DisplayName: "Group",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_GROUP},
+}
+
+var productRoleResourceType = &v2.ResourceType{
+	Id:          "product_role",
+	DisplayName: "Product Role",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_ROLE},
+}
+
+var projectRoleResourceType = &v2.ResourceType{
+	Id:          "project_role",
+	DisplayName: "Project Role",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_ROLE},
+}
+
+var globalPermissionResourceType = &v2.ResourceType{
+	Id:          "global_permission",
+	DisplayName: "Global Permission",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_ROLE},
+}
+
+var spaceResourceType = &v2.ResourceType{
+	Id:          "space",
+	DisplayName: "Space",
+}
+
+var projectResourceType = &v2.ResourceType{
+	Id:          "project",
+	DisplayName: "Project",
+}
+
+var repositoryResourceType = &v2.ResourceType{
+	Id:          "repository",
+	DisplayName: "Repository",
+}
+
+var accessTokenResourceType = &v2.ResourceType{
+	Id:          "access_token",
+	DisplayName: "Access Token",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_SECRET},
+}
+
+var sshKeyResourceType = &v2.ResourceType{
+	Id:          "ssh_key",
+	DisplayName: "SSH Key",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_SECRET},
+}
+
+var accountRoleResourceType = &v2.ResourceType{
+	Id:          "account_role",
+	DisplayName: "Account Role",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_ROLE},
+}
+
+var scheduleResourceType = &v2.ResourceType{
+	Id:          "schedule",
+	DisplayName: "Schedule",
+}
+
+var enterpriseResourceType = &v2.ResourceType{
+	Id:          "enterprise",
+	DisplayName: "Enterprise",
+}
+
+var workspaceResourceType = &v2.ResourceType{
+	Id:          "workspace",
+	DisplayName: "Workspace",
+}
+
+var boardResourceType = &v2.ResourceType{
+	Id:          "board",
+	DisplayName: "Board",
+}
+
+var pageResourceType = &v2.ResourceType{
+	Id:          "page",
+	DisplayName: "Page",
+}
+
+var pageAccessGroupResourceType = &v2.ResourceType{
+	Id:          "page_access_group",
+	DisplayName: "Page Access Group",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_GROUP},
+}
+
+var subscriberResourceType = &v2.ResourceType{
+	Id:          "subscriber",
+	DisplayName: "Subscriber",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_USER},
+}
+
+var compassComponentResourceType = &v2.ResourceType{
+	Id:          "compass_component",
+	DisplayName: "Compass Component",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_APP},
+}
+
+var authPolicyResourceType = &v2.ResourceType{
+	Id:          "auth_policy",
+	DisplayName: "Authentication Policy",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_GROUP},
+}
+
+var apiKeyResourceType = &v2.ResourceType{
+	Id:          "api_key",
+	DisplayName: "API Key",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_SECRET},
+}
+
+var apiTokenResourceType = &v2.ResourceType{
+	Id:          "api_token",
+	DisplayName: "API Token",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_SECRET},
+}
@@ -0,0 +1,37 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// NewStatuspage returns a connector that syncs the pages of a Statuspage organization through the Statuspage
+// REST API at baseURL, or client.DefaultStatuspageURL if it is empty, instead of Atlassian cloud.
+func NewStatuspage(ctx context.Context, baseURL, organizationID, apiKey string) (*Connector, error) {
+	l := ctxzap.Extract(ctx)
+
+	statuspageClient, err := client.NewStatuspageClient(ctx, baseURL, organizationID, apiKey)
+	if err != nil {
+		l.Error("error creating Statuspage client", zap.Error(err))
+		return nil, err
+	}
+
+	return &Connector{
+		statuspage:            statuspageClient,
+		statuspagePermissions: newStatuspagePermissions(statuspageClient),
+	}, nil
+}
+
+// statuspageSyncers returns the syncers of a Statuspage organization.
+func (d *Connector) statuspageSyncers(_ context.Context) []connectorbuilder.ResourceSyncer {
+	return []connectorbuilder.ResourceSyncer{
+		newStatuspageUserBuilder(d.statuspage),
+		newStatuspagePageBuilder(d.statuspage, d.statuspagePermissions),
+		newStatuspageAccessGroupBuilder(d.statuspage),
+		newStatuspageSubscriberBuilder(d.statuspage),
+	}
+}
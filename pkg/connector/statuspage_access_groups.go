@@ -0,0 +1,195 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statuspageAccessGroupBuilder reports the page access groups of audience-specific pages, whose member
+// entitlement is held by the page's subscribers in the group.
+type statuspageAccessGroupBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.StatuspageClient
+}
+
+func (o *statuspageAccessGroupBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return pageAccessGroupResourceType
+}
+
+// List returns every page access group of the parent page in a single page.
+func (o *statuspageAccessGroupBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	groups, annotation, err := o.client.ListAccessGroups(ctx, parentResourceID.Resource)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, group := range groups {
+		groupCopy := group
+		groupResource, err := parseIntoStatuspageAccessGroupResource(ctx, &groupCopy, parentResourceID)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, groupResource)
+	}
+
+	return resources, "", annotation, nil
+}
+
+func (o *statuspageAccessGroupBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	assigmentOptions := []entitlement.EntitlementOption{
+		entitlement.WithGrantableTo(subscriberResourceType),
+		entitlement.WithDescription(fmt.Sprintf("Member of the %s page access group", resource.DisplayName)),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Page Access Group Member", resource.DisplayName)),
+	}
+
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(resource, groupMemberEntitlement, assigmentOptions...),
+	}, "", nil, nil
+}
+
+// Grants returns a grant to every subscriber in the group.
+func (o *statuspageAccessGroupBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+
+	pageID, groupID, err := parseStatuspageAccessGroupResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	group, annotation, err := o.client.GetAccessGroup(ctx, pageID, groupID)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, userID := range group.PageAccessUserIDs {
+		principalID := &v2.ResourceId{
+			ResourceType: subscriberResourceType.Id,
+			Resource:     userID,
+		}
+		grants = append(grants, grant.NewGrant(resource, groupMemberEntitlement, principalID))
+	}
+
+	return grants, "", annotation, nil
+}
+
+// Grant adds the subscriber to the page access group.
+func (o *statuspageAccessGroupBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if principal.Id.ResourceType != subscriberResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: only subscribers can be granted page access group membership")
+	}
+
+	pageID, groupID, err := parseStatuspageAccessGroupResourceID(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+	userID := principal.Id.Resource
+
+	annotation, err := o.client.AddAccessGroupUser(ctx, pageID, groupID, userID)
+	if err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			annotation.Append(&v2.GrantAlreadyExists{})
+			return annotation, nil
+		}
+		l.Error("failed to add page access group member", zap.String("page_access_group_id", groupID), zap.String("page_access_user_id", userID), zap.Error(err))
+		return annotation, err
+	}
+
+	return annotation, nil
+}
+
+// Revoke removes the subscriber from the page access group.
+func (o *statuspageAccessGroupBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	principal := grant.Principal
+	if principal.Id.ResourceType != subscriberResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: only subscribers can have page access group membership revoked")
+	}
+
+	pageID, groupID, err := parseStatuspageAccessGroupResourceID(grant.Entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+	userID := principal.Id.Resource
+
+	annotation, err := o.client.RemoveAccessGroupUser(ctx, pageID, groupID, userID)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			annotation.Append(&v2.GrantAlreadyRevoked{})
+			return annotation, nil
+		}
+		l.Error("failed to remove page access group member", zap.String("page_access_group_id", groupID), zap.String("page_access_user_id", userID), zap.Error(err))
+		return annotation, err
+	}
+
+	return annotation, nil
+}
+
+func newStatuspageAccessGroupBuilder(c *client.StatuspageClient) *statuspageAccessGroupBuilder {
+	return &statuspageAccessGroupBuilder{
+		resourceType: pageAccessGroupResourceType,
+		client:       c,
+	}
+}
+
+func parseIntoStatuspageAccessGroupResource(_ context.Context, group *client.StatuspageAccessGroup, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"page_access_group_id": group.ID,
+		"page_id":              group.PageID,
+		"name":                 group.Name,
+		"external_identifier":  group.ExternalIdentifier,
+	}
+
+	groupTraits := []resource.GroupTraitOption{
+		resource.WithGroupProfile(profile),
+	}
+
+	ret, err := resource.NewGroupResource(
+		group.Name,
+		pageAccessGroupResourceType,
+		statuspageAccessGroupResourceID(group.PageID, group.ID),
+		groupTraits,
+		resource.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// statuspageAccessGroupResourceID combines the page ID and the group ID, as the API addresses groups within
+// their page, e.g. "pg1/grp1".
+func statuspageAccessGroupResourceID(pageID, groupID string) string {
+	return fmt.Sprintf("%s/%s", pageID, groupID)
+}
+
+func parseStatuspageAccessGroupResourceID(resourceID string) (string, string, error) {
+	pageID, groupID, ok := strings.Cut(resourceID, "/")
+	if !ok || pageID == "" || groupID == "" {
+		return "", "", fmt.Errorf("baton-atlassian: invalid page access group ID %q", resourceID)
+	}
+
+	return pageID, groupID, nil
+}
@@ -0,0 +1,206 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// statuspagePageBuilder reports the pages of the organization, with an entitlement for every team member role
+// held by the team members who can administer the page.
+type statuspagePageBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.StatuspageClient
+	permissions  *statuspagePermissions
+}
+
+func (o *statuspagePageBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return pageResourceType
+}
+
+// List returns every page in a single page.
+func (o *statuspagePageBuilder) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	pages, annotation, err := o.client.ListPages(ctx)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, page := range pages {
+		pageCopy := page
+		pageResource, err := parseIntoStatuspagePageResource(ctx, &pageCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, pageResource)
+	}
+
+	return resources, "", annotation, nil
+}
+
+// Entitlements returns an entitlement for every team member role.
+func (o *statuspagePageBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	entitlements := make([]*v2.Entitlement, 0, len(client.StatuspageRoles))
+
+	for _, role := range client.StatuspageRoles {
+		entitlements = append(entitlements, entitlement.NewPermissionEntitlement(resource, role,
+			entitlement.WithGrantableTo(userResourceType),
+			entitlement.WithDescription(fmt.Sprintf("Team member with the %s role on the %s page", role, resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("%s Page %s", resource.DisplayName, role)),
+		))
+	}
+
+	return entitlements, "", nil, nil
+}
+
+// Grants pages through the team members and returns a grant of their role to those who can access the page.
+// Owners and admins access every page; the permissions of users are looked up once per sync.
+func (o *statuspagePageBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+	var grants []*v2.Grant
+
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	users, nextPageToken, annotation, err := o.client.ListUsers(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, user := range users {
+		role := statuspageUserRole(&user)
+		if !slices.Contains(client.StatuspageRoles, role) {
+			l.Warn("skipping team member with an unknown role", zap.String("user_id", user.ID), zap.String("role", role))
+			continue
+		}
+
+		if role == client.StatuspageRoleUser {
+			permissions, annos, err := o.permissions.Get(ctx, user.ID)
+			updateRateLimit(&annotation, annos)
+			if err != nil {
+				return nil, "", annotation, err
+			}
+			if !permissions.CanAccess(resource.Id.Resource) {
+				continue
+			}
+		}
+
+		principalID := &v2.ResourceId{
+			ResourceType: userResourceType.Id,
+			Resource:     user.ID,
+		}
+		grants = append(grants, grant.NewGrant(resource, role, principalID))
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return grants, nextPageToken, annotation, nil
+}
+
+func newStatuspagePageBuilder(c *client.StatuspageClient, permissions *statuspagePermissions) *statuspagePageBuilder {
+	return &statuspagePageBuilder{
+		resourceType: pageResourceType,
+		client:       c,
+		permissions:  permissions,
+	}
+}
+
+// statuspagePermissions keeps the page permissions of team members for the duration of a sync, as they are
+// needed for the grants of every page. Connector.Validate calls Reset before the next one.
+type statuspagePermissions struct {
+	client *client.StatuspageClient
+
+	mtx         sync.Mutex
+	permissions map[string]*client.StatuspagePermissions
+}
+
+func newStatuspagePermissions(c *client.StatuspageClient) *statuspagePermissions {
+	return &statuspagePermissions{
+		client: c,
+	}
+}
+
+// Reset drops the permissions looked up so far.
+func (p *statuspagePermissions) Reset() {
+	if p == nil {
+		return
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.permissions = nil
+}
+
+// Get returns the permissions of the team member. The annotations are those of the lookup, or empty when the
+// permissions were already looked up.
+func (p *statuspagePermissions) Get(ctx context.Context, userID string) (*client.StatuspagePermissions, annotations.Annotations, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if permissions, ok := p.permissions[userID]; ok {
+		return permissions, nil, nil
+	}
+
+	permissions, annotation, err := p.client.GetPermissions(ctx, userID)
+	if err != nil {
+		return nil, annotation, err
+	}
+	if p.permissions == nil {
+		p.permissions = make(map[string]*client.StatuspagePermissions)
+	}
+	p.permissions[userID] = permissions
+
+	return permissions, annotation, nil
+}
+
+// parseIntoStatuspagePageResource returns the page, whose page access groups and subscribers are listed as its
+// children.
+func parseIntoStatuspagePageResource(_ context.Context, page *client.StatuspagePage, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	description := page.URL
+	if page.Domain != "" {
+		description = page.Domain
+	}
+
+	ret, err := resource.NewResource(
+		page.Name,
+		pageResourceType,
+		page.ID,
+		resource.WithParentResourceID(parentResourceID),
+		resource.WithDescription(description),
+		resource.WithAnnotation(
+			&v2.ChildResourceType{ResourceTypeId: pageAccessGroupResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: subscriberResourceType.Id},
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
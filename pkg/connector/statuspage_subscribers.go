@@ -0,0 +1,114 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// statuspageSubscriberBuilder reports the page access users of audience-specific pages, who subscribe to the
+// page and see the components of their page access groups.
+type statuspageSubscriberBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.StatuspageClient
+}
+
+func (o *statuspageSubscriberBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return subscriberResourceType
+}
+
+// List returns the page access users of the parent page.
+func (o *statuspageSubscriberBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	bag, pageToken, err := getToken(pToken, subscriberResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	users, nextPageToken, annotation, err := o.client.ListAccessUsers(ctx, parentResourceID.Resource, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, user := range users {
+		userCopy := user
+		subscriberResource, err := parseIntoStatuspageSubscriberResource(ctx, &userCopy, parentResourceID)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, subscriberResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+// Entitlements always returns an empty slice for subscribers.
+func (o *statuspageSubscriberBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for subscribers since they don't have any entitlements.
+func (o *statuspageSubscriberBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func newStatuspageSubscriberBuilder(c *client.StatuspageClient) *statuspageSubscriberBuilder {
+	return &statuspageSubscriberBuilder{
+		resourceType: subscriberResourceType,
+		client:       c,
+	}
+}
+
+func parseIntoStatuspageSubscriberResource(_ context.Context, user *client.StatuspageAccessUser, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"page_access_user_id": user.ID,
+		"page_id":             user.PageID,
+		"email":               user.Email,
+		"external_login":      user.ExternalLogin,
+		"created_at":          user.CreatedAt,
+	}
+
+	userTraits := []resource.UserTraitOption{
+		resource.WithUserProfile(profile),
+		resource.WithStatus(v2.UserTrait_Status_STATUS_ENABLED),
+		resource.WithEmail(user.Email, true),
+	}
+	if user.ExternalLogin != "" {
+		userTraits = append(userTraits, resource.WithUserLogin(user.ExternalLogin))
+	}
+
+	ret, err := resource.NewUserResource(
+		user.Email,
+		subscriberResourceType,
+		user.ID,
+		userTraits,
+		resource.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
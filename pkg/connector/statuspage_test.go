@@ -0,0 +1,243 @@
+package connector
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Tests that NewStatuspage syncs the Statuspage organization at the base URL, authenticated with the API key.
+func TestNewStatuspage(t *testing.T) {
+	server, transport := test.NewFixtureServer(t, test.StatuspageFixtures)
+	ctx := context.Background()
+
+	c, err := NewStatuspage(ctx, server.URL, "org-1", "key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	syncers := c.ResourceSyncers(ctx)
+	resourceTypes := make([]string, 0, len(syncers))
+	for _, syncer := range syncers {
+		resourceTypes = append(resourceTypes, syncer.ResourceType(ctx).Id)
+	}
+	expected := []string{userResourceType.Id, pageResourceType.Id, pageAccessGroupResourceType.Id, subscriberResourceType.Id}
+	if !slices.Equal(resourceTypes, expected) {
+		t.Fatalf("Expected resource types %v, got %v", expected, resourceTypes)
+	}
+
+	users, _ := listAll(t, syncers[0])
+	if len(users) != 3 || users[0].Id.Resource != "u-1" {
+		t.Fatalf("Expected the 3 team members by ID, got %v", users)
+	}
+
+	if got := transport.Requests("GET /v1/organizations/org-1/users?page=1")[0].Header.Get("Authorization"); got != "OAuth key" {
+		t.Errorf("Expected requests to be authenticated with the API key, got %q", got)
+	}
+}
+
+// Tests that team members are listed page after page from page 1 until Statuspage returns a short page.
+func TestStatuspageUserBuilder_ListsEveryPage(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(map[string]string{
+		"GET /v1/organizations/org-1/users?page=1&per_page=2": "StatuspageUsersFirstPage.json",
+		"GET /v1/organizations/org-1/users?page=2&per_page=2": "StatuspageUsersLastPage.json",
+	})
+	builder := newStatuspageUserBuilder(test.NewFixtureStatuspageClient(transport))
+
+	var ids []string
+	pToken := &pagination.Token{Size: 2}
+	for pages := 1; ; pages++ {
+		users, next, _, err := builder.List(context.Background(), nil, pToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, user := range users {
+			ids = append(ids, user.Id.Resource)
+		}
+		if next == "" {
+			if pages != 2 {
+				t.Errorf("Expected 2 pages, got %d", pages)
+			}
+			break
+		}
+		pToken = &pagination.Token{Size: 2, Token: next}
+	}
+
+	if !slices.Equal(ids, []string{"u-1", "u-2", "u-3"}) {
+		t.Errorf("Expected the 3 team members in order, got %v", ids)
+	}
+}
+
+// Tests that page roles are granted to the owner and to the users who can access the page, and that page access
+// group membership is granted to subscribers from the group alone.
+func TestStatuspageSyncers_Grants(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(test.StatuspageFixtures)
+	ctx := context.Background()
+	statuspageClient := test.NewFixtureStatuspageClient(transport)
+	syncers := (&Connector{statuspage: statuspageClient, statuspagePermissions: newStatuspagePermissions(statuspageClient)}).ResourceSyncers(ctx)
+
+	pages, pageGrants := listAll(t, syncers[1])
+	if len(pageGrants) != 2 {
+		t.Fatalf("Expected the owner and alice to access the page, got %v", pageGrants)
+	}
+	if pageGrants[0].Entitlement.Id != "page:pg-1:owner" || pageGrants[1].Entitlement.Id != "page:pg-1:user" {
+		t.Errorf("Expected the owner and user roles, got %v", pageGrants)
+	}
+
+	subscribers, _, _, err := syncers[3].List(ctx, pages[0].Id, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(subscribers) != 2 || subscribers[0].Id.Resource != "pau-1" {
+		t.Fatalf("Expected carol and dave to subscribe to the page, got %v", subscribers)
+	}
+
+	groups, _, _, err := syncers[2].List(ctx, pages[0].Id, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(groups) != 1 || groups[0].Id.Resource != "pg-1/pag-1" {
+		t.Fatalf("Expected the Enterprise page access group, got %v", groups)
+	}
+	groupGrants, _, _, err := syncers[2].Grants(ctx, groups[0], &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(groupGrants) != 1 || groupGrants[0].Principal.Id.Resource != "pau-1" {
+		t.Errorf("Expected a member grant to carol, got %v", groupGrants)
+	}
+	if listed := transport.Requests("GET /v1/pages/pg-1/page_access_groups"); len(listed) != 1 {
+		t.Errorf("Expected the page access groups to be listed only for the groups themselves, got %d requests", len(listed))
+	}
+}
+
+// Tests that the permissions of a user are looked up once per sync however many pages there are, that owners
+// are not looked up, and that a validated connector looks them up again.
+func TestStatuspagePageBuilder_LooksUpPermissionsOncePerSync(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(test.StatuspageFixtures)
+	ctx := context.Background()
+	statuspageClient := test.NewFixtureStatuspageClient(transport)
+	c := &Connector{statuspage: statuspageClient, statuspagePermissions: newStatuspagePermissions(statuspageClient)}
+	builder := newStatuspagePageBuilder(statuspageClient, c.statuspagePermissions)
+
+	var resources []*v2.Resource
+	for _, page := range []client.StatuspagePage{{ID: "pg-1", Name: "Customers"}, {ID: "pg-2", Name: "Partners"}} {
+		pageResource, err := parseIntoStatuspagePageResource(ctx, &page, nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resources = append(resources, pageResource)
+	}
+
+	grantsOfEveryPage := func() int {
+		var count int
+		for _, pageResource := range resources {
+			grants, _, _, err := builder.Grants(ctx, pageResource, &pagination.Token{})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			count += len(grants)
+		}
+		return count
+	}
+
+	if grants := grantsOfEveryPage(); grants != 3 {
+		t.Fatalf("Expected the owner on both pages and alice on the first, got %d grants", grants)
+	}
+	if lookups := len(transport.Requests("GET /v1/organizations/org-1/permissions/u-2")); lookups != 1 {
+		t.Fatalf("Expected the permissions of alice to be looked up once, got %d lookups", lookups)
+	}
+	if lookups := len(transport.Requests("GET /v1/organizations/org-1/permissions/u-1")); lookups != 0 {
+		t.Fatalf("Expected the permissions of the owner not to be looked up, got %d lookups", lookups)
+	}
+
+	if _, err := c.Validate(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	grantsOfEveryPage()
+	if lookups := len(transport.Requests("GET /v1/organizations/org-1/permissions/u-2")); lookups != 2 {
+		t.Errorf("Expected the permissions of alice to be looked up again in the next sync, got %d lookups", lookups)
+	}
+}
+
+// Tests that a permission lookup refused by Statuspage fails the page grants, and that a deleted page access
+// group is not found.
+func TestStatuspageBuilders_MapErrors(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(test.StatuspageFixtures)
+	transport.SetRoute("GET /v1/organizations/org-1/permissions/u-3", "403")
+	transport.SetRoute("GET /v1/pages/pg-1/page_access_groups/pag-1", "404")
+	ctx := context.Background()
+	statuspageClient := test.NewFixtureStatuspageClient(transport)
+
+	page, err := parseIntoStatuspagePageResource(ctx, &client.StatuspagePage{ID: "pg-1", Name: "Customers"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	pageBuilder := newStatuspagePageBuilder(statuspageClient, newStatuspagePermissions(statuspageClient))
+	if _, _, _, err := pageBuilder.Grants(ctx, page, &pagination.Token{}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected the refused permission lookup to fail with PermissionDenied, got %v", err)
+	}
+
+	group, err := parseIntoStatuspageAccessGroupResource(ctx, &client.StatuspageAccessGroup{ID: "pag-1", PageID: "pg-1", Name: "Enterprise"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	groupBuilder := newStatuspageAccessGroupBuilder(statuspageClient)
+	if _, _, _, err := groupBuilder.Grants(ctx, group, &pagination.Token{}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected the deleted page access group to fail with NotFound, got %v", err)
+	}
+}
+
+// Tests that subscribers are added to and removed from page access groups, and that existing and missing
+// memberships are reported as already granted and revoked.
+func TestStatuspageAccessGroupBuilder_Provisioning(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(test.StatuspageFixtures)
+	ctx := context.Background()
+	builder := newStatuspageAccessGroupBuilder(test.NewFixtureStatuspageClient(transport))
+
+	group, err := parseIntoStatuspageAccessGroupResource(ctx, &client.StatuspageAccessGroup{ID: "pag-1", PageID: "pg-1", Name: "Enterprise"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	subscriber, err := parseIntoStatuspageSubscriberResource(ctx, &client.StatuspageAccessUser{ID: "pau-2", PageID: "pg-1", Email: "dave@customer.com"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	entitlements, _, _, err := builder.Entitlements(ctx, group, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := builder.Grant(ctx, subscriber, entitlements[0]); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	added := transport.Requests("PUT /v1/pages/pg-1/page_access_groups/pag-1/page_access_users")
+	if len(added) != 1 || added[0].Body != `{"page_access_user_ids":["pau-2"]}` {
+		t.Fatalf("Expected dave to be added, got %v", added)
+	}
+	transport.SetRoute("PUT /v1/pages/pg-1/page_access_groups/pag-1/page_access_users", "409")
+	annos, err := builder.Grant(ctx, subscriber, entitlements[0])
+	if err != nil || !annos.Contains(&v2.GrantAlreadyExists{}) {
+		t.Fatalf("Expected the second grant to already exist, got %v and %v", annos, err)
+	}
+
+	revoke := &v2.Grant{Entitlement: entitlements[0], Principal: subscriber}
+	if _, err := builder.Revoke(ctx, revoke); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if removed := transport.Requests("DELETE /v1/pages/pg-1/page_access_groups/pag-1/page_access_users/pau-2"); len(removed) != 1 {
+		t.Fatalf("Expected dave to be removed once, got %d requests", len(removed))
+	}
+	transport.SetRoute("DELETE /v1/pages/pg-1/page_access_groups/pag-1/page_access_users/pau-2", "404")
+	annos, err = builder.Revoke(ctx, revoke)
+	if err != nil || !annos.Contains(&v2.GrantAlreadyRevoked{}) {
+		t.Fatalf("Expected the second revoke to be already revoked, got %v and %v", annos, err)
+	}
+}
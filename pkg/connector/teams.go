@@ -2,9 +2,7 @@ package connector
 
 import (
 	"context"
-
 	"fmt"
-	"sync"
 
 	"github.com/conductorone/baton-atlassian/pkg/client"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -18,8 +16,7 @@ import (
 type teamBuilder struct {
 	resourceType *v2.ResourceType
 	client       *client.AtlassianClient
-	teams        []client.TeamEdge
-	teamsMutex   sync.RWMutex
+	directory    *directory
 }
 
 var teamMembershipRoles = []string{"REGULAR", "ADMIN"}
@@ -31,14 +28,18 @@ func (o *teamBuilder) ResourceType(_ context.Context) *v2.ResourceType {
 func (o *teamBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
 	var resources []*v2.Resource
 
-	nextPageToken, annotation, err := o.GetTeams(ctx, pToken)
+	bag, offset, err := getOffsetToken(pToken, teamResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
 
+	teams, next, err := o.directory.Teams(ctx, offset, getPageSize(pToken))
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	for _, team := range o.teams {
-		teamCopy := team.Node.Team
+	for _, team := range teams {
+		teamCopy := team.Team
 		teamResource, err := parseIntoTeamResource(ctx, &teamCopy, nil)
 
 		if err != nil {
@@ -47,7 +48,12 @@ func (o *teamBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagina
 		resources = append(resources, teamResource)
 	}
 
-	return resources, nextPageToken, annotation, nil
+	nextPageToken, err := marshalOffsetToken(bag, next)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return resources, nextPageToken, nil, nil
 }
 
 func (o *teamBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
@@ -65,37 +71,95 @@ func (o *teamBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *
 	return entitlements, "", nil, nil
 }
 
-func (o *teamBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+func (o *teamBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
 	var grants []*v2.Grant
 
-	nextPageToken, annotation, err := o.GetTeams(ctx, pToken)
-
+	team, ok, err := o.directory.Team(ctx, resource.Id.Resource)
 	if err != nil {
 		return nil, "", nil, err
 	}
+	if !ok {
+		return nil, "", nil, nil
+	}
+
+	for _, member := range team.Members {
+		memberCopy := member.Node.Member
+		memberRole := member.Node.Role
+
+		userResource, _ := parseIntoUserResource(ctx, &memberCopy, resource.Id)
+		memberGrant := grant.NewGrant(resource, memberRole, userResource, grant.WithAnnotation(&v2.V1Identifier{
+			Id: fmt.Sprintf("team-grant:%s:%s:%s", resource.Id.Resource, memberCopy.ID, memberRole),
+		}))
+		grants = append(grants, memberGrant)
+	}
+
+	return grants, "", nil, nil
+}
+
+// Create creates a new Atlassian team from the given resource. The description and membership
+// settings are read from the group profile, falling back to the resource description.
+func (o *teamBuilder) Create(ctx context.Context, teamResource *v2.Resource) (*v2.Resource, annotations.Annotations, error) {
+	if teamResource.DisplayName == "" {
+		return nil, nil, fmt.Errorf("baton-atlassian: team display name is required")
+	}
 
-	for _, team := range o.teams {
-		if team.Node.Team.ID == resource.Id.Resource {
-			for _, member := range team.Node.Team.Members.Edges {
-				memberCopy := member.Node.Member
-				memberRole := member.Node.Role
+	input := client.TeamInput{
+		DisplayName:        teamResource.DisplayName,
+		Description:        teamResource.Description,
+		MembershipSettings: client.TeamMembershipSettingsOpen,
+	}
 
-				userResource, _ := parseIntoUserResource(ctx, &memberCopy, resource.Id)
-				memberGrant := grant.NewGrant(resource, memberRole, userResource, grant.WithAnnotation(&v2.V1Identifier{
-					Id: fmt.Sprintf("team-grant:%s:%s:%s", resource.Id.Resource, memberCopy.ID, memberRole),
-				}))
-				grants = append(grants, memberGrant)
-			}
+	groupTrait, err := resource.GetGroupTrait(teamResource)
+	if err == nil {
+		if description, ok := resource.GetProfileStringValue(groupTrait.Profile, "description"); ok && description != "" {
+			input.Description = description
 		}
+		if membershipSettings, ok := resource.GetProfileStringValue(groupTrait.Profile, "membership_settings"); ok && membershipSettings != "" {
+			input.MembershipSettings = membershipSettings
+		}
+	}
+
+	switch input.MembershipSettings {
+	case client.TeamMembershipSettingsOpen, client.TeamMembershipSettingsMemberInvite:
+	default:
+		return nil, nil, fmt.Errorf("baton-atlassian: unsupported team membership settings %q", input.MembershipSettings)
 	}
 
-	return grants, nextPageToken, annotation, nil
+	if o.client == nil {
+		return nil, nil, errExportReadOnly
+	}
+
+	team, annotation, err := o.client.CreateTeam(ctx, input)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	ret, err := parseIntoTeamResource(ctx, team, teamResource.ParentResourceId)
+	if err != nil {
+		return nil, annotation, err
+	}
+
+	return ret, annotation, nil
+}
+
+// Delete deletes the Atlassian team referenced by the given resource ID.
+func (o *teamBuilder) Delete(ctx context.Context, resourceId *v2.ResourceId) (annotations.Annotations, error) {
+	if resourceId.ResourceType != teamResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: non-team resource passed to team delete: %s", resourceId.ResourceType)
+	}
+
+	if o.client == nil {
+		return nil, errExportReadOnly
+	}
+
+	return o.client.DeleteTeam(ctx, resourceId.Resource)
 }
 
-func newTeamBuilder(c *client.AtlassianClient) *teamBuilder {
+func newTeamBuilder(c *client.AtlassianClient, d *directory) *teamBuilder {
 	return &teamBuilder{
 		resourceType: teamResourceType,
 		client:       c,
+		directory:    d,
 	}
 }
 
@@ -106,6 +170,10 @@ func parseIntoTeamResource(_ context.Context, team *client.Team, parentResourceI
 		"description":  team.Description,
 	}
 
+	if team.MembershipSettings != "" {
+		profile["membership_settings"] = team.MembershipSettings
+	}
+
 	groupTraits := []resource.GroupTraitOption{
 		resource.WithGroupProfile(profile),
 	}
@@ -125,39 +193,3 @@ func parseIntoTeamResource(_ context.Context, team *client.Team, parentResourceI
 
 	return ret, nil
 }
-
-func (o *teamBuilder) GetTeams(ctx context.Context, pToken *pagination.Token) (string, annotations.Annotations, error) {
-	o.teamsMutex.RLock()
-	defer o.teamsMutex.RUnlock()
-
-	if o.teams != nil || len(o.teams) > 0 {
-		return "", nil, nil
-	}
-
-	bag, pageToken, err := getToken(pToken, userResourceType)
-	if err != nil {
-		return "", nil, err
-	}
-	teams, nextPageToken, _, err := o.client.ListTeams(ctx, client.PageOptions{
-		PageSize:  pToken.Size,
-		PageToken: pageToken,
-	})
-
-	if err != nil {
-		return "", nil, err
-	}
-
-	err = bag.Next(nextPageToken)
-	if err != nil {
-		return "", nil, err
-	}
-
-	o.teams = teams
-
-	nextPageToken, err = bag.Marshal()
-	if err != nil {
-		return "", nil, err
-	}
-
-	return nextPageToken, nil, nil
-}
@@ -3,6 +3,7 @@ package connector
 import (
 	"context"
 	encoding "encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,14 +14,10 @@ import (
 
 	"github.com/conductorone/baton-atlassian/pkg/client"
 	"github.com/conductorone/baton-atlassian/test"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 )
 
-type MemberNode struct {
-	Member client.Member `json:"member"`
-	Role   string        `json:"role"`
-}
-
 func TestMain(m *testing.M) {
 	code := m.Run()
 	os.Exit(code)
@@ -82,33 +79,27 @@ func TestAtlassianClient_GetTeamsAndUsers(t *testing.T) {
 
 		expectedMembers := []client.MemberEdge{
 			{
-				Node: MemberNode(struct {
-					Member client.Member
-					Role   string
-				}{
+				Node: client.MemberEdgeNode{
 					Member: client.Member{
 						ID:        fmt.Sprintf("ari:cloud:identity::user/%s", test.UserIDs[0]),
 						Name:      "User 1",
 						AccountID: test.UserIDs[0],
 					},
 					Role: "REGULAR",
-				}),
+				},
 			},
 		}
 
 		if index == 1 {
 			expectedMembers = append(expectedMembers, client.MemberEdge{
-				Node: MemberNode(struct {
-					Member client.Member
-					Role   string
-				}{
+				Node: client.MemberEdgeNode{
 					Member: client.Member{
 						ID:        fmt.Sprintf("ari:cloud:identity::user/%s", test.UserIDs[1]),
 						Name:      "User 2",
 						AccountID: test.UserIDs[1],
 					},
 					Role: "ADMIN",
-				}),
+				},
 			})
 		}
 		if !reflect.DeepEqual(memberEdges, expectedMembers) {
@@ -146,7 +137,7 @@ func TestAtlassianClient_GetTeamsAndUsers_RequestDetails(t *testing.T) {
 	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
 	userEmailMock := "user@test.com"
 	apiTokenMock := "api-token"
-	testClient := client.NewClient(userEmailMock, apiTokenMock, test.OrganizationID, "", baseHttpClient)
+	testClient := client.NewClient(userEmailMock, apiTokenMock, "", client.Endpoints{}, test.OrganizationID, "", baseHttpClient)
 
 	// Call GetUsers.
 	ctx := context.Background()
@@ -185,6 +176,42 @@ func TestAtlassianClient_GetTeamsAndUsers_RequestDetails(t *testing.T) {
 		}
 	}
 }
+
+// Tests that teams are listed from the configured site, the one teams are created on.
+func TestAtlassianClient_ListTeams_SiteID(t *testing.T) {
+	mockResponseBody, err := ReadFile("Teams.json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var capturedBody client.GraphQLRequest
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&capturedBody); err != nil {
+			return nil, err
+		}
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(mockResponseBody)),
+		}
+		resp.Header.Set("Content-Type", "application/json")
+		return resp, nil
+	})
+
+	baseHttpClient := uhttp.NewBaseHttpClient(&http.Client{Transport: mockTransport})
+	atlassianClient := client.NewClient("", "", "", client.Endpoints{}, test.OrganizationID, "siteTest", baseHttpClient)
+
+	if _, _, _, err := atlassianClient.ListTeams(context.Background(), client.PageOptions{PageSize: 5}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	variables, _ := capturedBody.Variables.(map[string]interface{})
+	if siteID := variables["siteId"]; siteID != "siteTest" {
+		t.Errorf("Expected teams to be listed from site siteTest, got %v", siteID)
+	}
+}
+
 func ReadFile(fileName string) (string, error) {
 	data, err := os.ReadFile("../../test/mockResponses/" + fileName)
 	if err != nil {
@@ -193,3 +220,64 @@ func ReadFile(fileName string) (string, error) {
 
 	return string(data), nil
 }
+
+func TestTeamBuilder_Create(t *testing.T) {
+	mockResponseBody, err := ReadFile("CreateTeam.json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var capturedBody client.GraphQLRequest
+	mockTransport := &test.MockRoundTripper{}
+	mockTransport.SetRoundTrip(func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&capturedBody); err != nil {
+			return nil, err
+		}
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(mockResponseBody)),
+		}
+		resp.Header.Set("Content-Type", "application/json")
+		return resp, nil
+	})
+
+	baseHttpClient := uhttp.NewBaseHttpClient(&http.Client{Transport: mockTransport})
+	atlassianClient := client.NewClient("", "", "", client.Endpoints{}, test.OrganizationID, "", baseHttpClient)
+	builder := newTeamBuilder(atlassianClient, newDirectory(atlassianClient, defaultDirectoryMemoryLimit))
+
+	teamResource, err := resource.NewGroupResource(
+		"Team 3",
+		teamResourceType,
+		"new-team",
+		[]resource.GroupTraitOption{
+			resource.WithGroupProfile(map[string]interface{}{
+				"description":         "Provisioned team",
+				"membership_settings": client.TeamMembershipSettingsMemberInvite,
+			}),
+		},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	created, _, err := builder.Create(context.Background(), teamResource)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedVariables := map[string]interface{}{
+		"organizationId":     test.OrganizationID,
+		"siteId":             "None",
+		"displayName":        "Team 3",
+		"description":        "Provisioned team",
+		"membershipSettings": client.TeamMembershipSettingsMemberInvite,
+	}
+	if !reflect.DeepEqual(capturedBody.Variables, expectedVariables) {
+		t.Errorf("Unexpected mutation variables: got %+v, want %+v", capturedBody.Variables, expectedVariables)
+	}
+
+	if created.Id.Resource != "ari:cloud:identity::team/teamTest3" || created.DisplayName != "Team 3" {
+		t.Errorf("Unexpected created team: %+v", created)
+	}
+}
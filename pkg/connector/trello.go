@@ -0,0 +1,36 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// NewTrello returns a connector that syncs a Trello Enterprise through the Trello REST API at baseURL, or
+// client.DefaultTrelloURL if it is empty, instead of Atlassian cloud.
+func NewTrello(ctx context.Context, baseURL, enterpriseID, apiKey, token string) (*Connector, error) {
+	l := ctxzap.Extract(ctx)
+
+	trelloClient, err := client.NewTrelloClient(ctx, baseURL, enterpriseID, apiKey, token)
+	if err != nil {
+		l.Error("error creating Trello client", zap.Error(err))
+		return nil, err
+	}
+
+	return &Connector{
+		trello: trelloClient,
+	}, nil
+}
+
+// trelloSyncers returns the syncers of a Trello Enterprise.
+func (d *Connector) trelloSyncers(_ context.Context) []connectorbuilder.ResourceSyncer {
+	return []connectorbuilder.ResourceSyncer{
+		newTrelloUserBuilder(d.trello),
+		newTrelloEnterpriseBuilder(d.trello),
+		newTrelloWorkspaceBuilder(d.trello),
+		newTrelloBoardBuilder(d.trello),
+	}
+}
@@ -0,0 +1,86 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// trelloBoardMemberTypes are the membership types of board members, each reported as an entitlement of the
+// board.
+var trelloBoardMemberTypes = []string{client.TrelloMemberTypeAdmin, client.TrelloMemberTypeNormal, client.TrelloMemberTypeObserver}
+
+type trelloBoardBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.TrelloClient
+}
+
+func (o *trelloBoardBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return boardResourceType
+}
+
+// List returns every board, including closed boards, of the parent workspace in a single page.
+func (o *trelloBoardBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	boards, annotation, err := o.client.ListWorkspaceBoards(ctx, parentResourceID.Resource)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, board := range boards {
+		boardCopy := board
+		boardResource, err := parseIntoTrelloBoardResource(ctx, &boardCopy, parentResourceID)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, boardResource)
+	}
+
+	return resources, "", annotation, nil
+}
+
+// Entitlements returns an entitlement for every board membership type.
+func (o *trelloBoardBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return trelloMembershipEntitlements(resource, trelloBoardMemberTypes), "", nil, nil
+}
+
+// Grants returns a grant of its membership type to every member of the board.
+func (o *trelloBoardBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	memberships, annotation, err := o.client.ListBoardMemberships(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return trelloMembershipGrants(resource, memberships), "", annotation, nil
+}
+
+func newTrelloBoardBuilder(c *client.TrelloClient) *trelloBoardBuilder {
+	return &trelloBoardBuilder{
+		resourceType: boardResourceType,
+		client:       c,
+	}
+}
+
+func parseIntoTrelloBoardResource(_ context.Context, board *client.TrelloBoard, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	ret, err := resource.NewResource(
+		board.Name,
+		boardResourceType,
+		board.ID,
+		resource.WithParentResourceID(parentResourceID),
+		resource.WithDescription(board.Desc),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,96 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const enterpriseLicensedEntitlement = "licensed"
+
+// trelloEnterpriseBuilder reports the Trello Enterprise itself, whose licensed entitlement is held by the members
+// consuming a license.
+type trelloEnterpriseBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.TrelloClient
+}
+
+func (o *trelloEnterpriseBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return enterpriseResourceType
+}
+
+// List returns the enterprise the client syncs.
+func (o *trelloEnterpriseBuilder) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	enterpriseResource, err := resource.NewResource(
+		"Trello Enterprise",
+		enterpriseResourceType,
+		o.client.EnterpriseID(),
+	)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return []*v2.Resource{enterpriseResource}, "", nil, nil
+}
+
+func (o *trelloEnterpriseBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(resource, enterpriseLicensedEntitlement,
+			entitlement.WithGrantableTo(userResourceType),
+			entitlement.WithDescription(fmt.Sprintf("Holds a license of the %s", resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("%s License", resource.DisplayName)),
+		),
+	}, "", nil, nil
+}
+
+// Grants returns a grant to every licensed member of the enterprise.
+func (o *trelloEnterpriseBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var grants []*v2.Grant
+
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	members, nextPageToken, annotation, err := o.client.ListLicensedEnterpriseMembers(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, member := range members {
+		principalID := &v2.ResourceId{
+			ResourceType: userResourceType.Id,
+			Resource:     member.ID,
+		}
+		grants = append(grants, grant.NewGrant(resource, enterpriseLicensedEntitlement, principalID))
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return grants, nextPageToken, annotation, nil
+}
+
+func newTrelloEnterpriseBuilder(c *client.TrelloClient) *trelloEnterpriseBuilder {
+	return &trelloEnterpriseBuilder{
+		resourceType: enterpriseResourceType,
+		client:       c,
+	}
+}
@@ -0,0 +1,222 @@
+package connector
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// Tests that NewTrello syncs the Trello Enterprise at the base URL, authenticated with the API key and token,
+// with deactivated members disabled.
+func TestNewTrello(t *testing.T) {
+	server, transport := test.NewFixtureServer(t, test.TrelloFixtures)
+	ctx := context.Background()
+
+	c, err := NewTrello(ctx, server.URL, "e-1", "key", "token")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	syncers := c.ResourceSyncers(ctx)
+	resourceTypes := make([]string, 0, len(syncers))
+	for _, syncer := range syncers {
+		resourceTypes = append(resourceTypes, syncer.ResourceType(ctx).Id)
+	}
+	expected := []string{userResourceType.Id, enterpriseResourceType.Id, workspaceResourceType.Id, boardResourceType.Id}
+	if !slices.Equal(resourceTypes, expected) {
+		t.Fatalf("Expected resource types %v, got %v", expected, resourceTypes)
+	}
+
+	users, _ := listAll(t, syncers[0])
+	if len(users) != 3 {
+		t.Fatalf("Expected 3 users, got %d", len(users))
+	}
+	userTrait, err := resource.GetUserTrait(users[2])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if userTrait.Status.Status != v2.UserTrait_Status_STATUS_DISABLED {
+		t.Errorf("Expected bob to be deactivated, got %s", userTrait.Status.Status)
+	}
+
+	if got := transport.Requests("GET /1/enterprises/e-1/members")[0].Header.Get("Authorization"); got != `OAuth oauth_consumer_key="key", oauth_token="token"` {
+		t.Errorf("Expected requests to be authenticated with the API key and token, got %q", got)
+	}
+}
+
+// Tests that members are listed page after page from index 1 until Trello returns a short page.
+func TestTrelloUserBuilder_ListsEveryPage(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(map[string]string{
+		"GET /1/enterprises/e-1/members?startIndex=1&count=2": "TrelloMembersFirstPage.json",
+		"GET /1/enterprises/e-1/members?startIndex=3&count=2": "TrelloMembersLastPage.json",
+	})
+	builder := newTrelloUserBuilder(test.NewFixtureTrelloClient(transport))
+
+	var ids []string
+	pToken := &pagination.Token{Size: 2}
+	for pages := 1; ; pages++ {
+		users, next, _, err := builder.List(context.Background(), nil, pToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, user := range users {
+			ids = append(ids, user.Id.Resource)
+		}
+		if next == "" {
+			if pages != 2 {
+				t.Errorf("Expected 2 pages, got %d", pages)
+			}
+			break
+		}
+		pToken = &pagination.Token{Size: 2, Token: next}
+	}
+
+	if !slices.Equal(ids, []string{"m-1", "m-2", "m-3"}) {
+		t.Errorf("Expected the 3 members in order, got %v", ids)
+	}
+}
+
+// Tests that licenses, active workspace memberships and board memberships are granted to the members holding
+// them.
+func TestTrelloSyncers_Grants(t *testing.T) {
+	ctx := context.Background()
+	syncers := (&Connector{trello: test.NewFixtureTrelloClient(test.NewFixtureRoundTripper(test.TrelloFixtures))}).ResourceSyncers(ctx)
+
+	_, licenseGrants := listAll(t, syncers[1])
+	if len(licenseGrants) != 2 {
+		t.Fatalf("Expected 2 licensed members, got %v", licenseGrants)
+	}
+
+	workspaces, workspaceGrants := listAll(t, syncers[2])
+	if len(workspaceGrants) != 2 || workspaceGrants[0].Entitlement.Id != "workspace:w-1:admin" {
+		t.Fatalf("Expected the active admin and normal workspace members, got %v", workspaceGrants)
+	}
+
+	boards, _, _, err := syncers[3].List(ctx, workspaces[0].Id, &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(boards) != 1 || boards[0].ParentResourceId.Resource != "w-1" {
+		t.Fatalf("Expected the Roadmap board of the workspace, got %v", boards)
+	}
+	boardGrants, _, _, err := syncers[3].Grants(ctx, boards[0], &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(boardGrants) != 2 || boardGrants[1].Entitlement.Id != "board:b-1:observer" {
+		t.Errorf("Expected an admin and an observer, got %v", boardGrants)
+	}
+}
+
+// Tests that workspace membership is granted with the type of the entitlement, that normal members are made
+// admins and removed, and that deleting a user deactivates it in the enterprise.
+func TestTrelloProvisioning(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(test.TrelloFixtures)
+	ctx := context.Background()
+	trelloClient := test.NewFixtureTrelloClient(transport)
+	builder := newTrelloWorkspaceBuilder(trelloClient)
+
+	workspace, err := parseIntoTrelloWorkspaceResource(ctx, &client.TrelloWorkspace{ID: "w-1", Name: "engineering"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	alice, err := parseIntoTrelloUserResource(ctx, &client.TrelloMember{ID: "m-2", Username: "alice"}, false, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	carol, err := parseIntoTrelloUserResource(ctx, &client.TrelloMember{ID: "m-4", Username: "carol"}, false, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	entitlements, _, _, err := builder.Entitlements(ctx, workspace, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := builder.Grant(ctx, carol, entitlements[1]); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if added := transport.Requests("PUT /1/organizations/w-1/members/m-4?type=normal"); len(added) != 1 {
+		t.Fatalf("Expected carol to be added as a normal member once, got %d requests", len(added))
+	}
+	if _, err := builder.Grant(ctx, alice, entitlements[0]); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if promoted := transport.Requests("PUT /1/organizations/w-1/members/m-2?type=admin"); len(promoted) != 1 {
+		t.Fatalf("Expected alice to be made an admin once, got %d requests", len(promoted))
+	}
+
+	revoke := &v2.Grant{Entitlement: entitlements[1], Principal: alice}
+	if _, err := builder.Revoke(ctx, revoke); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if removed := transport.Requests("DELETE /1/organizations/w-1/members/m-2"); len(removed) != 1 {
+		t.Fatalf("Expected alice to be removed once, got %d requests", len(removed))
+	}
+	transport.SetRoute("DELETE /1/organizations/w-1/members/m-2", "404")
+	annos, err := builder.Revoke(ctx, revoke)
+	if err != nil || !annos.Contains(&v2.GrantAlreadyRevoked{}) {
+		t.Fatalf("Expected a member removed concurrently to be already revoked, got %v and %v", annos, err)
+	}
+
+	// Deletions only reach builders that are resource managers.
+	var userManager connectorbuilder.ResourceManager = newTrelloUserBuilder(trelloClient)
+	if _, err := userManager.Delete(ctx, carol.Id); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if deactivated := transport.Requests("PUT /1/enterprises/e-1/members/m-4/deactivated"); len(deactivated) != 1 {
+		t.Errorf("Expected carol to be deactivated once, got %d requests", len(deactivated))
+	}
+}
+
+// Tests that granting and revoking workspace membership leaves the admin membership of a member in place, and
+// that deactivated memberships are already revoked.
+func TestTrelloWorkspaceBuilder_KeepsHeldMembership(t *testing.T) {
+	transport := test.NewFixtureRoundTripper(test.TrelloFixtures)
+	ctx := context.Background()
+	builder := newTrelloWorkspaceBuilder(test.NewFixtureTrelloClient(transport))
+
+	workspace, err := parseIntoTrelloWorkspaceResource(ctx, &client.TrelloWorkspace{ID: "w-1", Name: "engineering"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	admin, err := parseIntoTrelloUserResource(ctx, &client.TrelloMember{ID: "m-1", Username: "admin"}, false, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	bob, err := parseIntoTrelloUserResource(ctx, &client.TrelloMember{ID: "m-3", Username: "bob"}, true, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	entitlements, _, _, err := builder.Entitlements(ctx, workspace, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := builder.Grant(ctx, admin, entitlements[1]); err == nil {
+		t.Fatalf("Expected granting normal membership to a workspace admin to fail")
+	}
+	annos, err := builder.Grant(ctx, admin, entitlements[0])
+	if err != nil || !annos.Contains(&v2.GrantAlreadyExists{}) {
+		t.Fatalf("Expected the held admin membership to be already granted, got %v", err)
+	}
+	annos, err = builder.Revoke(ctx, &v2.Grant{Entitlement: entitlements[1], Principal: admin})
+	if err != nil || !annos.Contains(&v2.GrantAlreadyRevoked{}) {
+		t.Fatalf("Expected a stale normal membership grant to be already revoked, got %v", err)
+	}
+	annos, err = builder.Revoke(ctx, &v2.Grant{Entitlement: entitlements[1], Principal: bob})
+	if err != nil || !annos.Contains(&v2.GrantAlreadyRevoked{}) {
+		t.Fatalf("Expected a deactivated membership to be already revoked, got %v", err)
+	}
+	if changes := len(transport.Requests("PUT /1/organizations/w-1/members/m-1")) + len(transport.Requests("DELETE /1/organizations/w-1/members/m-1")) +
+		len(transport.Requests("DELETE /1/organizations/w-1/members/m-3")); changes != 0 {
+		t.Fatalf("Expected the memberships to be unchanged, got %d membership changes", changes)
+	}
+}
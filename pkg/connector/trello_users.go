@@ -0,0 +1,144 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type trelloUserBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.TrelloClient
+}
+
+func (o *trelloUserBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return userResourceType
+}
+
+// List returns the members of the Trello Enterprise, including deactivated members.
+func (o *trelloUserBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	members, nextPageToken, annotation, err := o.client.ListEnterpriseMembers(ctx, client.PageOptions{
+		PageSize:  pToken.Size,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, member := range members {
+		memberCopy := member
+		userResource, err := parseIntoTrelloUserResource(ctx, &memberCopy, o.client.Deactivated(&memberCopy), nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, userResource)
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return resources, nextPageToken, annotation, nil
+}
+
+// Entitlements always returns an empty slice for users.
+func (o *trelloUserBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for users since they don't have any entitlements.
+func (o *trelloUserBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Create always fails: Trello members sign up themselves. It is implemented so that the SDK routes deletions to Delete.
+func (o *trelloUserBuilder) Create(_ context.Context, _ *v2.Resource) (*v2.Resource, annotations.Annotations, error) {
+	return nil, nil, status.Error(codes.Unimplemented, "baton-atlassian: users cannot be created")
+}
+
+// Delete deactivates the member in the enterprise, which frees its license and removes its access to the
+// enterprise's workspaces and boards. The member is reported as disabled by the next sync.
+func (o *trelloUserBuilder) Delete(ctx context.Context, resourceId *v2.ResourceId) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	annotation, err := o.client.DeactivateEnterpriseMember(ctx, resourceId.Resource)
+	if err != nil {
+		l.Error("failed to deactivate enterprise member", zap.String("member_id", resourceId.Resource), zap.Error(err))
+		return annotation, err
+	}
+
+	return annotation, nil
+}
+
+func newTrelloUserBuilder(c *client.TrelloClient) *trelloUserBuilder {
+	return &trelloUserBuilder{
+		resourceType: userResourceType,
+		client:       c,
+	}
+}
+
+// parseIntoTrelloUserResource returns the member with its Trello member ID, which workspace and board
+// memberships refer to.
+func parseIntoTrelloUserResource(_ context.Context, member *client.TrelloMember, deactivated bool, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	userStatus := v2.UserTrait_Status_STATUS_ENABLED
+	if deactivated {
+		userStatus = v2.UserTrait_Status_STATUS_DISABLED
+	}
+
+	profile := map[string]interface{}{
+		"user_id":            member.ID,
+		"username":           member.Username,
+		"full_name":          member.FullName,
+		"email":              member.Email,
+		"deactivated":        deactivated,
+		"date_last_accessed": member.DateLastAccessed,
+	}
+
+	userTraits := []resource.UserTraitOption{
+		resource.WithUserProfile(profile),
+		resource.WithStatus(userStatus),
+		resource.WithUserLogin(member.Username),
+	}
+	if member.Email != "" {
+		userTraits = append(userTraits, resource.WithEmail(member.Email, true))
+	}
+
+	displayName := member.FullName
+	if displayName == "" {
+		displayName = member.Username
+	}
+
+	ret, err := resource.NewUserResource(
+		displayName,
+		userResourceType,
+		member.ID,
+		userTraits,
+		resource.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,213 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// trelloWorkspaceMemberTypes are the membership types of workspace members, each reported as an entitlement of
+// the workspace.
+var trelloWorkspaceMemberTypes = []string{client.TrelloMemberTypeAdmin, client.TrelloMemberTypeNormal}
+
+type trelloWorkspaceBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.TrelloClient
+}
+
+func (o *trelloWorkspaceBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return workspaceResourceType
+}
+
+// List returns every workspace of the enterprise in a single page.
+func (o *trelloWorkspaceBuilder) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var resources []*v2.Resource
+
+	workspaces, annotation, err := o.client.ListWorkspaces(ctx)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	for _, workspace := range workspaces {
+		workspaceCopy := workspace
+		workspaceResource, err := parseIntoTrelloWorkspaceResource(ctx, &workspaceCopy, nil)
+		if err != nil {
+			return nil, "", annotation, err
+		}
+		resources = append(resources, workspaceResource)
+	}
+
+	return resources, "", annotation, nil
+}
+
+// Entitlements returns an entitlement for every workspace membership type.
+func (o *trelloWorkspaceBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return trelloMembershipEntitlements(resource, trelloWorkspaceMemberTypes), "", nil, nil
+}
+
+// Grants returns a grant of its membership type to every member of the workspace.
+func (o *trelloWorkspaceBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	memberships, annotation, err := o.client.ListWorkspaceMemberships(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", annotation, err
+	}
+
+	return trelloMembershipGrants(resource, memberships), "", annotation, nil
+}
+
+// Grant adds the member to the workspace with the membership type of the entitlement, or makes a normal member
+// an admin. Members have a single membership type per workspace, so granting normal membership to an admin is
+// refused rather than downgrading the member.
+func (o *trelloWorkspaceBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if principal.Id.ResourceType != userResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: only users can be granted workspace membership")
+	}
+
+	workspaceID := entitlement.Resource.Id.Resource
+	memberID := principal.Id.Resource
+
+	memberships, annotation, err := o.client.ListWorkspaceMemberships(ctx, workspaceID)
+	if err != nil {
+		return annotation, err
+	}
+
+	held := trelloHeldMemberType(memberships, memberID)
+	if held == entitlement.Slug {
+		annotation.Append(&v2.GrantAlreadyExists{})
+		return annotation, nil
+	}
+	if held == client.TrelloMemberTypeAdmin {
+		return annotation, fmt.Errorf("baton-atlassian: %s is an admin of workspace %s, revoke it before granting %s", memberID, workspaceID, entitlement.Slug)
+	}
+
+	annotation, err = o.client.SetWorkspaceMember(ctx, workspaceID, memberID, entitlement.Slug)
+	if err != nil {
+		l.Error("failed to add workspace member", zap.String("workspace_id", workspaceID), zap.String("member_id", memberID), zap.Error(err))
+		return annotation, err
+	}
+
+	return annotation, nil
+}
+
+// Revoke removes the member from the workspace. Members have a single membership type per workspace, so they
+// are only removed while it is the type of the grant.
+func (o *trelloWorkspaceBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	principal := grant.Principal
+	if principal.Id.ResourceType != userResourceType.Id {
+		return nil, fmt.Errorf("baton-atlassian: only users can have workspace membership revoked")
+	}
+
+	workspaceID := grant.Entitlement.Resource.Id.Resource
+	memberID := principal.Id.Resource
+
+	memberships, annotation, err := o.client.ListWorkspaceMemberships(ctx, workspaceID)
+	if err != nil {
+		return annotation, err
+	}
+	if trelloHeldMemberType(memberships, memberID) != grant.Entitlement.Slug {
+		annotation.Append(&v2.GrantAlreadyRevoked{})
+		return annotation, nil
+	}
+
+	annotation, err = o.client.RemoveWorkspaceMember(ctx, workspaceID, memberID)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			annotation.Append(&v2.GrantAlreadyRevoked{})
+			return annotation, nil
+		}
+		l.Error("failed to remove workspace member", zap.String("workspace_id", workspaceID), zap.String("member_id", memberID), zap.Error(err))
+		return annotation, err
+	}
+
+	return annotation, nil
+}
+
+func newTrelloWorkspaceBuilder(c *client.TrelloClient) *trelloWorkspaceBuilder {
+	return &trelloWorkspaceBuilder{
+		resourceType: workspaceResourceType,
+		client:       c,
+	}
+}
+
+// parseIntoTrelloWorkspaceResource returns the workspace, whose boards are listed as its children.
+func parseIntoTrelloWorkspaceResource(_ context.Context, workspace *client.TrelloWorkspace, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	displayName := workspace.DisplayName
+	if displayName == "" {
+		displayName = workspace.Name
+	}
+
+	ret, err := resource.NewResource(
+		displayName,
+		workspaceResourceType,
+		workspace.ID,
+		resource.WithParentResourceID(parentResourceID),
+		resource.WithDescription(workspace.Desc),
+		resource.WithAnnotation(&v2.ChildResourceType{ResourceTypeId: boardResourceType.Id}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// trelloMembershipEntitlements returns a permission entitlement for every membership type.
+func trelloMembershipEntitlements(resource *v2.Resource, memberTypes []string) []*v2.Entitlement {
+	entitlements := make([]*v2.Entitlement, 0, len(memberTypes))
+
+	for _, memberType := range memberTypes {
+		entitlements = append(entitlements, entitlement.NewPermissionEntitlement(resource, memberType,
+			entitlement.WithGrantableTo(userResourceType),
+			entitlement.WithDescription(fmt.Sprintf("%s member of %s", memberType, resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("%s %s", resource.DisplayName, memberType)),
+		))
+	}
+
+	return entitlements
+}
+
+// trelloHeldMemberType returns the membership type of the member, or an empty string when it is not a member or
+// its membership is deactivated, as trelloMembershipGrants reports no grant for it.
+func trelloHeldMemberType(memberships []client.TrelloMembership, memberID string) string {
+	for _, membership := range memberships {
+		if membership.IDMember == memberID && !membership.Deactivated {
+			return membership.MemberType
+		}
+	}
+
+	return ""
+}
+
+// trelloMembershipGrants returns a grant of its membership type to every member. Deactivated memberships no
+// longer give access and are skipped.
+func trelloMembershipGrants(resource *v2.Resource, memberships []client.TrelloMembership) []*v2.Grant {
+	var grants []*v2.Grant
+
+	for _, membership := range memberships {
+		if membership.Deactivated {
+			continue
+		}
+		principalID := &v2.ResourceId{
+			ResourceType: userResourceType.Id,
+			Resource:     membership.IDMember,
+		}
+		grants = append(grants, grant.NewGrant(resource, membership.MemberType, principalID))
+	}
+
+	return grants
+}
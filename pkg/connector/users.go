@@ -12,7 +12,9 @@ import (
 
 type userBuilder struct {
 	resourceType *v2.ResourceType
-	client       *client.AtlassianClient
+	directory    *directory
+	authPolicies *authPolicyMembership
+	children     []*v2.ResourceType
 }
 
 func (o *userBuilder) ResourceType(_ context.Context) *v2.ResourceType {
@@ -24,37 +26,45 @@ func (o *userBuilder) ResourceType(_ context.Context) *v2.ResourceType {
 func (o *userBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
 	var resources []*v2.Resource
 
-	bag, pageToken, err := getToken(pToken, userResourceType)
+	bag, offset, err := getOffsetToken(pToken, userResourceType)
 	if err != nil {
 		return nil, "", nil, err
 	}
-	teams, nextPageToken, _, err := o.client.ListTeams(ctx, client.PageOptions{
-		PageSize:  pToken.Size,
-		PageToken: pageToken,
-	})
 
+	users, next, err := o.directory.Users(ctx, offset, getPageSize(pToken))
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	err = bag.Next(nextPageToken)
-	if err != nil {
-		return nil, "", nil, err
-	}
+	for _, user := range users {
+		userCopy := user
 
-	for _, team := range teams {
-		for _, member := range team.Node.Team.Members.Edges {
-			memberCopy := member.Node.Member
-			userResource, err := parseIntoUserResource(ctx, &memberCopy, nil)
-			if err != nil {
-				return nil, "", nil, err
-			}
+		policy, ok := o.authPolicies.Policy(ctx, user.AccountID)
+		var userTraits []resource.UserTraitOption
+		if ok {
+			userTraits = authPolicyUserTraits(policy)
+		}
+		if user.Status != v2.UserTrait_Status_STATUS_UNSPECIFIED {
+			userTraits = append(userTraits, resource.WithStatus(user.Status))
+		}
+		if !user.LastLogin.IsZero() {
+			userTraits = append(userTraits, resource.WithLastLogin(user.LastLogin))
+		}
 
-			resources = append(resources, userResource)
+		userResource, err := parseIntoUserResource(ctx, &userCopy.Member, nil, userTraits...)
+		if err != nil {
+			return nil, "", nil, err
 		}
+		annos := annotations.Annotations(userResource.Annotations)
+		for _, child := range o.children {
+			annos.Append(&v2.ChildResourceType{ResourceTypeId: child.Id})
+		}
+		userResource.Annotations = annos
+
+		resources = append(resources, userResource)
 	}
 
-	nextPageToken, err = bag.Marshal()
+	nextPageToken, err := marshalOffsetToken(bag, next)
 	if err != nil {
 		return nil, "", nil, err
 	}
@@ -72,7 +82,9 @@ func (o *userBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.To
 	return nil, "", nil, nil
 }
 
-func parseIntoUserResource(_ context.Context, user *client.Member, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+// parseIntoUserResource returns the user with its ARI as ID, with any extra traits, such as the SSO and MFA
+// status from its authentication policy.
+func parseIntoUserResource(_ context.Context, user *client.Member, parentResourceID *v2.ResourceId, extraTraits ...resource.UserTraitOption) (*v2.Resource, error) {
 	var userStatus = v2.UserTrait_Status_STATUS_ENABLED
 
 	profile := map[string]interface{}{
@@ -86,6 +98,7 @@ func parseIntoUserResource(_ context.Context, user *client.Member, parentResourc
 		resource.WithStatus(userStatus),
 		resource.WithUserLogin(user.Name),
 	}
+	userTraits = append(userTraits, extraTraits...)
 
 	displayName := user.Name
 
@@ -103,9 +116,13 @@ func parseIntoUserResource(_ context.Context, user *client.Member, parentResourc
 	return ret, nil
 }
 
-func newUserBuilder(c *client.AtlassianClient) *userBuilder {
+// newUserBuilder returns a builder for the users of the directory, which have the given child resource types,
+// such as their API tokens.
+func newUserBuilder(d *directory, authPolicies *authPolicyMembership, children ...*v2.ResourceType) *userBuilder {
 	return &userBuilder{
 		resourceType: userResourceType,
-		client:       c,
+		directory:    d,
+		authPolicies: authPolicies,
+		children:     children,
 	}
 }
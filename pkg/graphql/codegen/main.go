@@ -0,0 +1,383 @@
+// Command codegen validates the GraphQL operations of a package against the
+// checked-in schema snapshot and generates typed variables and responses for
+// them.
+//
+// Every named fragment becomes a Go type of the same name, so operations can
+// share types by spreading the same fragment. Other selections are named
+// after the operation and the path to them.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// scalars maps GraphQL scalars to Go types. Enums are generated as strings.
+var scalars = map[string]string{
+	"ID":       "string",
+	"String":   "string",
+	"Int":      "int",
+	"Float":    "float64",
+	"Boolean":  "bool",
+	"URL":      "string",
+	"DateTime": "string",
+}
+
+var initialisms = map[string]string{
+	"api":  "API",
+	"ari":  "ARI",
+	"id":   "ID",
+	"ids":  "IDs",
+	"json": "JSON",
+	"ssh":  "SSH",
+	"url":  "URL",
+	"uuid": "UUID",
+}
+
+func main() {
+	schemaPath := flag.String("schema", "", "path to the GraphQL schema snapshot")
+	documents := flag.String("documents", ".", "directory containing the *.graphql operations")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package name of the generated file")
+	out := flag.String("out", "graphql_gen.go", "path to the generated file")
+	flag.Parse()
+
+	src, err := Generate(*schemaPath, *documents, *pkg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*out, src, 0o600); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Generate validates the operations in documents against the schema and
+// returns the formatted Go source for them.
+func Generate(schemaPath, documents, pkg string) ([]byte, error) {
+	schemaSource, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: schemaPath, Input: string(schemaSource)})
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := loadDocuments(schema, documents)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		schema:    schema,
+		fragments: make(map[string]*ast.FragmentDefinition),
+		declared:  make(map[string]bool),
+	}
+	for _, fragment := range doc.Fragments {
+		g.fragments[fragment.Name] = fragment
+	}
+
+	operations := doc.Operations
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].Name < operations[j].Name
+	})
+	for _, operation := range operations {
+		if err := g.operation(operation); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by github.com/conductorone/baton-atlassian/pkg/graphql/codegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n", pkg)
+	for _, decl := range g.decls {
+		buf.WriteString("\n")
+		buf.WriteString(decl)
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+// loadDocuments parses every *.graphql file in dir into a single document, so
+// operations can spread fragments declared in other files, and validates it.
+func loadDocuments(schema *ast.Schema, dir string) (*ast.QueryDocument, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.graphql"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	doc := &ast.QueryDocument{}
+	for _, path := range paths {
+		input, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		fileDoc, err := parser.ParseQuery(&ast.Source{Name: path, Input: string(input)})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, operation := range fileDoc.Operations {
+			if operation.Name == "" {
+				return nil, fmt.Errorf("%s: operations must be named", path)
+			}
+		}
+		doc.Operations = append(doc.Operations, fileDoc.Operations...)
+		doc.Fragments = append(doc.Fragments, fileDoc.Fragments...)
+	}
+
+	if errs := validator.Validate(schema, doc); len(errs) > 0 {
+		return nil, errs
+	}
+
+	return doc, nil
+}
+
+type generator struct {
+	schema    *ast.Schema
+	fragments map[string]*ast.FragmentDefinition
+	declared  map[string]bool
+	decls     []string
+}
+
+func (g *generator) operation(operation *ast.OperationDefinition) error {
+	name := exportedName(operation.Name)
+	kind := string(operation.Operation)
+
+	var query bytes.Buffer
+	formatter.NewFormatter(&query).FormatQueryDocument(&ast.QueryDocument{
+		Operations: ast.OperationList{operation},
+		Fragments:  g.usedFragments(operation.SelectionSet),
+	})
+
+	document := unexportedName(operation.Name) + "Document"
+	g.decls = append(g.decls, fmt.Sprintf(
+		"// %s is the %s %s, including the fragments it spreads.\nconst %s = `\n%s`\n",
+		document, operation.Name, kind, document, query.String(),
+	))
+
+	variables := make([]string, 0, len(operation.VariableDefinitions))
+	for _, variable := range operation.VariableDefinitions {
+		typ, err := g.inputType(variable.Type)
+		if err != nil {
+			return fmt.Errorf("%s: $%s: %w", operation.Name, variable.Variable, err)
+		}
+		variables = append(variables, structField(variable.Variable, typ, variable.Type.NonNull))
+	}
+	g.decls = append(g.decls, fmt.Sprintf(
+		"// %sVariables are the variables of the %s %s.\ntype %sVariables struct {\n%s\n}\n",
+		name, operation.Name, kind, name, strings.Join(variables, "\n"),
+	))
+
+	doc := fmt.Sprintf("// %sResponse is the data returned by the %s %s.\n", name, operation.Name, kind)
+	if err := g.declareStruct(name+"Response", doc, operation.SelectionSet); err != nil {
+		return fmt.Errorf("%s: %w", operation.Name, err)
+	}
+
+	// Queries only read, so they are retried after failures like GET requests.
+	readOnly := ""
+	if operation.Operation == ast.Query {
+		readOnly = ", readOnly: true"
+	}
+	g.decls = append(g.decls, fmt.Sprintf(
+		"func new%sRequest(variables *%sVariables) *GraphQLRequest {\n"+
+			"return &GraphQLRequest{OperationName: %q, Query: %s, Variables: variables%s}\n}\n",
+		name, name, operation.Name, document, readOnly,
+	))
+
+	return nil
+}
+
+// declareStruct declares a struct type for a selection set, followed by the
+// types of its fields.
+func (g *generator) declareStruct(name, doc string, selections ast.SelectionSet) error {
+	if g.declared[name] {
+		return nil
+	}
+	g.declared[name] = true
+
+	index := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	var fields []string
+	for _, selection := range selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			typ, err := g.outputType(name+exportedName(s.Alias), s.Definition.Type, s.SelectionSet)
+			if err != nil {
+				return fmt.Errorf("%s: %w", s.Alias, err)
+			}
+			fields = append(fields, structField(s.Alias, typ, true))
+		case *ast.FragmentSpread:
+			if err := g.declareFragment(s.Name); err != nil {
+				return err
+			}
+			fields = append(fields, s.Name)
+		case *ast.InlineFragment:
+			return fmt.Errorf("%s: inline fragments are not supported, use a named fragment", name)
+		}
+	}
+
+	g.decls[index] = fmt.Sprintf("%stype %s struct {\n%s\n}\n", doc, name, strings.Join(fields, "\n"))
+	return nil
+}
+
+func (g *generator) declareFragment(name string) error {
+	fragment := g.fragments[name]
+	doc := fmt.Sprintf("// %s is the %s fragment on %s.\n", name, name, fragment.TypeCondition)
+	return g.declareStruct(name, doc, fragment.SelectionSet)
+}
+
+// outputType returns the Go type of a selected field. A selection made of a
+// single fragment spread uses the fragment's type.
+func (g *generator) outputType(name string, t *ast.Type, selections ast.SelectionSet) (string, error) {
+	if t.Elem != nil {
+		elem, err := g.outputType(name, t.Elem, selections)
+		return "[]" + elem, err
+	}
+
+	if len(selections) == 0 {
+		return g.leafType(t.NamedType)
+	}
+
+	if spread, ok := selections[0].(*ast.FragmentSpread); ok && len(selections) == 1 {
+		return spread.Name, g.declareFragment(spread.Name)
+	}
+
+	return name, g.declareStruct(name, "", selections)
+}
+
+func (g *generator) inputType(t *ast.Type) (string, error) {
+	if t.Elem != nil {
+		elem, err := g.inputType(t.Elem)
+		return "[]" + elem, err
+	}
+
+	def := g.schema.Types[t.NamedType]
+	if def == nil || def.Kind != ast.InputObject {
+		return g.leafType(t.NamedType)
+	}
+
+	if !g.declared[def.Name] {
+		g.declared[def.Name] = true
+		index := len(g.decls)
+		g.decls = append(g.decls, "")
+
+		fields := make([]string, 0, len(def.Fields))
+		for _, field := range def.Fields {
+			typ, err := g.inputType(field.Type)
+			if err != nil {
+				return "", fmt.Errorf("%s.%s: %w", def.Name, field.Name, err)
+			}
+			fields = append(fields, structField(field.Name, typ, field.Type.NonNull))
+		}
+		g.decls[index] = fmt.Sprintf("// %s is the %s input type.\ntype %s struct {\n%s\n}\n",
+			def.Name, def.Name, def.Name, strings.Join(fields, "\n"))
+	}
+
+	return def.Name, nil
+}
+
+func (g *generator) leafType(name string) (string, error) {
+	def := g.schema.Types[name]
+	if def != nil && def.Kind == ast.Enum {
+		return "string", nil
+	}
+
+	typ, ok := scalars[name]
+	if !ok {
+		return "", fmt.Errorf("no Go type for scalar %s", name)
+	}
+	return typ, nil
+}
+
+// usedFragments returns the fragments spread by a selection set, directly or
+// through other fragments, sorted by name.
+func (g *generator) usedFragments(selections ast.SelectionSet) ast.FragmentDefinitionList {
+	seen := make(map[string]bool)
+	var walk func(ast.SelectionSet)
+	walk = func(selections ast.SelectionSet) {
+		for _, selection := range selections {
+			switch s := selection.(type) {
+			case *ast.Field:
+				walk(s.SelectionSet)
+			case *ast.InlineFragment:
+				walk(s.SelectionSet)
+			case *ast.FragmentSpread:
+				if !seen[s.Name] {
+					seen[s.Name] = true
+					walk(g.fragments[s.Name].SelectionSet)
+				}
+			}
+		}
+	}
+	walk(selections)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fragments := make(ast.FragmentDefinitionList, 0, len(names))
+	for _, name := range names {
+		fragments = append(fragments, g.fragments[name])
+	}
+	return fragments
+}
+
+// structField declares a field whose JSON name is the GraphQL name. Nullable
+// variables are omitted when empty so the server applies their defaults.
+func structField(graphqlName, typ string, required bool) string {
+	tag := graphqlName
+	if !required {
+		tag += ",omitempty"
+	}
+	return fmt.Sprintf("%s %s `json:%q`", exportedName(graphqlName), typ, tag)
+}
+
+// exportedName converts a camelCase GraphQL name into an exported Go name,
+// spelling initialisms the Go way, e.g. organizationId becomes OrganizationID.
+func exportedName(name string) string {
+	var words []string
+	start := 0
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) && !unicode.IsUpper(rune(name[i-1])) {
+			words = append(words, name[start:i])
+			start = i
+		}
+	}
+	words = append(words, name[start:])
+
+	var b strings.Builder
+	for _, word := range words {
+		if initialism, ok := initialisms[strings.ToLower(word)]; ok {
+			b.WriteString(initialism)
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+func unexportedName(name string) string {
+	return strings.ToLower(name[:1]) + name[1:]
+}
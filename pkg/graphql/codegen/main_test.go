@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const schemaPath = "../schema/schema.graphql"
+
+// Tests that the checked-in client code matches the schema and operations, so
+// forgetting to run `go generate ./pkg/client` fails CI.
+func TestGenerate_ClientIsUpToDate(t *testing.T) {
+	got, err := Generate(schemaPath, "../../client", "client")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want, err := os.ReadFile("../../client/graphql_gen.go")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Error("pkg/client/graphql_gen.go is out of date, run `go generate ./pkg/client`")
+	}
+}
+
+func TestGenerate_RejectsInvalidOperations(t *testing.T) {
+	dir := t.TempDir()
+	query := `query Teams($organizationId: ID!) {
+    team {
+        teamSearchV2(organizationId: $organizationId) {
+            pageInfo {
+                hasMorePages
+            }
+        }
+    }
+}`
+	if err := os.WriteFile(filepath.Join(dir, "Teams.query.graphql"), []byte(query), 0o600); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	_, err := Generate(schemaPath, dir, "client")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if !strings.Contains(err.Error(), "hasMorePages") || !strings.Contains(err.Error(), "siteId") {
+		t.Errorf("Expected the unknown field and missing argument to be reported, got %v", err)
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	cases := map[string]string{
+		"organizationId": "OrganizationID",
+		"teamSearchV2":   "TeamSearchV2",
+		"hostUrl":        "HostURL",
+		"memberIds":      "MemberIDs",
+		"node":           "Node",
+	}
+	for in, want := range cases {
+		if got := exportedName(in); got != want {
+			t.Errorf("exportedName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
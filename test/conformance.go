@@ -0,0 +1,220 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+)
+
+const (
+	// ConformancePageSize is the page size the conformance suite asks for, small enough that listings of
+	// test fixtures span several pages.
+	ConformancePageSize = 1
+
+	// maxConformancePages bounds every listing, so that pagination that does not terminate fails the test
+	// instead of hanging it.
+	maxConformancePages = 10000
+)
+
+// Conformance drives every syncer to exhaustion, as the SDK syncer does, and checks the invariants the SDK
+// relies on:
+//   - pagination terminates, without handing out the same page token twice;
+//   - resource, entitlement and grant IDs are unique;
+//   - parent resource IDs resolve to emitted resources;
+//   - every entitlement is on the resource it was listed for;
+//   - every grant principal is an emitted resource, and every granted or expanded entitlement is emitted.
+//
+// Child resource types announced with a ChildResourceType annotation are listed under each parent.
+func Conformance(t testing.TB, syncers []connectorbuilder.ResourceSyncer) {
+	t.Helper()
+
+	ctx := context.Background()
+	c := &conformance{
+		t:            t,
+		syncers:      make(map[string]connectorbuilder.ResourceSyncer),
+		resources:    make(map[resourceKey]*v2.Resource),
+		entitlements: make(map[string]*v2.Entitlement),
+	}
+
+	for _, syncer := range syncers {
+		resourceType := syncer.ResourceType(ctx)
+		if _, ok := c.syncers[resourceType.Id]; ok {
+			t.Errorf("resource type %s has several syncers", resourceType.Id)
+			continue
+		}
+		c.syncers[resourceType.Id] = syncer
+	}
+
+	for _, syncer := range syncers {
+		c.listResources(ctx, syncer, nil)
+	}
+
+	var grants []*v2.Grant
+	for _, resource := range c.order {
+		syncer := c.syncers[resource.Id.ResourceType]
+		c.listEntitlements(ctx, syncer, resource)
+		grants = append(grants, c.listGrants(ctx, syncer, resource)...)
+	}
+
+	for _, resource := range c.order {
+		if parent := resource.ParentResourceId; parent != nil {
+			if _, ok := c.resources[keyOf(parent)]; !ok {
+				t.Errorf("resource %s has parent %s, which was not emitted", keyOf(resource.Id), keyOf(parent))
+			}
+		}
+	}
+
+	grantIDs := make(map[string]struct{}, len(grants))
+	for _, grant := range grants {
+		if _, ok := grantIDs[grant.Id]; ok {
+			t.Errorf("grant %s was emitted more than once", grant.Id)
+		}
+		grantIDs[grant.Id] = struct{}{}
+
+		if _, ok := c.resources[keyOf(grant.Principal.GetId())]; !ok {
+			t.Errorf("grant %s has principal %s, which was not emitted", grant.Id, keyOf(grant.Principal.GetId()))
+		}
+		if _, ok := c.entitlements[grant.Entitlement.GetId()]; !ok {
+			t.Errorf("grant %s is for entitlement %s, which was not emitted", grant.Id, grant.Entitlement.GetId())
+		}
+
+		annos := annotations.Annotations(grant.Annotations)
+		expandable := &v2.GrantExpandable{}
+		ok, err := annos.Pick(expandable)
+		if err != nil {
+			t.Errorf("grant %s has an invalid expandable annotation: %v", grant.Id, err)
+		}
+		if !ok {
+			continue
+		}
+		for _, entitlementID := range expandable.EntitlementIds {
+			if _, ok := c.entitlements[entitlementID]; !ok {
+				t.Errorf("grant %s expands entitlement %s, which was not emitted", grant.Id, entitlementID)
+			}
+		}
+	}
+}
+
+type resourceKey struct {
+	resourceType string
+	resource     string
+}
+
+func (k resourceKey) String() string {
+	return k.resourceType + ":" + k.resource
+}
+
+func keyOf(id *v2.ResourceId) resourceKey {
+	return resourceKey{resourceType: id.GetResourceType(), resource: id.GetResource()}
+}
+
+type conformance struct {
+	t            testing.TB
+	syncers      map[string]connectorbuilder.ResourceSyncer
+	resources    map[resourceKey]*v2.Resource
+	order        []*v2.Resource
+	entitlements map[string]*v2.Entitlement
+}
+
+func (c *conformance) listResources(ctx context.Context, syncer connectorbuilder.ResourceSyncer, parent *v2.ResourceId) {
+	resourceType := syncer.ResourceType(ctx).Id
+
+	var resources []*v2.Resource
+	paginate(c.t, "resources of "+resourceType, func(token *pagination.Token) (string, error) {
+		page, next, _, err := syncer.List(ctx, parent, token)
+		resources = append(resources, page...)
+		return next, err
+	})
+
+	for _, resource := range resources {
+		key := keyOf(resource.Id)
+		if key.resourceType != resourceType {
+			c.t.Errorf("syncer for %s emitted resource %s", resourceType, key)
+			continue
+		}
+		if _, ok := c.resources[key]; ok {
+			c.t.Errorf("resource %s was emitted more than once", key)
+			continue
+		}
+		c.resources[key] = resource
+		c.order = append(c.order, resource)
+
+		for _, a := range resource.Annotations {
+			child := &v2.ChildResourceType{}
+			if !a.MessageIs(child) {
+				continue
+			}
+			if err := a.UnmarshalTo(child); err != nil {
+				c.t.Errorf("resource %s has an invalid child resource type annotation: %v", key, err)
+				continue
+			}
+
+			childSyncer, ok := c.syncers[child.ResourceTypeId]
+			if !ok {
+				c.t.Errorf("resource %s has children of type %s, which has no syncer", key, child.ResourceTypeId)
+				continue
+			}
+			c.listResources(ctx, childSyncer, resource.Id)
+		}
+	}
+}
+
+func (c *conformance) listEntitlements(ctx context.Context, syncer connectorbuilder.ResourceSyncer, resource *v2.Resource) {
+	var entitlements []*v2.Entitlement
+	paginate(c.t, "entitlements of "+keyOf(resource.Id).String(), func(token *pagination.Token) (string, error) {
+		page, next, _, err := syncer.Entitlements(ctx, resource, token)
+		entitlements = append(entitlements, page...)
+		return next, err
+	})
+
+	for _, entitlement := range entitlements {
+		if keyOf(entitlement.Resource.GetId()) != keyOf(resource.Id) {
+			c.t.Errorf("entitlement %s listed for %s is on %s", entitlement.Id, keyOf(resource.Id), keyOf(entitlement.Resource.GetId()))
+		}
+		if _, ok := c.entitlements[entitlement.Id]; ok {
+			c.t.Errorf("entitlement %s was emitted more than once", entitlement.Id)
+			continue
+		}
+		c.entitlements[entitlement.Id] = entitlement
+	}
+}
+
+func (c *conformance) listGrants(ctx context.Context, syncer connectorbuilder.ResourceSyncer, resource *v2.Resource) []*v2.Grant {
+	var grants []*v2.Grant
+	paginate(c.t, "grants of "+keyOf(resource.Id).String(), func(token *pagination.Token) (string, error) {
+		page, next, _, err := syncer.Grants(ctx, resource, token)
+		grants = append(grants, page...)
+		return next, err
+	})
+	return grants
+}
+
+// paginate calls list with the token of every page until it returns no next page token.
+func paginate(t testing.TB, what string, list func(token *pagination.Token) (string, error)) {
+	t.Helper()
+
+	seen := make(map[string]struct{})
+	token := &pagination.Token{Size: ConformancePageSize}
+	for range maxConformancePages {
+		next, err := list(token)
+		if err != nil {
+			t.Errorf("listing %s: %v", what, err)
+			return
+		}
+		if next == "" {
+			return
+		}
+		if _, ok := seen[next]; ok {
+			t.Errorf("listing %s: page token %q was returned twice", what, next)
+			return
+		}
+		seen[next] = struct{}{}
+		token = &pagination.Token{Size: ConformancePageSize, Token: next}
+	}
+
+	t.Errorf("listing %s: no last page after %d pages", what, maxConformancePages)
+}
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+var brokenResourceType = &v2.ResourceType{Id: "broken", DisplayName: "Broken"}
+
+// brokenSyncer violates every invariant of the conformance suite.
+type brokenSyncer struct{}
+
+func (brokenSyncer) ResourceType(_ context.Context) *v2.ResourceType {
+	return brokenResourceType
+}
+
+func (brokenSyncer) List(_ context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	missingParent := &v2.ResourceId{ResourceType: brokenResourceType.Id, Resource: "missing"}
+	first, _ := resource.NewResource("first", brokenResourceType, "a", resource.WithParentResourceID(missingParent))
+	duplicate, _ := resource.NewResource("duplicate", brokenResourceType, "a")
+
+	// The same page and token are returned forever.
+	return []*v2.Resource{first, duplicate}, "again", nil, nil
+}
+
+func (brokenSyncer) Entitlements(_ context.Context, r *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return []*v2.Entitlement{entitlement.NewAssignmentEntitlement(r, "member")}, "", nil, nil
+}
+
+func (brokenSyncer) Grants(_ context.Context, r *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	ghost := &v2.ResourceId{ResourceType: brokenResourceType.Id, Resource: "ghost"}
+	return []*v2.Grant{grant.NewGrant(r, "owner", ghost)}, "", nil, nil
+}
+
+// recorder records the errors of a test instead of failing it.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestConformance_ReportsViolations(t *testing.T) {
+	r := &recorder{TB: t}
+	Conformance(r, []connectorbuilder.ResourceSyncer{brokenSyncer{}})
+
+	expected := []string{
+		`page token "again" was returned twice`,
+		"resource broken:a was emitted more than once",
+		"has parent broken:missing, which was not emitted",
+		"has principal broken:ghost, which was not emitted",
+		"is for entitlement broken:a:owner, which was not emitted",
+	}
+	for _, message := range expected {
+		found := false
+		for _, e := range r.errors {
+			if strings.Contains(e, message) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Expected an error containing %q, got %q", message, r.errors)
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package fakeatlassian
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+)
+
+// adminPageSize is the size of Admin API pages when the client sets no limit.
+const adminPageSize = 100
+
+type adminError struct {
+	Message string `json:"message"`
+}
+
+type groupMembershipBody struct {
+	AccountID string `json:"account_id"`
+}
+
+type roleAssignmentBody struct {
+	ResourceID string `json:"resourceId"`
+	RoleID     string `json:"roleId"`
+}
+
+// registerAdmin registers the Admin API routes. Handlers run with s.mtx held, for the fixture organization.
+func (s *Server) registerAdmin(mux *http.ServeMux) {
+	routes := map[string]http.HandlerFunc{
+		"GET /v1/orgs/{org}/groups":                                       s.listGroups,
+		"POST /v1/orgs/{org}/directory/groups":                            s.createGroup,
+		"GET /v1/orgs/{org}/directory/groups/{group}":                     s.getGroup,
+		"DELETE /v1/orgs/{org}/directory/groups/{group}":                  s.deleteGroup,
+		"GET /v1/orgs/{org}/directory/groups/{group}/memberships":         s.listGroupMembers,
+		"POST /v1/orgs/{org}/directory/groups/{group}/memberships":        s.addGroupMember,
+		"DELETE /v1/orgs/{org}/directory/groups/{group}/memberships/{id}": s.removeGroupMember,
+		"GET /v1/orgs/{org}/policies":                                     s.listAuthPolicies,
+		"GET /v1/orgs/{org}/policies/{policy}/users":                      s.listAuthPolicyUsers,
+		"GET /v1/orgs/{org}/api-keys":                                     s.listAPIKeys,
+		"GET /v1/orgs/{org}/events":                                       s.listEvents,
+		"GET /v2/orgs/{org}/workspaces":                                   s.listWorkspaces,
+		"GET /v2/orgs/{org}/workspaces/{workspace}":                       s.getWorkspace,
+		"GET /v2/orgs/{org}/directories/-/users":                          s.listDirectoryUsers,
+		"POST /v2/orgs/{org}/directories/-/users/{id}/role-assignments/assign": func(w http.ResponseWriter, r *http.Request) {
+			s.assignRole(w, r, true)
+		},
+		"POST /v2/orgs/{org}/directories/-/users/{id}/role-assignments/revoke": func(w http.ResponseWriter, r *http.Request) {
+			s.assignRole(w, r, false)
+		},
+	}
+
+	for route, handler := range routes {
+		method, path, _ := strings.Cut(route, " ")
+		pattern := method + " " + adminPath + path
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			s.mtx.Lock()
+			defer s.mtx.Unlock()
+
+			s.requests[pattern]++
+
+			if r.PathValue("org") != s.fixture.OrganizationID {
+				writeJSON(w, http.StatusNotFound, adminError{Message: "organization not found"})
+				return
+			}
+			handler(w, r)
+		})
+	}
+}
+
+// registerUserManagement registers the user management API routes, which take the Admin API key. Handlers run
+// with s.mtx held.
+func (s *Server) registerUserManagement(mux *http.ServeMux) {
+	routes := map[string]http.HandlerFunc{
+		"GET /{account}/manage/api-tokens":            s.listAPITokens,
+		"DELETE /{account}/manage/api-tokens/{token}": s.revokeAPIToken,
+	}
+
+	for route, handler := range routes {
+		method, path, _ := strings.Cut(route, " ")
+		pattern := method + " " + userManagementPath + path
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			s.mtx.Lock()
+			defer s.mtx.Unlock()
+
+			s.requests[pattern]++
+
+			if _, ok := s.fixture.APITokens[r.PathValue("account")]; !ok {
+				writeJSON(w, http.StatusForbidden, adminError{Message: "the account is not managed by the organization"})
+				return
+			}
+			handler(w, r)
+		})
+	}
+}
+
+func (s *Server) listGroups(w http.ResponseWriter, r *http.Request) {
+	groups := make([]client.Group, 0, len(s.fixture.Groups))
+	for _, group := range s.fixture.Groups {
+		groups = append(groups, group.Group)
+	}
+
+	data, next, ok := adminPage(w, r, groups, s.pageSize(adminPageSize, adminPageSize))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, client.GroupsResponse{Data: data, Links: client.AdminLinks{Next: next}})
+}
+
+func (s *Server) createGroup(w http.ResponseWriter, r *http.Request) {
+	var input client.GroupInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Name == "" {
+		writeJSON(w, http.StatusBadRequest, adminError{Message: "a group name is required"})
+		return
+	}
+
+	group := Group{
+		Group: client.Group{
+			ID:          s.newID("group-"),
+			Name:        input.Name,
+			Description: input.Description,
+		},
+	}
+	s.fixture.Groups = append(s.fixture.Groups, group)
+
+	writeJSON(w, http.StatusCreated, group.Group)
+}
+
+func (s *Server) getGroup(w http.ResponseWriter, r *http.Request) {
+	group := s.group(w, r)
+	if group == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, group.Group)
+}
+
+func (s *Server) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	i := slices.IndexFunc(s.fixture.Groups, func(group Group) bool { return group.ID == r.PathValue("group") })
+	if i < 0 {
+		writeJSON(w, http.StatusNotFound, adminError{Message: "group not found"})
+		return
+	}
+	s.fixture.Groups = slices.Delete(s.fixture.Groups, i, i+1)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listGroupMembers(w http.ResponseWriter, r *http.Request) {
+	group := s.group(w, r)
+	if group == nil {
+		return
+	}
+
+	members := make([]client.GroupMember, 0, len(group.Members))
+	for _, accountID := range group.Members {
+		user := s.user(accountID)
+		members = append(members, client.GroupMember{AccountID: user.AccountID, Name: user.Name, Email: user.Email})
+	}
+
+	data, next, ok := adminPage(w, r, members, s.pageSize(adminPageSize, adminPageSize))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, client.GroupMembersResponse{Data: data, Links: client.AdminLinks{Next: next}})
+}
+
+func (s *Server) addGroupMember(w http.ResponseWriter, r *http.Request) {
+	group := s.group(w, r)
+	if group == nil {
+		return
+	}
+
+	var body groupMembershipBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.AccountID == "" {
+		writeJSON(w, http.StatusBadRequest, adminError{Message: "an account_id is required"})
+		return
+	}
+	if !slices.Contains(group.Members, body.AccountID) {
+		group.Members = append(group.Members, body.AccountID)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) removeGroupMember(w http.ResponseWriter, r *http.Request) {
+	group := s.group(w, r)
+	if group == nil {
+		return
+	}
+
+	var ok bool
+	group.Members, ok = remove(group.Members, r.PathValue("id"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, adminError{Message: "membership not found"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listEvents(w http.ResponseWriter, r *http.Request) {
+	from, _ := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+	to, _ := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+
+	var events []client.Event
+	for _, event := range s.fixture.Events {
+		if inRange(event.Attributes.Time, from, to) {
+			events = append(events, event)
+		}
+	}
+
+	data, next, ok := adminPage(w, r, events, s.pageSize(adminPageSize, adminPageSize))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, client.EventsResponse{Data: data, Links: client.AdminLinks{Next: next}})
+}
+
+func (s *Server) listAuthPolicies(w http.ResponseWriter, r *http.Request) {
+	policyType := r.URL.Query().Get("type")
+
+	policies := make([]client.AuthPolicy, 0, len(s.fixture.AuthPolicies))
+	for _, policy := range s.fixture.AuthPolicies {
+		if policyType == "" || policy.Attributes.Type == policyType {
+			policies = append(policies, policy.AuthPolicy)
+		}
+	}
+
+	data, next, ok := adminPage(w, r, policies, s.pageSize(adminPageSize, adminPageSize))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, client.AuthPoliciesResponse{Data: data, Links: client.AdminLinks{Next: next}})
+}
+
+func (s *Server) listAuthPolicyUsers(w http.ResponseWriter, r *http.Request) {
+	i := slices.IndexFunc(s.fixture.AuthPolicies, func(policy AuthPolicy) bool { return policy.ID == r.PathValue("policy") })
+	if i < 0 {
+		writeJSON(w, http.StatusNotFound, adminError{Message: "policy not found"})
+		return
+	}
+
+	accountIDs := s.fixture.AuthPolicies[i].Members
+	users := make([]client.DirectoryUser, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		user := s.user(accountID)
+		users = append(users, client.DirectoryUser{AccountID: user.AccountID, Name: user.Name, Email: user.Email})
+	}
+
+	data, next, ok := adminPage(w, r, users, s.pageSize(adminPageSize, adminPageSize))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, client.DirectoryUsersResponse{Data: data, Links: client.AdminLinks{Next: next}})
+}
+
+func (s *Server) listAPIKeys(w http.ResponseWriter, r *http.Request) {
+	data, next, ok := adminPage(w, r, s.fixture.APIKeys, s.pageSize(adminPageSize, adminPageSize))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, client.APIKeysResponse{Data: data, Links: client.AdminLinks{Next: next}})
+}
+
+func (s *Server) listAPITokens(w http.ResponseWriter, r *http.Request) {
+	tokens := s.fixture.APITokens[r.PathValue("account")]
+	if tokens == nil {
+		tokens = []client.UserAPIToken{}
+	}
+	writeJSON(w, http.StatusOK, tokens)
+}
+
+func (s *Server) revokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	accountID := r.PathValue("account")
+	tokens := s.fixture.APITokens[accountID]
+
+	i := slices.IndexFunc(tokens, func(token client.UserAPIToken) bool { return token.ID == r.PathValue("token") })
+	if i < 0 {
+		writeJSON(w, http.StatusNotFound, adminError{Message: "api token not found"})
+		return
+	}
+	s.fixture.APITokens[accountID] = slices.Delete(tokens, i, i+1)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listWorkspaces(w http.ResponseWriter, r *http.Request) {
+	workspaces := make([]client.Workspace, 0, len(s.fixture.Workspaces))
+	for _, workspace := range s.fixture.Workspaces {
+		workspaces = append(workspaces, workspace.Workspace)
+	}
+
+	data, next, ok := adminPage(w, r, workspaces, s.pageSize(adminPageSize, adminPageSize))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, client.WorkspacesResponse{Data: data, Links: client.AdminLinks{Next: next}})
+}
+
+func (s *Server) getWorkspace(w http.ResponseWriter, r *http.Request) {
+	workspace := s.workspace(r.PathValue("workspace"))
+	if workspace == nil {
+		writeJSON(w, http.StatusNotFound, adminError{Message: "workspace not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, client.WorkspaceResponse{Data: workspace.Workspace})
+}
+
+// listDirectoryUsers lists the users of the organization, or with resourceIds and roleIds the holders of a role
+// on a workspace, optionally narrowed to the account in accountIds.
+func (s *Server) listDirectoryUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var accountIDs []string
+	if resourceID := query.Get("resourceIds"); resourceID != "" {
+		workspace := s.workspace(resourceID)
+		if workspace == nil {
+			writeJSON(w, http.StatusBadRequest, adminError{Message: "unknown resourceIds"})
+			return
+		}
+		accountIDs = workspace.Roles[query.Get("roleIds")]
+	} else {
+		for _, user := range s.fixture.Users {
+			accountIDs = append(accountIDs, user.AccountID)
+		}
+	}
+
+	users := make([]client.DirectoryUser, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		if filter := query.Get("accountIds"); filter != "" && filter != accountID {
+			continue
+		}
+		user := s.user(accountID)
+		users = append(users, client.DirectoryUser{AccountID: user.AccountID, Name: user.Name, Email: user.Email})
+	}
+
+	data, next, ok := adminPage(w, r, users, s.pageSize(adminPageSize, adminPageSize))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, client.DirectoryUsersResponse{Data: data, Links: client.AdminLinks{Next: next}})
+}
+
+func (s *Server) assignRole(w http.ResponseWriter, r *http.Request, assign bool) {
+	var body roleAssignmentBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, adminError{Message: err.Error()})
+		return
+	}
+
+	workspace := s.workspace(body.ResourceID)
+	if workspace == nil {
+		writeJSON(w, http.StatusNotFound, adminError{Message: "workspace not found"})
+		return
+	}
+
+	accountID := r.PathValue("id")
+	holders := workspace.Roles[body.RoleID]
+	if assign {
+		if !slices.Contains(holders, accountID) {
+			holders = append(holders, accountID)
+		}
+	} else {
+		holders, _ = remove(holders, accountID)
+	}
+
+	if workspace.Roles == nil {
+		workspace.Roles = make(map[string][]string)
+	}
+	workspace.Roles[body.RoleID] = holders
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// group returns the group of the request path, or writes a not found error.
+func (s *Server) group(w http.ResponseWriter, r *http.Request) *Group {
+	for i := range s.fixture.Groups {
+		if s.fixture.Groups[i].ID == r.PathValue("group") {
+			return &s.fixture.Groups[i]
+		}
+	}
+
+	writeJSON(w, http.StatusNotFound, adminError{Message: "group not found"})
+	return nil
+}
+
+func (s *Server) workspace(id string) *Workspace {
+	for i := range s.fixture.Workspaces {
+		if s.fixture.Workspaces[i].ID == id {
+			return &s.fixture.Workspaces[i]
+		}
+	}
+	return nil
+}
+
+// adminPage returns the page of items at the cursor of the request, of at most the requested limit. Like the Admin API, the cursor of the
+// next page is returned in links.next. Invalid cursors are answered with a bad request error.
+func adminPage[T any](w http.ResponseWriter, r *http.Request, items []T, size int) ([]T, string, bool) {
+	if limit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && limit > 0 {
+		size = min(size, limit)
+	}
+
+	data, next, err := page(items, r.URL.Query().Get("cursor"), size)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, adminError{Message: err.Error()})
+		return nil, "", false
+	}
+	if data == nil {
+		data = []T{}
+	}
+	return data, next, true
+}
@@ -0,0 +1,112 @@
+package fakeatlassian_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/pkg/connector"
+	"github.com/conductorone/baton-atlassian/test/fakeatlassian"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+)
+
+// Tests that org API keys are linked to the admin who created them, that the API tokens of managed accounts
+// are listed under their owner, and that revoking a token removes it and succeeds once it is gone.
+func TestAPIKeysAndTokens(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	fixture, err := fakeatlassian.Load(filepath.Join("testdata", "org.json"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	server := fakeatlassian.New(t, fixture, fakeatlassian.WithMaxPageSize(1))
+
+	ctx := context.Background()
+	c, err := connector.New(
+		ctx,
+		client.NewBasicAuth("user@example.com", "token"),
+		client.NewBearerAuth("admin-key"),
+		server.Endpoints(),
+		fixture.OrganizationID,
+		"",
+		connector.ProductAccessModeRoleAssignment,
+		0,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	syncers := make(map[string]connectorbuilder.ResourceSyncer)
+	for _, syncer := range c.ResourceSyncers(ctx) {
+		syncers[syncer.ResourceType(ctx).Id] = syncer
+	}
+
+	keys := listResources(t, syncers["api_key"])
+	if len(keys) != len(fixture.APIKeys) {
+		t.Fatalf("Expected %d API keys, got %d", len(fixture.APIKeys), len(keys))
+	}
+	keyTrait := secretTrait(t, keys[0])
+	if keyTrait.CreatedById.GetResource() != "ari:cloud:identity::user/"+fixture.APIKeys[0].Attributes.CreatedBy {
+		t.Errorf("Expected the key to be created by User 1, got %v", keyTrait.CreatedById)
+	}
+	if !keyTrait.ExpiresAt.AsTime().Equal(fixture.APIKeys[0].Attributes.ExpiresAt) {
+		t.Errorf("Expected the key to expire at %s, got %v", fixture.APIKeys[0].Attributes.ExpiresAt, keyTrait.ExpiresAt)
+	}
+	if keys[0].Description != "read:events:admin, read:users:admin" {
+		t.Errorf("Expected the scopes as description, got %q", keys[0].Description)
+	}
+
+	users := listResources(t, syncers["user"])
+	annos := annotations.Annotations(users[0].Annotations)
+	if !annos.Contains(&v2.ChildResourceType{}) {
+		t.Fatalf("Expected users to have API tokens as children")
+	}
+
+	tokensSyncer := syncers["api_token"]
+	tokens := make(map[string][]*v2.Resource)
+	for _, user := range users {
+		page, _, _, err := tokensSyncer.List(ctx, user.Id, &pagination.Token{})
+		if err != nil {
+			t.Fatalf("Expected no error for %s, got %v", user.DisplayName, err)
+		}
+		tokens[user.DisplayName] = page
+	}
+	if len(tokens["User 1"]) != 2 || len(tokens["User 2"]) != 0 || len(tokens["User 3"]) != 0 {
+		t.Fatalf("Expected User 1 to own two tokens and the others none, got %v", tokens)
+	}
+
+	tokenTrait := secretTrait(t, tokens["User 1"][0])
+	if tokenTrait.IdentityId.GetResource() != users[0].Id.Resource || tokenTrait.LastUsedAt == nil {
+		t.Errorf("Expected the token to belong to User 1 and to have been used, got %v", tokenTrait)
+	}
+
+	deleter, ok := tokensSyncer.(connectorbuilder.ResourceManager)
+	if !ok {
+		t.Fatalf("Expected API tokens to be revocable")
+	}
+	if _, err := deleter.Delete(ctx, tokens["User 1"][0].Id); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	remaining := server.Fixture().APITokens[fixture.Users[0].AccountID]
+	if len(remaining) != 1 || remaining[0].ID != "token-cli" {
+		t.Errorf("Expected only the CLI token to remain, got %v", remaining)
+	}
+	if _, err := deleter.Delete(ctx, tokens["User 1"][0].Id); err != nil {
+		t.Errorf("Expected revoking the token again to succeed, got %v", err)
+	}
+}
+
+func secretTrait(t *testing.T, r *v2.Resource) *v2.SecretTrait {
+	t.Helper()
+
+	trait := &v2.SecretTrait{}
+	annos := annotations.Annotations(r.Annotations)
+	if _, err := annos.Pick(trait); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return trait
+}
@@ -0,0 +1,121 @@
+package fakeatlassian_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/pkg/connector"
+	"github.com/conductorone/baton-atlassian/test/fakeatlassian"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// Tests that users report the SSO and two-step verification enforcement of the authentication policy applying
+// to them, and that policies grant their member entitlement to those users without fetching them again.
+func TestAuthPolicies(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	fixture, err := fakeatlassian.Load(filepath.Join("testdata", "org.json"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	server := fakeatlassian.New(t, fixture, fakeatlassian.WithMaxPageSize(1))
+
+	ctx := context.Background()
+	c, err := connector.New(
+		ctx,
+		client.NewBasicAuth("user@example.com", "token"),
+		client.NewBearerAuth("admin-key"),
+		server.Endpoints(),
+		fixture.OrganizationID,
+		"",
+		connector.ProductAccessModeRoleAssignment,
+		0,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	syncers := make(map[string]connectorbuilder.ResourceSyncer)
+	for _, syncer := range c.ResourceSyncers(ctx) {
+		syncers[syncer.ResourceType(ctx).Id] = syncer
+	}
+
+	users := listResources(t, syncers["user"])
+	if len(users) != len(fixture.Users) {
+		t.Fatalf("Expected %d users, got %d", len(fixture.Users), len(users))
+	}
+	for _, user := range users {
+		userTrait, err := resource.GetUserTrait(user)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// User 1 to 3 are in the SSO enforced policy, User 4 and 5 in the default one.
+		enforced := user.DisplayName == "User 1" || user.DisplayName == "User 2" || user.DisplayName == "User 3"
+		if userTrait.GetSsoStatus().GetSsoEnabled() != enforced || userTrait.GetMfaStatus().GetMfaEnabled() != enforced {
+			t.Errorf("Expected %s to have SSO and MFA enforced %t, got %v and %v", user.DisplayName, enforced, userTrait.GetSsoStatus(), userTrait.GetMfaStatus())
+		}
+	}
+
+	const policyUsersRoute = "GET /admin/v1/orgs/{org}/policies/{policy}/users"
+	policyUsersRequests := server.Requests(policyUsersRoute)
+	if policyUsersRequests == 0 {
+		t.Fatalf("Expected the policy members to be loaded with the users")
+	}
+
+	policies := listResources(t, syncers["auth_policy"])
+	if len(policies) != len(fixture.AuthPolicies) {
+		t.Fatalf("Expected %d policies, got %d", len(fixture.AuthPolicies), len(policies))
+	}
+
+	groupTrait, err := resource.GetGroupTrait(policies[1])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if timeout, _ := resource.GetProfileInt64Value(groupTrait.Profile, "session_timeout_minutes"); timeout != 480 {
+		t.Errorf("Expected a session timeout of 480 minutes, got %d", timeout)
+	}
+
+	var grants []*v2.Grant
+	token := &pagination.Token{Size: 1}
+	for {
+		page, next, _, err := syncers["auth_policy"].Grants(ctx, policies[0], token)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		grants = append(grants, page...)
+		if next == "" {
+			break
+		}
+		token = &pagination.Token{Size: 1, Token: next}
+	}
+	if len(grants) != 2 || grants[0].Principal.Id.Resource != "ari:cloud:identity::user/"+fixture.AuthPolicies[0].Members[0] {
+		t.Errorf("Expected the default policy to be granted to User 4 and 5, got %v", grants)
+	}
+	if got := server.Requests(policyUsersRoute); got != policyUsersRequests {
+		t.Errorf("Expected the policy grants to reuse the members loaded with the users, got %d more requests", got-policyUsersRequests)
+	}
+}
+
+func listResources(t *testing.T, syncer connectorbuilder.ResourceSyncer) []*v2.Resource {
+	t.Helper()
+
+	var resources []*v2.Resource
+	token := &pagination.Token{Size: 1}
+	for {
+		page, next, _, err := syncer.List(context.Background(), nil, token)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resources = append(resources, page...)
+		if next == "" {
+			return resources
+		}
+		token = &pagination.Token{Size: 1, Token: next}
+	}
+}
@@ -0,0 +1,90 @@
+package fakeatlassian_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/pkg/connector"
+	"github.com/conductorone/baton-atlassian/test/fakeatlassian"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// Tests that Compass components are synced for the configured site, with their tier, and that ownership is
+// granted to the owning team and expands to its members.
+func TestCompassComponents(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	fixture, err := fakeatlassian.Load(filepath.Join("testdata", "org.json"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	server := fakeatlassian.New(t, fixture, fakeatlassian.WithMaxPageSize(1))
+
+	ctx := context.Background()
+	c, err := connector.New(
+		ctx,
+		client.NewBasicAuth("user@example.com", "token"),
+		nil,
+		server.Endpoints(),
+		fixture.OrganizationID,
+		fixture.CloudID,
+		"",
+		0,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	syncers := c.ResourceSyncers(ctx)
+	components := syncers[len(syncers)-1]
+	if components.ResourceType(ctx).Id != "compass_component" {
+		t.Fatalf("Expected the last syncer to sync compass components, got %s", components.ResourceType(ctx).Id)
+	}
+
+	var resources []*v2.Resource
+	token := &pagination.Token{Size: 1}
+	for {
+		page, next, _, err := components.List(ctx, nil, token)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resources = append(resources, page...)
+		if next == "" {
+			break
+		}
+		token = &pagination.Token{Size: 1, Token: next}
+	}
+	if len(resources) != len(fixture.Components) {
+		t.Fatalf("Expected %d components, got %d", len(fixture.Components), len(resources))
+	}
+
+	appTrait, err := resource.GetAppTrait(resources[0])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if tier, _ := resource.GetProfileStringValue(appTrait.Profile, "tier"); tier != "1" {
+		t.Errorf("Expected tier 1, got %q", tier)
+	}
+
+	grants, _, _, err := components.Grants(ctx, resources[0], &pagination.Token{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(grants) != 1 || grants[0].Principal.Id.Resource != "ari:cloud:identity::team/teamTest1" {
+		t.Fatalf("Expected ownership to be granted to Team 1, got %v", grants)
+	}
+	annos := annotations.Annotations(grants[0].Annotations)
+	if !annos.Contains(&v2.GrantExpandable{}) {
+		t.Errorf("Expected the ownership grant to expand to the team's members")
+	}
+
+	grants, _, _, err = components.Grants(ctx, resources[1], &pagination.Token{})
+	if err != nil || len(grants) != 0 {
+		t.Errorf("Expected no grants for the unowned component, got %v and %v", grants, err)
+	}
+}
@@ -0,0 +1,153 @@
+package fakeatlassian_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/pkg/connector"
+	"github.com/conductorone/baton-atlassian/test"
+	"github.com/conductorone/baton-atlassian/test/fakeatlassian"
+)
+
+func TestConformance(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	fixture, err := fakeatlassian.Load(filepath.Join("testdata", "org.json"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name              string
+		adminAuth         client.Authenticator
+		siteID            string
+		productAccessMode string
+	}{
+		{
+			name: "without admin access",
+		},
+		{
+			name:              "role assignment",
+			adminAuth:         client.NewBearerAuth("admin-key"),
+			productAccessMode: connector.ProductAccessModeRoleAssignment,
+		},
+		{
+			name:              "default group",
+			adminAuth:         client.NewBearerAuth("admin-key"),
+			productAccessMode: connector.ProductAccessModeDefaultGroup,
+		},
+		{
+			name:   "compass components",
+			siteID: fixture.CloudID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := fakeatlassian.New(t, fixture, fakeatlassian.WithMaxPageSize(test.ConformancePageSize))
+
+			ctx := context.Background()
+			c, err := connector.New(
+				ctx,
+				client.NewBasicAuth("user@example.com", "token"),
+				tt.adminAuth,
+				server.Endpoints(),
+				fixture.OrganizationID,
+				tt.siteID,
+				tt.productAccessMode,
+				0,
+			)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			test.Conformance(t, c.ResourceSyncers(ctx))
+		})
+	}
+
+	t.Run("export", func(t *testing.T) {
+		ctx := context.Background()
+		c, err := connector.NewFromExport(
+			ctx,
+			filepath.Join("..", "mockResponses", "ExportUsers.csv"),
+			filepath.Join("..", "mockResponses", "ExportTeams.json"),
+		)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		test.Conformance(t, c.ResourceSyncers(ctx))
+	})
+
+	backends := []struct {
+		name     string
+		fixtures map[string]string
+		connect  func(ctx context.Context, baseURL string) (*connector.Connector, error)
+	}{
+		{
+			name:     "jira data center",
+			fixtures: test.JiraDCFixtures,
+			connect: func(ctx context.Context, baseURL string) (*connector.Connector, error) {
+				return connector.NewDataCenter(ctx, connector.DataCenterProductJira, baseURL, client.NewBearerAuth("pat"))
+			},
+		},
+		{
+			name:     "confluence data center",
+			fixtures: test.ConfluenceDCFixtures,
+			connect: func(ctx context.Context, baseURL string) (*connector.Connector, error) {
+				return connector.NewDataCenter(ctx, connector.DataCenterProductConfluence, baseURL, client.NewBearerAuth("pat"))
+			},
+		},
+		{
+			name:     "bitbucket data center",
+			fixtures: test.BitbucketDCFixtures,
+			connect: func(ctx context.Context, baseURL string) (*connector.Connector, error) {
+				return connector.NewDataCenter(ctx, connector.DataCenterProductBitbucket, baseURL, client.NewBearerAuth("pat"))
+			},
+		},
+		{
+			name:     "crowd",
+			fixtures: test.CrowdFixtures,
+			connect: func(ctx context.Context, baseURL string) (*connector.Connector, error) {
+				return connector.NewDataCenter(ctx, connector.DataCenterProductCrowd, baseURL, client.NewBasicAuth("baton", "secret"))
+			},
+		},
+		{
+			name:     "opsgenie",
+			fixtures: test.OpsgenieFixtures,
+			connect: func(ctx context.Context, baseURL string) (*connector.Connector, error) {
+				return connector.NewOpsgenie(ctx, baseURL, "key")
+			},
+		},
+		{
+			name:     "trello",
+			fixtures: test.TrelloFixtures,
+			connect: func(ctx context.Context, baseURL string) (*connector.Connector, error) {
+				return connector.NewTrello(ctx, baseURL, "e-1", "key", "token")
+			},
+		},
+		{
+			name:     "statuspage",
+			fixtures: test.StatuspageFixtures,
+			connect: func(ctx context.Context, baseURL string) (*connector.Connector, error) {
+				return connector.NewStatuspage(ctx, baseURL, "org-1", "key")
+			},
+		},
+	}
+
+	for _, tt := range backends {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := test.NewFixtureServer(t, tt.fixtures)
+
+			ctx := context.Background()
+			c, err := tt.connect(ctx, server.URL)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			test.Conformance(t, c.ResourceSyncers(ctx))
+		})
+	}
+}
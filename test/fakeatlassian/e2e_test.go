@@ -0,0 +1,159 @@
+package fakeatlassian_test
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+	"github.com/conductorone/baton-atlassian/pkg/connector"
+	"github.com/conductorone/baton-atlassian/test"
+	"github.com/conductorone/baton-atlassian/test/fakeatlassian"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/dotc1z"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	sdkSync "github.com/conductorone/baton-sdk/pkg/sync"
+)
+
+// Tests a full sync of the fixture organization through the SDK syncer, with pages small enough that every
+// listing spans several of them.
+func TestSync(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	fixture, err := fakeatlassian.Load(filepath.Join("testdata", "org.json"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	server := fakeatlassian.New(t, fixture, fakeatlassian.WithMaxPageSize(1))
+
+	ctx := context.Background()
+	c, err := connector.New(
+		ctx,
+		client.NewBasicAuth("user@example.com", "token"),
+		client.NewBearerAuth("admin-key"),
+		server.Endpoints(),
+		fixture.OrganizationID,
+		"",
+		connector.ProductAccessModeRoleAssignment,
+		0,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	srv, err := connectorbuilder.NewConnector(ctx, c)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	dir := t.TempDir()
+	c1zPath := filepath.Join(dir, "sync.c1z")
+	syncer, err := sdkSync.NewSyncer(ctx, test.NewConnectorClient(t, srv), sdkSync.WithC1ZPath(c1zPath), sdkSync.WithTmpDir(dir))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := syncer.Sync(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := syncer.Close(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	store, err := dotc1z.NewC1ZFile(ctx, c1zPath, dotc1z.WithTmpDir(dir))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer store.Close()
+
+	stats, err := store.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]int64{
+		"user":         6,
+		"team":         3,
+		"group":        2,
+		"product_role": 4,
+		"auth_policy":  2,
+		"api_key":      2,
+		"api_token":    2,
+		// 5 team memberships, 6 group memberships, 7 product role assignments and 5 policy memberships.
+		"grants": 23,
+	}
+	for key, count := range expected {
+		if stats[key] != count {
+			t.Errorf("Expected %d %s, got %d", count, key, stats[key])
+		}
+	}
+
+	for _, operation := range []string{"Teams", "GET /admin/v1/orgs/{org}/groups", "GET /admin/v2/orgs/{org}/workspaces"} {
+		if server.Requests(operation) < 2 {
+			t.Errorf("Expected %s to be paged, got %d requests", operation, server.Requests(operation))
+		}
+	}
+}
+
+// Tests that with Admin API access the users of the organization who belong to no team are synced, so that
+// their group memberships point at emitted users.
+func TestUsersOutsideTeams(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	fixture, err := fakeatlassian.Load(filepath.Join("testdata", "org.json"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	server := fakeatlassian.New(t, fixture, fakeatlassian.WithMaxPageSize(1))
+
+	ctx := context.Background()
+	c, err := connector.New(
+		ctx,
+		client.NewBasicAuth("user@example.com", "token"),
+		client.NewBearerAuth("admin-key"),
+		server.Endpoints(),
+		fixture.OrganizationID,
+		"",
+		connector.ProductAccessModeRoleAssignment,
+		0,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	syncers := make(map[string]connectorbuilder.ResourceSyncer)
+	for _, syncer := range c.ResourceSyncers(ctx) {
+		syncers[syncer.ResourceType(ctx).Id] = syncer
+	}
+
+	// User 6 is a member of the engineering group only.
+	const outsider = "ari:cloud:identity::user/712020:6a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+
+	users := listResources(t, syncers["user"])
+	if !slices.ContainsFunc(users, func(user *v2.Resource) bool { return user.Id.Resource == outsider }) {
+		t.Fatalf("Expected User 6 to be synced although it belongs to no team, got %v", users)
+	}
+
+	groups := listResources(t, syncers["group"])
+	i := slices.IndexFunc(groups, func(group *v2.Resource) bool { return group.Id.Resource == "group-scim-engineering" })
+	if i < 0 {
+		t.Fatalf("Expected the engineering group, got %v", groups)
+	}
+	var grants []*v2.Grant
+	token := &pagination.Token{Size: 1}
+	for {
+		page, next, _, err := syncers["group"].Grants(ctx, groups[i], token)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		grants = append(grants, page...)
+		if next == "" {
+			break
+		}
+		token = &pagination.Token{Size: 1, Token: next}
+	}
+	if !slices.ContainsFunc(grants, func(grant *v2.Grant) bool { return grant.Principal.Id.Resource == outsider }) {
+		t.Errorf("Expected User 6 to be granted engineering membership, got %v", grants)
+	}
+}
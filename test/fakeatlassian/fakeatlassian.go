@@ -0,0 +1,268 @@
+// Package fakeatlassian is an in-process fake of the Atlassian GraphQL gateway and Admin API for end-to-end
+// tests. It serves the operations and endpoints the connector uses from a fixture, with cursor paging and
+// mutations applied to the fixture.
+package fakeatlassian
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+)
+
+const (
+	graphqlPath = "/gateway/api/graphql"
+	adminPath   = "/admin"
+	// userManagementPath is deliberately not next to adminPath, so that clients must be configured with it.
+	userManagementPath = "/user-management"
+
+	orgARIPrefix  = "ari:cloud:platform::org/"
+	userARIPrefix = "ari:cloud:identity::user/"
+	teamARIPrefix = "ari:cloud:identity::team/"
+
+	// defaultPageSize is the page size of GraphQL connections queried without an explicit size.
+	defaultPageSize = 50
+)
+
+// Fixture is the content of the fake organization.
+type Fixture struct {
+	OrganizationID string          `json:"organizationId"`
+	CloudID        string          `json:"cloudId"`
+	Users          []User          `json:"users"`
+	Teams          []Team          `json:"teams"`
+	Groups         []Group         `json:"groups"`
+	Workspaces     []Workspace     `json:"workspaces"`
+	Components     []Component     `json:"components"`
+	AuthPolicies   []AuthPolicy    `json:"authPolicies"`
+	APIKeys        []client.APIKey `json:"apiKeys"`
+	// APITokens holds the API tokens of the managed accounts by account ID. Accounts without an entry are not
+	// managed by the organization.
+	APITokens map[string][]client.UserAPIToken `json:"apiTokens"`
+	Events    []client.Event                   `json:"events"`
+}
+
+type User struct {
+	AccountID string `json:"accountId"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+}
+
+type Team struct {
+	ID                 string       `json:"id"`
+	DisplayName        string       `json:"displayName"`
+	Description        string       `json:"description"`
+	MembershipSettings string       `json:"membershipSettings"`
+	Members            []TeamMember `json:"members"`
+}
+
+type TeamMember struct {
+	AccountID string `json:"accountId"`
+	Role      string `json:"role"`
+}
+
+// Component is a Compass component of the site, owned by the team with the ID in OwnerID.
+type Component struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	TypeID      string `json:"typeId"`
+	Tier        string `json:"tier"`
+	OwnerID     string `json:"ownerId"`
+}
+
+// Group is an organization group and the account IDs of its members.
+type Group struct {
+	client.Group
+	Members []string `json:"members"`
+}
+
+// Workspace is a product instance and the account IDs holding each of its roles.
+type Workspace struct {
+	client.Workspace
+	Roles map[string][]string `json:"roles"`
+}
+
+// AuthPolicy is an authentication policy and the account IDs of the users it applies to.
+type AuthPolicy struct {
+	client.AuthPolicy
+	Members []string `json:"members"`
+}
+
+// Load reads a fixture from a JSON file.
+func Load(path string) (Fixture, error) {
+	var fixture Fixture
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fixture, err
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return fixture, fmt.Errorf("fakeatlassian: invalid fixture %s: %w", path, err)
+	}
+
+	return fixture, nil
+}
+
+// Server serves a fixture. Requests must be authenticated, but any credentials are accepted.
+type Server struct {
+	*httptest.Server
+
+	maxPageSize int
+
+	mtx      sync.Mutex
+	fixture  Fixture
+	nextID   int
+	requests map[string]int
+}
+
+type Option func(*Server)
+
+// WithMaxPageSize caps the size of every page, whatever the client asks for, so that small fixtures still
+// span several pages. By default GraphQL page sizes are left to the client and Admin API pages hold 100 items.
+func WithMaxPageSize(size int) Option {
+	return func(s *Server) {
+		s.maxPageSize = size
+	}
+}
+
+// New starts a server for the fixture that is closed when the test ends.
+func New(t testing.TB, fixture Fixture, opts ...Option) *Server {
+	t.Helper()
+
+	s := &Server{
+		fixture:  fixture,
+		requests: make(map[string]int),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+graphqlPath, s.handleGraphQL)
+	s.registerAdmin(mux)
+	s.registerUserManagement(mux)
+
+	s.Server = httptest.NewServer(s.authenticate(mux))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Endpoints returns the client endpoints of the server.
+func (s *Server) Endpoints() client.Endpoints {
+	return client.Endpoints{
+		GraphQL:        s.URL + graphqlPath,
+		Admin:          s.URL + adminPath,
+		UserManagement: s.URL + userManagementPath,
+	}
+}
+
+// Fixture returns the current content of the organization, including the changes made through the API.
+func (s *Server) Fixture() Fixture {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	data, _ := json.Marshal(s.fixture)
+	var fixture Fixture
+	_ = json.Unmarshal(data, &fixture)
+	return fixture
+}
+
+// Requests returns the number of requests received for a GraphQL operation name or an Admin API route
+// pattern, e.g. "GET /admin/v1/orgs/{org}/groups".
+func (s *Server) Requests(name string) int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.requests[name]
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "missing credentials"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// pageSize returns the size of the next page for the requested size.
+func (s *Server) pageSize(requested, fallback int) int {
+	if requested <= 0 {
+		requested = fallback
+	}
+	if s.maxPageSize > 0 {
+		return min(requested, s.maxPageSize)
+	}
+	return requested
+}
+
+// newID returns a new unique ID for a created object. It must be called with s.mtx held.
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s%d", prefix, s.nextID)
+}
+
+func (s *Server) user(accountID string) User {
+	for _, user := range s.fixture.Users {
+		if user.AccountID == accountID {
+			return user
+		}
+	}
+	return User{AccountID: accountID}
+}
+
+// page returns the items of the page starting at cursor, and the cursor of the next page or "" on the last
+// page. Cursors are offsets.
+func page[T any](items []T, cursor string, size int) ([]T, string, error) {
+	offset := 0
+	if cursor != "" {
+		var err error
+		offset, err = strconv.Atoi(cursor)
+		if err != nil || offset < 0 {
+			return nil, "", fmt.Errorf("invalid cursor %q", cursor)
+		}
+	}
+	if offset >= len(items) {
+		return nil, "", nil
+	}
+
+	end := min(offset+size, len(items))
+	if end == len(items) {
+		return items[offset:end], "", nil
+	}
+	return items[offset:end], strconv.Itoa(end), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// inRange reports whether the RFC 3339 time is within [from, to], both in milliseconds since the epoch.
+func inRange(value string, from, to int64) bool {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return false
+	}
+	ms := t.UnixMilli()
+	return ms >= from && (to == 0 || ms <= to)
+}
+
+func remove(values []string, value string) ([]string, bool) {
+	i := slices.Index(values, value)
+	if i < 0 {
+		return values, false
+	}
+	return slices.Delete(values, i, i+1), true
+}
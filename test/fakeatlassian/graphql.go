@@ -0,0 +1,235 @@
+package fakeatlassian
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/conductorone/baton-atlassian/pkg/client"
+)
+
+type graphQLRequest struct {
+	OperationName string          `json:"operationName"`
+	Variables     json.RawMessage `json:"variables"`
+}
+
+type graphQLResponse struct {
+	Data   any            `json:"data,omitempty"`
+	Errors []graphQLError `json:"errors,omitempty"`
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+// handleGraphQL serves the operations in pkg/client/*.graphql, dispatched on their operation name.
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, graphQLResponse{Errors: []graphQLError{{Message: err.Error()}}})
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.requests[req.OperationName]++
+
+	var data any
+	var err error
+	switch req.OperationName {
+	case "Teams":
+		data, err = s.teams(req.Variables)
+	case "TeamMembers":
+		data, err = s.teamMembers(req.Variables)
+	case "CreateTeam":
+		data, err = s.createTeam(req.Variables)
+	case "DeleteTeam":
+		data, err = s.deleteTeam(req.Variables)
+	case "CompassComponents":
+		data, err = s.compassComponents(req.Variables)
+	default:
+		writeJSON(w, http.StatusBadRequest, graphQLResponse{Errors: []graphQLError{{Message: "unknown operation " + req.OperationName}}})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusOK, graphQLResponse{Errors: []graphQLError{{Message: err.Error()}}})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, graphQLResponse{Data: data})
+}
+
+func (s *Server) teams(variables json.RawMessage) (*client.TeamsResponse, error) {
+	var vars client.TeamsVariables
+	if err := json.Unmarshal(variables, &vars); err != nil {
+		return nil, err
+	}
+	if vars.OrganizationID != s.fixture.OrganizationID {
+		return nil, fmt.Errorf("organization %q not found", vars.OrganizationID)
+	}
+
+	teams, next, err := page(s.fixture.Teams, vars.AfterTeam, s.pageSize(vars.FirstTeam, defaultPageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var res client.TeamsResponse
+	res.Team.TeamSearchV2.PageInfo = pageInfo(next)
+	for _, team := range teams {
+		var edge client.TeamEdge
+		edge.Node.Team.Team = client.Team{
+			ID:                 team.ID,
+			OrganizationID:     orgARIPrefix + s.fixture.OrganizationID,
+			DisplayName:        team.DisplayName,
+			Description:        team.Description,
+			MembershipSettings: team.MembershipSettings,
+		}
+
+		members, next, err := page(team.Members, "", s.pageSize(vars.FirstMember, defaultPageSize))
+		if err != nil {
+			return nil, err
+		}
+		edge.Node.Team.Members.PageInfo = pageInfo(next)
+		edge.Node.Team.Members.Edges = s.memberEdges(members)
+
+		res.Team.TeamSearchV2.Edges = append(res.Team.TeamSearchV2.Edges, edge)
+	}
+
+	return &res, nil
+}
+
+func (s *Server) teamMembers(variables json.RawMessage) (*client.TeamMembersResponse, error) {
+	var vars client.TeamMembersVariables
+	if err := json.Unmarshal(variables, &vars); err != nil {
+		return nil, err
+	}
+
+	var res client.TeamMembersResponse
+	for _, team := range s.fixture.Teams {
+		if team.ID != vars.TeamID {
+			continue
+		}
+
+		members, next, err := page(team.Members, vars.After, s.pageSize(vars.First, defaultPageSize))
+		if err != nil {
+			return nil, err
+		}
+		res.Team.TeamV2.Members.PageInfo = pageInfo(next)
+		res.Team.TeamV2.Members.Edges = s.memberEdges(members)
+	}
+
+	return &res, nil
+}
+
+func (s *Server) createTeam(variables json.RawMessage) (*client.CreateTeamResponse, error) {
+	var vars client.CreateTeamVariables
+	if err := json.Unmarshal(variables, &vars); err != nil {
+		return nil, err
+	}
+
+	team := Team{
+		ID:                 s.newID(teamARIPrefix),
+		DisplayName:        vars.DisplayName,
+		Description:        vars.Description,
+		MembershipSettings: vars.MembershipSettings,
+	}
+	s.fixture.Teams = append(s.fixture.Teams, team)
+
+	var res client.CreateTeamResponse
+	res.Team.CreateTeam.Success = true
+	res.Team.CreateTeam.Team = client.Team{
+		ID:                 team.ID,
+		OrganizationID:     orgARIPrefix + vars.OrganizationID,
+		DisplayName:        team.DisplayName,
+		Description:        team.Description,
+		MembershipSettings: team.MembershipSettings,
+	}
+
+	return &res, nil
+}
+
+func (s *Server) deleteTeam(variables json.RawMessage) (*client.DeleteTeamResponse, error) {
+	var vars client.DeleteTeamVariables
+	if err := json.Unmarshal(variables, &vars); err != nil {
+		return nil, err
+	}
+
+	var res client.DeleteTeamResponse
+	for i, team := range s.fixture.Teams {
+		if team.ID == vars.TeamID {
+			s.fixture.Teams = slices.Delete(s.fixture.Teams, i, i+1)
+			res.Team.DeleteTeam.Success = true
+			return &res, nil
+		}
+	}
+
+	res.Team.DeleteTeam.Errors = []client.MutationError{{Message: "team not found", Code: "NOT_FOUND"}}
+	return &res, nil
+}
+
+func (s *Server) compassComponents(variables json.RawMessage) (*client.CompassComponentsResponse, error) {
+	var vars client.CompassComponentsVariables
+	if err := json.Unmarshal(variables, &vars); err != nil {
+		return nil, err
+	}
+
+	var res client.CompassComponentsResponse
+	if vars.CloudID != s.fixture.CloudID {
+		res.Compass.SearchComponents.Message = fmt.Sprintf("site %q not found", vars.CloudID)
+		return &res, nil
+	}
+
+	components, next, err := page(s.fixture.Components, vars.After, s.pageSize(vars.First, defaultPageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	connection := &res.Compass.SearchComponents.CompassComponentConnection
+	connection.PageInfo = pageInfo(next)
+	for _, component := range components {
+		var node client.CompassComponentConnectionNodes
+		node.Component = client.CompassComponent{
+			ID:          component.ID,
+			Name:        component.Name,
+			Description: component.Description,
+			TypeID:      component.TypeID,
+			OwnerID:     component.OwnerID,
+		}
+		if component.Tier != "" {
+			node.Component.Fields = []client.CompassEnumField{{
+				Definition: client.CompassEnumFieldDefinition{ID: "compass:tier", Name: "Tier"},
+				Value:      []string{component.Tier},
+			}}
+		}
+		connection.Nodes = append(connection.Nodes, node)
+	}
+
+	return &res, nil
+}
+
+func (s *Server) memberEdges(members []TeamMember) []client.MemberEdge {
+	edges := make([]client.MemberEdge, 0, len(members))
+	for _, member := range members {
+		user := s.user(member.AccountID)
+		edges = append(edges, client.MemberEdge{
+			Node: client.MemberEdgeNode{
+				Member: client.Member{
+					AccountID: user.AccountID,
+					ID:        userARIPrefix + user.AccountID,
+					Name:      user.Name,
+				},
+				Role: member.Role,
+			},
+		})
+	}
+	return edges
+}
+
+func pageInfo(next string) client.PageInfo {
+	return client.PageInfo{
+		HasNextPage: next != "",
+		EndCursor:   next,
+	}
+}
@@ -0,0 +1,134 @@
+package test
+
+// The fixture routes of the product backends, for NewFixtureRoundTripper and NewFixtureServer. Every instance
+// they describe answers the listings of all of its resource types, so that syncs of it satisfy the
+// conformance suite.
+
+// JiraDCFixtures answer as a Jira Data Center instance with three users, two groups, one application role
+// and one project.
+var JiraDCFixtures = map[string]string{
+	"GET /rest/api/2/user/search?startAt=0":                      "JiraDCUsers.json",
+	"GET /rest/api/2/user/search":                                "EmptyList.json",
+	"GET /rest/api/2/user?username=alice":                        "JiraDCUser.json",
+	"GET /rest/api/2/groups/picker":                              "JiraDCGroups.json",
+	"GET /rest/api/2/group/member?groupname=jira-administrators": "JiraDCAdministratorsMembers.json",
+	"GET /rest/api/2/group/member?groupname=jira-software-users": "JiraDCSoftwareUsersMembers.json",
+	"GET /rest/api/2/applicationrole":                            "JiraDCApplicationRoles.json",
+	"GET /rest/api/2/project":                                    "JiraDCProjects.json",
+	"GET /rest/api/2/role":                                       "JiraDCRoles.json",
+	"GET /rest/api/2/project/10000/role/10002":                   "JiraDCProjectRole.json",
+}
+
+// ConfluenceDCFixtures answer as a Confluence Data Center instance with three users, two groups and one space,
+// and accept group membership changes made through JSON-RPC.
+var ConfluenceDCFixtures = map[string]string{
+	"GET /rest/api/user/list?start=0":                                 "ConfluenceDCUsers.json",
+	"GET /rest/api/user/list":                                         "ConfluenceDCEmptyPage.json",
+	"GET /rest/api/user?username=alice":                               "ConfluenceDCUserAlice.json",
+	"GET /rest/api/user?key=8a7f808a2":                                "ConfluenceDCUserAlice.json",
+	"GET /rest/api/user?username=bob":                                 "ConfluenceDCUserBob.json",
+	"GET /rest/api/group?start=0":                                     "ConfluenceDCGroups.json",
+	"GET /rest/api/group":                                             "ConfluenceDCEmptyPage.json",
+	"GET /rest/api/group/confluence-administrators/member?start=0":    "ConfluenceDCAdministratorsMembers.json",
+	"GET /rest/api/group/confluence-administrators/member":            "ConfluenceDCEmptyPage.json",
+	"GET /rest/api/group/confluence-users/member?start=0":             "ConfluenceDCUsersMembers.json",
+	"GET /rest/api/group/confluence-users/member":                     "ConfluenceDCEmptyPage.json",
+	"GET /rest/api/space?start=0":                                     "ConfluenceDCSpaces.json",
+	"GET /rest/api/space":                                             "ConfluenceDCEmptyPage.json",
+	"POST /rpc/json-rpc/confluenceservice-v2/getGlobalPermissionSets": "ConfluenceDCGlobalPermissions.json",
+	"POST /rpc/json-rpc/confluenceservice-v2/getSpacePermissionSets":  "ConfluenceDCSpacePermissions.json",
+	"POST /rpc/json-rpc/confluenceservice-v2/addUserToGroup":          "ConfluenceDCRPCSucceeded.json",
+	"POST /rpc/json-rpc/confluenceservice-v2/removeUserFromGroup":     "ConfluenceDCRPCSucceeded.json",
+}
+
+// BitbucketDCFixtures answer as a Bitbucket Data Center instance with two users, one group, one project and
+// one repository, and accept the permissions set on them.
+var BitbucketDCFixtures = map[string]string{
+	"GET /rest/api/1.0/admin/users":                                       "BitbucketDCUsers.json",
+	"GET /rest/api/1.0/users/admin":                                       "BitbucketDCUserAdmin.json",
+	"GET /rest/api/1.0/users/alice_example.com":                           "BitbucketDCUserAlice.json",
+	"GET /rest/api/1.0/admin/groups":                                      "BitbucketDCGroups.json",
+	"GET /rest/api/1.0/admin/groups/more-members?context=developers":      "BitbucketDCDevelopersMembers.json",
+	"GET /rest/api/1.0/admin/permissions/users":                           "BitbucketDCGlobalUserPermissions.json",
+	"GET /rest/api/1.0/admin/permissions/groups":                          "BitbucketDCGlobalGroupPermissions.json",
+	"GET /rest/api/1.0/projects":                                          "BitbucketDCProjects.json",
+	"GET /rest/api/1.0/projects/PROJ/permissions/users":                   "BitbucketDCProjectUserPermissions.json",
+	"GET /rest/api/1.0/projects/PROJ/permissions/groups":                  "BitbucketDCProjectGroupPermissions.json",
+	"PUT /rest/api/1.0/projects/PROJ/permissions/users":                   "204",
+	"DELETE /rest/api/1.0/projects/PROJ/permissions/users":                "204",
+	"GET /rest/api/1.0/repos":                                             "BitbucketDCRepositories.json",
+	"GET /rest/api/1.0/projects/PROJ/repos/service/permissions/users":     "BitbucketDCEmptyPage.json",
+	"GET /rest/api/1.0/projects/PROJ/repos/service/permissions/groups":    "BitbucketDCRepositoryGroupPermissions.json",
+	"PUT /rest/api/1.0/projects/PROJ/repos/service/permissions/users":     "204",
+	"DELETE /rest/api/1.0/projects/PROJ/repos/service/permissions/groups": "204",
+	"GET /rest/access-tokens/1.0/users/alice_example.com":                 "BitbucketDCAccessTokensAlice.json",
+	"GET /rest/access-tokens/1.0/users/admin":                             "BitbucketDCEmptyPage.json",
+	"GET /rest/ssh/1.0/keys?user=alice@example.com":                       "BitbucketDCSSHKeysAlice.json",
+	"GET /rest/ssh/1.0/keys":                                              "BitbucketDCEmptyPage.json",
+}
+
+// CrowdFixtures answer as a Crowd server with three users and two groups, one nested in the other, and
+// accept membership changes and deactivations.
+var CrowdFixtures = map[string]string{
+	"GET /rest/usermanagement/1/search?entity-type=user&start-index=0":                                 "CrowdUsers.json",
+	"GET /rest/usermanagement/1/search?entity-type=user":                                               "CrowdEmptyUsers.json",
+	"GET /rest/usermanagement/1/search?entity-type=group&start-index=0":                                "CrowdGroups.json",
+	"GET /rest/usermanagement/1/search?entity-type=group":                                              "CrowdEmptyGroups.json",
+	"GET /rest/usermanagement/1/user?username=alice":                                                   "CrowdUserAlice.json",
+	"PUT /rest/usermanagement/1/user?username=alice":                                                   "204",
+	"GET /rest/usermanagement/1/group/user/direct?groupname=crowd-administrators&start-index=0":        "CrowdAdministratorsMembers.json",
+	"GET /rest/usermanagement/1/group/user/direct?groupname=developers&start-index=0":                  "CrowdDevelopersMembers.json",
+	"GET /rest/usermanagement/1/group/user/direct":                                                     "CrowdEmptyUsers.json",
+	"POST /rest/usermanagement/1/group/user/direct?groupname=crowd-administrators":                     "201",
+	"DELETE /rest/usermanagement/1/group/user/direct?groupname=crowd-administrators&username=alice":    "204",
+	"GET /rest/usermanagement/1/group/child-group/direct?groupname=crowd-administrators&start-index=0": "CrowdAdministratorsChildGroups.json",
+	"GET /rest/usermanagement/1/group/child-group/direct":                                              "CrowdEmptyGroups.json",
+}
+
+// OpsgenieFixtures answer as an Opsgenie account with three users, one custom role, one team and one schedule,
+// and accept team membership changes.
+var OpsgenieFixtures = map[string]string{
+	"GET /v2/users?offset=0":           "OpsgenieUsers.json",
+	"GET /v2/users":                    "OpsgenieEmptyList.json",
+	"GET /v2/roles":                    "OpsgenieRoles.json",
+	"GET /v2/teams":                    "OpsgenieTeams.json",
+	"GET /v2/teams/t-1":                "OpsgenieTeam.json",
+	"POST /v2/teams/t-1/members":       "OpsgenieTeamResult.json",
+	"DELETE /v2/teams/t-1/members/u-1": "OpsgenieTeamResult.json",
+	"DELETE /v2/teams/t-1/members/u-3": "OpsgenieTeamResult.json",
+	"GET /v2/schedules":                "OpsgenieSchedules.json",
+	"GET /v2/schedules/s-1":            "OpsgenieSchedule.json",
+}
+
+// TrelloFixtures answer as the Trello Enterprise e-1 with three members, one workspace and one board, and
+// accept workspace membership changes and deactivations.
+var TrelloFixtures = map[string]string{
+	"GET /1/enterprises/e-1/members?startIndex=1":                         "TrelloMembers.json",
+	"GET /1/enterprises/e-1/members?startIndex=1&filter=licensed+eq+true": "TrelloLicensedMembers.json",
+	"GET /1/enterprises/e-1/members":                                      "EmptyList.json",
+	"PUT /1/enterprises/e-1/members/m-4/deactivated":                      "TrelloMemberResult.json",
+	"GET /1/enterprises/e-1/organizations":                                "TrelloWorkspaces.json",
+	"GET /1/organizations/w-1/memberships":                                "TrelloWorkspaceMemberships.json",
+	"PUT /1/organizations/w-1/members/m-2":                                "TrelloWorkspaceResult.json",
+	"PUT /1/organizations/w-1/members/m-4":                                "TrelloWorkspaceResult.json",
+	"DELETE /1/organizations/w-1/members/m-2":                             "TrelloWorkspaceResult.json",
+	"GET /1/organizations/w-1/boards":                                     "TrelloBoards.json",
+	"GET /1/boards/b-1/memberships":                                       "TrelloBoardMemberships.json",
+}
+
+// StatuspageFixtures answer as the Statuspage organization org-1 with three team members, of whom alice can
+// access the audience-specific page pg-1, which has one page access group and two page access users, and
+// accept page access group changes.
+var StatuspageFixtures = map[string]string{
+	"GET /v1/pages": "StatuspagePages.json",
+	"GET /v1/organizations/org-1/users?page=1":                               "StatuspageUsers.json",
+	"GET /v1/organizations/org-1/users":                                      "EmptyList.json",
+	"GET /v1/organizations/org-1/permissions/u-2":                            "StatuspagePermissionsAlice.json",
+	"GET /v1/organizations/org-1/permissions/u-3":                            "StatuspageNoPermissions.json",
+	"GET /v1/pages/pg-1/page_access_groups":                                  "StatuspageAccessGroups.json",
+	"GET /v1/pages/pg-1/page_access_groups/pag-1":                            "StatuspageAccessGroup.json",
+	"GET /v1/pages/pg-1/page_access_users?page=1":                            "StatuspageAccessUsers.json",
+	"GET /v1/pages/pg-1/page_access_users":                                   "EmptyList.json",
+	"PUT /v1/pages/pg-1/page_access_groups/pag-1/page_access_users":          "StatuspageAccessGroupResult.json",
+	"DELETE /v1/pages/pg-1/page_access_groups/pag-1/page_access_users/pau-2": "StatuspageAccessGroupResult.json",
+}
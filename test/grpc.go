@@ -0,0 +1,80 @@
+package test
+
+import (
+	"net"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// connectorClient is a types.ConnectorClient made of the clients of every connector service.
+type connectorClient struct {
+	v2.ResourceTypesServiceClient
+	v2.ResourcesServiceClient
+	v2.EntitlementsServiceClient
+	v2.GrantsServiceClient
+	v2.ConnectorServiceClient
+	v2.AssetServiceClient
+	v2.GrantManagerServiceClient
+	v2.ResourceManagerServiceClient
+	v2.AccountManagerServiceClient
+	v2.CredentialManagerServiceClient
+	v2.EventServiceClient
+	v2.TicketsServiceClient
+}
+
+// NewConnectorClient serves the connector over gRPC on a local port and returns a client for it, as the SDK
+// does when running a connector in a separate process. The server stops when the test ends.
+func NewConnectorClient(t testing.TB, srv types.ConnectorServer) types.ConnectorClient {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening for the connector server: %v", err)
+	}
+
+	server := grpc.NewServer()
+	v2.RegisterResourceTypesServiceServer(server, srv)
+	v2.RegisterResourcesServiceServer(server, srv)
+	v2.RegisterEntitlementsServiceServer(server, srv)
+	v2.RegisterGrantsServiceServer(server, srv)
+	v2.RegisterConnectorServiceServer(server, srv)
+	v2.RegisterAssetServiceServer(server, srv)
+	v2.RegisterGrantManagerServiceServer(server, srv)
+	v2.RegisterResourceManagerServiceServer(server, srv)
+	v2.RegisterAccountManagerServiceServer(server, srv)
+	v2.RegisterCredentialManagerServiceServer(server, srv)
+	v2.RegisterEventServiceServer(server, srv)
+	v2.RegisterTicketsServiceServer(server, srv)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("connecting to the connector server: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return &connectorClient{
+		ResourceTypesServiceClient:     v2.NewResourceTypesServiceClient(conn),
+		ResourcesServiceClient:         v2.NewResourcesServiceClient(conn),
+		EntitlementsServiceClient:      v2.NewEntitlementsServiceClient(conn),
+		GrantsServiceClient:            v2.NewGrantsServiceClient(conn),
+		ConnectorServiceClient:         v2.NewConnectorServiceClient(conn),
+		AssetServiceClient:             v2.NewAssetServiceClient(conn),
+		GrantManagerServiceClient:      v2.NewGrantManagerServiceClient(conn),
+		ResourceManagerServiceClient:   v2.NewResourceManagerServiceClient(conn),
+		AccountManagerServiceClient:    v2.NewAccountManagerServiceClient(conn),
+		CredentialManagerServiceClient: v2.NewCredentialManagerServiceClient(conn),
+		EventServiceClient:             v2.NewEventServiceClient(conn),
+		TicketsServiceClient:           v2.NewTicketsServiceClient(conn),
+	}
+}
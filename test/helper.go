@@ -1,7 +1,20 @@
 package test
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
 
 	"github.com/conductorone/baton-atlassian/pkg/client"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
@@ -12,6 +25,9 @@ var (
 	OrganizationID = "organizationTest"
 )
 
+// FixtureURL is the base URL of the clients of a FixtureRoundTripper, which answers every host alike.
+const FixtureURL = "https://atlassian.example.com"
+
 // Custom RoundTripper for testing.
 type TestRoundTripper struct {
 	response *http.Response
@@ -41,5 +57,229 @@ func NewTestClient(response *http.Response, err error) *client.AtlassianClient {
 	transport := &TestRoundTripper{response: response, err: err}
 	httpClient := &http.Client{Transport: transport}
 	baseHttpClient := uhttp.NewBaseHttpClient(httpClient)
-	return client.NewClient("", "", OrganizationID, "", baseHttpClient)
+	return client.NewClient("", "", "", client.Endpoints{}, OrganizationID, "", baseHttpClient)
+}
+
+// NewFixtureDataCenterClient returns a Data Center client authenticating with auth, sending its requests through
+// the round tripper without rate limiting them.
+func NewFixtureDataCenterClient(transport *FixtureRoundTripper, auth client.Authenticator) *client.DataCenterClient {
+	dataCenterClient := client.NewDataCenterClientWithAuth(FixtureURL, auth, transport.HTTPClient())
+	dataCenterClient.DisableRateLimit()
+	return dataCenterClient
+}
+
+// NewFixtureOpsgenieClient returns an Opsgenie client with the API key "key", sending its requests through the
+// round tripper without rate limiting them.
+func NewFixtureOpsgenieClient(transport *FixtureRoundTripper) *client.OpsgenieClient {
+	opsgenieClient := client.NewOpsgenieClientWithAuth(FixtureURL, client.NewGenieKeyAuth("key"), transport.HTTPClient())
+	opsgenieClient.DisableRateLimit()
+	return opsgenieClient
+}
+
+// NewFixtureTrelloClient returns a client of the Trello Enterprise e-1 with the API key "key" and token
+// "token", sending its requests through the round tripper without rate limiting them.
+func NewFixtureTrelloClient(transport *FixtureRoundTripper) *client.TrelloClient {
+	trelloClient := client.NewTrelloClientWithAuth(FixtureURL, "e-1", client.NewTrelloAuth("key", "token"), transport.HTTPClient())
+	trelloClient.DisableRateLimit()
+	return trelloClient
+}
+
+// NewFixtureStatuspageClient returns a client of the Statuspage organization org-1 with the API key "key",
+// sending its requests through the round tripper without rate limiting them.
+func NewFixtureStatuspageClient(transport *FixtureRoundTripper) *client.StatuspageClient {
+	statuspageClient := client.NewStatuspageClientWithAuth(FixtureURL, "org-1", client.NewStatuspageAuth("key"), transport.HTTPClient())
+	statuspageClient.DisableRateLimit()
+	return statuspageClient
+}
+
+// FixtureRoundTripper is a MockRoundTripper answering requests with the fixtures under test/mockResponses, and
+// recording the requests it answers.
+type FixtureRoundTripper struct {
+	MockRoundTripper
+
+	mtx      sync.Mutex
+	routes   map[string]string
+	requests []FixtureRequest
+}
+
+// FixtureRequest is a request answered by a FixtureRoundTripper.
+type FixtureRequest struct {
+	Method string
+	URL    *url.URL
+	Header http.Header
+	// Body is the body of the request without surrounding whitespace, such as the newline JSON encoders end
+	// with.
+	Body string
+}
+
+// NewFixtureRoundTripper returns a round tripper answering the requests matching a route with its fixture, and
+// every other request with a 404.
+//
+// Routes are a method and a path, optionally followed by query parameters the request must hold, such as
+// "GET /rest/api/2/user?username=alice". When several routes match, the one holding the most parameters wins.
+// Fixtures are a file name under test/mockResponses, optionally preceded by a status code, such as
+// "201 CreateTeam.json", or a status code alone for an empty answer.
+func NewFixtureRoundTripper(routes map[string]string) *FixtureRoundTripper {
+	f := &FixtureRoundTripper{routes: make(map[string]string, len(routes))}
+	for route, fixture := range routes {
+		f.routes[route] = fixture
+	}
+	f.SetRoundTrip(f.answer)
+
+	return f
+}
+
+// NewFixtureServer returns a test server answering like a round tripper from NewFixtureRoundTripper, which
+// is returned too. The server is closed when the test ends.
+func NewFixtureServer(t testing.TB, routes map[string]string) (*httptest.Server, *FixtureRoundTripper) {
+	t.Helper()
+
+	f := NewFixtureRoundTripper(routes)
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+
+	return server, f
+}
+
+// SetRoute answers the requests matching the route with the fixture from now on.
+func (f *FixtureRoundTripper) SetRoute(route, fixture string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.routes[route] = fixture
+}
+
+// Requests returns the requests answered so far that match the route, in the order they were sent.
+func (f *FixtureRoundTripper) Requests(route string) []FixtureRequest {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	var requests []FixtureRequest
+	for _, request := range f.requests {
+		if _, ok := matchRoute(route, request.Method, request.URL); ok {
+			requests = append(requests, request)
+		}
+	}
+
+	return requests
+}
+
+// ServeHTTP answers the request like RoundTrip, so that the round tripper can back a test server for clients
+// that are built with their own HTTP client, such as the ones of the public connector constructors.
+func (f *FixtureRoundTripper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	resp, err := f.answer(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer resp.Body.Close()
+
+	for key, values := range resp.Header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// HTTPClient returns an HTTP client sending its requests through the round tripper.
+func (f *FixtureRoundTripper) HTTPClient() *uhttp.BaseHttpClient {
+	return uhttp.NewBaseHttpClient(&http.Client{Transport: f})
+}
+
+func (f *FixtureRoundTripper) answer(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f.mtx.Lock()
+	f.requests = append(f.requests, FixtureRequest{
+		Method: req.Method,
+		URL:    req.URL,
+		Header: req.Header.Clone(),
+		Body:   strings.TrimSpace(string(body)),
+	})
+
+	fixture, best := "", -1
+	routes := make([]string, 0, len(f.routes))
+	for route := range f.routes {
+		routes = append(routes, route)
+	}
+	slices.Sort(routes)
+	for _, route := range routes {
+		if params, ok := matchRoute(route, req.Method, req.URL); ok && params > best {
+			fixture, best = f.routes[route], params
+		}
+	}
+	f.mtx.Unlock()
+
+	if best < 0 {
+		return fixtureResponse(req, http.StatusNotFound, nil), nil
+	}
+
+	status := http.StatusOK
+	file := fixture
+	if code, rest, _ := strings.Cut(fixture, " "); len(code) == 3 {
+		if parsed, err := strconv.Atoi(code); err == nil {
+			status, file = parsed, rest
+		}
+	}
+	if file == "" {
+		return fixtureResponse(req, status, nil), nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(mockResponsesDir(), file))
+	if err != nil {
+		return nil, fmt.Errorf("fixture for %s %s: %w", req.Method, req.URL, err)
+	}
+
+	return fixtureResponse(req, status, data), nil
+}
+
+// matchRoute returns whether the request matches the route, and how many query parameters of the route it
+// holds.
+func matchRoute(route, method string, u *url.URL) (int, bool) {
+	routeMethod, target, _ := strings.Cut(route, " ")
+	path, rawQuery, _ := strings.Cut(target, "?")
+	if routeMethod != method || path != u.Path {
+		return 0, false
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return 0, false
+	}
+	params := 0
+	for key, values := range query {
+		for _, value := range values {
+			if !slices.Contains(u.Query()[key], value) {
+				return 0, false
+			}
+			params++
+		}
+	}
+
+	return params, true
+}
+
+func fixtureResponse(req *http.Request, status int, body []byte) *http.Response {
+	resp := &http.Response{
+		StatusCode: status,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}
+	resp.Header.Set("Content-Type", "application/json")
+
+	return resp
+}
+
+// mockResponsesDir returns the directory of the fixtures, wherever the tests run from.
+func mockResponsesDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "mockResponses")
 }